@@ -0,0 +1,339 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// This file implements the shared ACME cache used by clustered
+// setups: with several replicas behind DNS round-robin the CA's
+// HTTP-01 validation request may reach a different replica than the
+// one whose ACME client started the order.  The cache shares the
+// challenge tokens - and the issued certificate bundles - between
+// all replicas via a directory on a shared filesystem (NFS and the
+// like) and/or Redis, so a single replica obtains each certificate
+// for the whole cluster instead of every replica burning the CA's
+// rate limits on its own.
+
+type (
+	// `TAcmeCacheConfig` describes the backends of the shared ACME
+	// cache; at least one of them must be configured.
+	TAcmeCacheConfig struct {
+		// Dir is a directory shared between all replicas (e.g. an
+		// NFS mount) holding the cached tokens and certificates.
+		Dir string `json:"dir,omitempty"`
+
+		// Redis optionally shares the cache via Redis instead of
+		// (or in addition to) the directory, see `TRedisConfig`.
+		Redis *TRedisConfig `json:"redis,omitempty"`
+
+		// TokenMinutes is how long a published challenge token
+		// stays answerable (default 10).
+		TokenMinutes int `json:"tokenMinutes,omitempty"`
+	}
+
+	// `tAcmeCert` is the stored form of one certificate bundle.
+	tAcmeCert struct {
+		CertPEM string `json:"certPem"`
+		KeyPEM  string `json:"keyPem"`
+	}
+
+	// `tAcmeToken` is the stored form of one HTTP-01 challenge.
+	tAcmeToken struct {
+		Auth  string    `json:"auth"`
+		Until time.Time `json:"until"`
+	}
+
+	// `tAcmeCache` shares ACME challenge tokens and issued
+	// certificates between all replicas of a cluster.
+	tAcmeCache struct {
+		mtx   sync.Mutex    // guards the store's load-modify-save
+		store TStateStore   // shared directory backend (or nil)
+		redis *tRedisClient // shared Redis backend (or nil)
+		ttl   time.Duration // challenge token lifetime
+	}
+)
+
+// state-store buckets resp. Redis key prefixes of the cache:
+const (
+	acmeCertBucket  = `acme-certs`
+	acmeTokenBucket = `acme-tokens`
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newAcmeCache()` creates the shared ACME cache configured by
+// `aConfig`, filling in the usual defaults.
+func newAcmeCache(aConfig *TAcmeCacheConfig) (*tAcmeCache, error) {
+	ttl := time.Duration(aConfig.TokenMinutes) * time.Minute
+	if 0 >= ttl {
+		ttl = 10 * time.Minute
+	}
+	cache := &tAcmeCache{ttl: ttl}
+	if "" != aConfig.Dir {
+		store, err := NewFileStore(aConfig.Dir)
+		if nil != err {
+			return nil, err
+		}
+		cache.store = store
+	}
+	if (nil != aConfig.Redis) && ("" != aConfig.Redis.Addr) {
+		cache.redis = newRedisClient(aConfig.Redis)
+	}
+	if (nil == cache.store) && (nil == cache.redis) {
+		return nil, fmt.Errorf("ACME cache needs a dir or redis backend")
+	}
+
+	return cache, nil
+} // newAcmeCache()
+
+// --------------------------------------------------------------------------
+// tAcmeCache methods:
+
+// `getCert()` returns the cached certificate bundle of `aHost`.
+func (ac *tAcmeCache) getCert(aHost string) (*tAcmeCert, bool) {
+	if nil != ac.redis {
+		if value, ok, err := ac.redis.get(`acme:cert:` + aHost); (nil == err) && ok {
+			var bundle tAcmeCert
+			if nil == json.Unmarshal([]byte(value), &bundle) {
+				return &bundle, true
+			}
+		}
+	}
+	if nil != ac.store {
+		if entries, err := ac.store.Load(acmeCertBucket); nil == err {
+			if data, ok := entries[aHost]; ok {
+				var bundle tAcmeCert
+				if nil == json.Unmarshal(data, &bundle) {
+					return &bundle, true
+				}
+			}
+		}
+	}
+
+	return nil, false
+} // getCert()
+
+// `getToken()` looks the HTTP-01 challenge `aToken` up in the shared
+// backends, returning its key authorisation.
+func (ac *tAcmeCache) getToken(aToken string) (string, bool) {
+	if nil != ac.redis {
+		if auth, ok, err := ac.redis.get(`acme:token:` + aToken); (nil == err) && ok {
+			return auth, true
+		}
+	}
+	if nil != ac.store {
+		if entries, err := ac.store.Load(acmeTokenBucket); nil == err {
+			var entry tAcmeToken
+			if data, ok := entries[aToken]; ok &&
+				(nil == json.Unmarshal(data, &entry)) &&
+				time.Now().Before(entry.Until) {
+				return entry.Auth, true
+			}
+		}
+	}
+
+	return ``, false
+} // getToken()
+
+// `putCert()` stores the certificate bundle of `aHost` in all
+// configured backends; certificates don't expire from the cache,
+// they're replaced by their renewals.
+//
+// Returns:
+//   - `error`: The last backend failure (`nil` if all succeeded).
+func (ac *tAcmeCache) putCert(aHost string, aBundle *tAcmeCert) error {
+	data, err := json.Marshal(aBundle)
+	if nil != err {
+		return err
+	}
+	var lastErr error
+	if nil != ac.redis {
+		if err = ac.redis.set(`acme:cert:`+aHost, string(data), 0); nil != err {
+			lastErr = err
+		}
+	}
+	if nil != ac.store {
+		ac.mtx.Lock()
+		entries, err := ac.store.Load(acmeCertBucket)
+		if nil == err {
+			entries[aHost] = data
+			err = ac.store.Save(acmeCertBucket, entries)
+		}
+		ac.mtx.Unlock()
+		if nil != err {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+} // putCert()
+
+// `putToken()` publishes the HTTP-01 challenge `aToken` with its key
+// authorisation `aAuth` to all configured backends, pruning expired
+// tokens as a side effect.
+//
+// Returns:
+//   - `error`: The last backend failure (`nil` if all succeeded).
+func (ac *tAcmeCache) putToken(aToken, aAuth string) error {
+	var lastErr error
+	if nil != ac.redis {
+		if err := ac.redis.set(`acme:token:`+aToken, aAuth, ac.ttl); nil != err {
+			lastErr = err
+		}
+	}
+	if nil != ac.store {
+		ac.mtx.Lock()
+		entries, err := ac.store.Load(acmeTokenBucket)
+		if nil == err {
+			now := time.Now()
+			for token, data := range entries {
+				var entry tAcmeToken
+				if (nil != json.Unmarshal(data, &entry)) ||
+					now.After(entry.Until) {
+					delete(entries, token)
+				}
+			}
+			data, _ := json.Marshal(tAcmeToken{
+				Auth: aAuth, Until: now.Add(ac.ttl)})
+			entries[aToken] = data
+			err = ac.store.Save(acmeTokenBucket, entries)
+		}
+		ac.mtx.Unlock()
+		if nil != err {
+			lastErr = err
+		}
+	}
+
+	return lastErr
+} // putToken()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `acmeCert()` handles `GET|POST /api/acme/cert` fetching resp.
+// publishing a host's certificate bundle via the shared cache, so
+// the replica that obtained a certificate can distribute it to its
+// peers (e.g. from a certbot deploy hook).
+//
+// `GET` takes the hostname as `host` query parameter; the `POST`
+// JSON body carries `host`, `certPem`, and `keyPem`.
+func (ad *TAdmin) acmeCert(aWriter http.ResponseWriter, aRequest *http.Request) {
+	cache := ad.handler.acmeCache
+	if nil == cache {
+		http.Error(aWriter, `no ACME cache configured`,
+			http.StatusNotImplemented)
+		return
+	}
+
+	switch aRequest.Method {
+	case http.MethodGet:
+		host := aRequest.FormValue(`host`)
+		if "" == host {
+			http.Error(aWriter, `missing hostname`, http.StatusBadRequest)
+			return
+		}
+		bundle, ok := cache.getCert(normalizeHostname(host))
+		if !ok {
+			http.Error(aWriter, `no cached certificate`,
+				http.StatusNotFound)
+			return
+		}
+		sendJSON(aWriter, bundle)
+
+	case http.MethodPost:
+		var body struct {
+			Host    string `json:"host"`
+			CertPEM string `json:"certPem"`
+			KeyPEM  string `json:"keyPem"`
+		}
+		if err := json.NewDecoder(aRequest.Body).Decode(&body); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if ("" == body.Host) || ("" == body.CertPEM) || ("" == body.KeyPEM) {
+			http.Error(aWriter, `host, certPem, and keyPem required`,
+				http.StatusBadRequest)
+			return
+		}
+		// reject bundles that wouldn't load as a key pair anyway:
+		if _, err := tls.X509KeyPair(
+			[]byte(body.CertPEM), []byte(body.KeyPEM)); nil != err {
+			http.Error(aWriter, err.Error(),
+				http.StatusUnprocessableEntity)
+			return
+		}
+		host := normalizeHostname(body.Host)
+		err := cache.putCert(host, &tAcmeCert{
+			CertPEM: body.CertPEM, KeyPEM: body.KeyPEM})
+		ad.audit.Log(aRequest.RemoteAddr, `acme-cert`, `published `+host)
+		if nil != err {
+			ad.logger.Error(`admin`, `acme cert: `+err.Error())
+			http.Error(aWriter, err.Error(), http.StatusBadGateway)
+			return
+		}
+		sendJSON(aWriter, map[string]string{
+			`host`: host, `status`: `ok`})
+
+	default:
+		methodNotAllowed(aWriter, http.MethodGet, http.MethodPost)
+	}
+} // acmeCert()
+
+// `acmeHTTP()` handles `POST /api/acme/http` publishing an HTTP-01
+// challenge token to the shared cache, so any replica can answer
+// the CA's validation request; this lets an external ACME client
+// (e.g. certbot's manual auth hooks) run next to a single replica
+// only.
+//
+// The JSON body carries the challenge `token` and its `keyAuth`.
+func (ad *TAdmin) acmeHTTP(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodPost != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodPost)
+		return
+	}
+	cache := ad.handler.acmeCache
+	if nil == cache {
+		http.Error(aWriter, `no ACME cache configured`,
+			http.StatusNotImplemented)
+		return
+	}
+
+	var body struct {
+		Token   string `json:"token"`
+		KeyAuth string `json:"keyAuth"`
+	}
+	if err := json.NewDecoder(aRequest.Body).Decode(&body); nil != err {
+		http.Error(aWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ("" == body.Token) || ("" == body.KeyAuth) {
+		http.Error(aWriter, `token and keyAuth required`,
+			http.StatusBadRequest)
+		return
+	}
+
+	err := cache.putToken(body.Token, body.KeyAuth)
+	ad.audit.Log(aRequest.RemoteAddr, `acme-http`, `token `+body.Token)
+	if nil != err {
+		ad.logger.Error(`admin`, `acme http: `+err.Error())
+		http.Error(aWriter, err.Error(), http.StatusBadGateway)
+		return
+	}
+	sendJSON(aWriter, map[string]string{`status`: `ok`})
+} // acmeHTTP()
+
+/* _EoF_ */
@@ -0,0 +1,117 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAcmeCacheDir(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := newAcmeCache(&TAcmeCacheConfig{Dir: dir})
+	if nil != err {
+		t.Fatalf(`newAcmeCache() failed: %v`, err)
+	}
+
+	if err = cache.putToken(`tok-1`, `tok-1.auth`); nil != err {
+		t.Fatalf(`putToken() failed: %v`, err)
+	}
+	if auth, ok := cache.getToken(`tok-1`); (!ok) || (`tok-1.auth` != auth) {
+		t.Errorf(`getToken() = (%q, %v), want (tok-1.auth, true)`, auth, ok)
+	}
+	if _, ok := cache.getToken(`unknown`); ok {
+		t.Error(`getToken() found an unknown token`)
+	}
+
+	bundle := &tAcmeCert{CertPEM: `cert-pem`, KeyPEM: `key-pem`}
+	if err = cache.putCert(`www.mwat.de`, bundle); nil != err {
+		t.Fatalf(`putCert() failed: %v`, err)
+	}
+
+	// a second replica sharing the directory sees both entries:
+	replica, err := newAcmeCache(&TAcmeCacheConfig{Dir: dir})
+	if nil != err {
+		t.Fatalf(`newAcmeCache() failed: %v`, err)
+	}
+	if auth, ok := replica.getToken(`tok-1`); (!ok) || (`tok-1.auth` != auth) {
+		t.Errorf(`replica getToken() = (%q, %v)`, auth, ok)
+	}
+	if shared, ok := replica.getCert(`www.mwat.de`); (!ok) ||
+		(bundle.CertPEM != shared.CertPEM) || (bundle.KeyPEM != shared.KeyPEM) {
+		t.Errorf(`replica getCert() = (%v, %v)`, shared, ok)
+	}
+
+	if _, err = newAcmeCache(&TAcmeCacheConfig{}); nil == err {
+		t.Error(`newAcmeCache() accepted an empty configuration`)
+	}
+} // TestAcmeCacheDir()
+
+func TestAcmeCacheRedis(t *testing.T) {
+	addr := startFakeRedis(t)
+	conf := &TAcmeCacheConfig{Redis: &TRedisConfig{Addr: addr}}
+	cache, err := newAcmeCache(conf)
+	if nil != err {
+		t.Fatalf(`newAcmeCache() failed: %v`, err)
+	}
+
+	if err = cache.putToken(`tok-2`, `tok-2.auth`); nil != err {
+		t.Fatalf(`putToken() failed: %v`, err)
+	}
+	if err = cache.putCert(`www.mwat.de`,
+		&tAcmeCert{CertPEM: `cert-pem`, KeyPEM: `key-pem`}); nil != err {
+		t.Fatalf(`putCert() failed: %v`, err)
+	}
+
+	// another replica sharing the Redis server sees both entries:
+	replica, _ := newAcmeCache(conf)
+	if auth, ok := replica.getToken(`tok-2`); (!ok) || (`tok-2.auth` != auth) {
+		t.Errorf(`getToken() = (%q, %v), want (tok-2.auth, true)`, auth, ok)
+	}
+	if bundle, ok := replica.getCert(`www.mwat.de`); (!ok) ||
+		(`cert-pem` != bundle.CertPEM) {
+		t.Errorf(`getCert() = (%v, %v)`, bundle, ok)
+	}
+	if _, ok := replica.getToken(`unknown`); ok {
+		t.Error(`getToken() found an unknown token`)
+	}
+} // TestAcmeCacheRedis()
+
+func TestAcmeChallengeFromCache(t *testing.T) {
+	ph, err := NewProxyHandler(WithConfig(&TConfig{
+		AcmeCache: &TAcmeCacheConfig{Dir: t.TempDir()},
+	}))
+	if nil != err {
+		t.Fatalf(`NewProxyHandler() failed: %v`, err)
+	}
+	if err = ph.acmeCache.putToken(`tok-3`, `tok-3.auth`); nil != err {
+		t.Fatalf(`putToken() failed: %v`, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet,
+		`http://www.mwat.de/.well-known/acme-challenge/tok-3`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if (http.StatusOK != rec.Code) || (`tok-3.auth` != rec.Body.String()) {
+		t.Errorf(`challenge answered (%d, %q), want (200, tok-3.auth)`,
+			rec.Code, rec.Body.String())
+	}
+
+	// an unknown token must not fall through to the routing:
+	req = httptest.NewRequest(http.MethodGet,
+		`http://www.mwat.de/.well-known/acme-challenge/unknown`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusNotFound != rec.Code {
+		t.Errorf(`unknown token status = %d, want 404`, rec.Code)
+	}
+} // TestAcmeChallengeFromCache()
+
+/* _EoF_ */
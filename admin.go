@@ -0,0 +1,488 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+type (
+	// `TAdminConfig` holds the setup of the administrative listener.
+	TAdminConfig struct {
+		// Addr is the TCP address the admin API listens on
+		// (e.g. `127.0.0.1:8404`); ignored if `Socket` is set.
+		Addr string `json:"addr,omitempty"`
+
+		// Socket is a Unix socket path for the admin API,
+		// taking precedence over `Addr`.
+		Socket string `json:"socket,omitempty"`
+
+		// Token is the bearer token required for all API calls.
+		Token string `json:"token"`
+
+		// ReadToken is an optional second bearer token restricted
+		// to the read-only (`GET`) endpoints, for monitoring glue
+		// that must not control live traffic.
+		ReadToken string `json:"readToken,omitempty"`
+
+		// CertFile/KeyFile hold the TLS certificate of the admin
+		// listener; required when `ClientCA` is set.
+		CertFile string `json:"certFile,omitempty"`
+		KeyFile  string `json:"keyFile,omitempty"`
+
+		// ClientCA is a PEM file of CA certificates; when set, the
+		// TCP admin listener serves TLS and additionally requires
+		// a client certificate signed by one of them (mTLS).
+		ClientCA string `json:"clientCA,omitempty"`
+	}
+
+	// `TAdmin` serves the administrative HTTP API controlling the
+	// proxy at runtime: listing and changing routes, toggling
+	// maintenance mode, and triggering configuration reloads.
+	TAdmin struct {
+		audit     *TAuditLog
+		certs     *TCertWatcher
+		cluster   *TClusterSync
+		confName  string
+		dns       TDNSProvider
+		failover  *TFailoverMonitor
+		handler   *TProxyHandler
+		health    *THealthChecker
+		logger    *TLogger
+		mux       *http.ServeMux
+		stream    *TEventStream
+		token     string
+		readToken string
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewAdmin()` creates the administrative API for `aHandler`.
+//
+// Parameters:
+//   - `aConfig`: The admin listener configuration.
+//   - `aConfName`: The configuration file re-read on reload requests.
+//   - `aHandler`: The proxy handler to administer.
+//   - `aLogger`: The error logger for administrative problems.
+//
+// Returns:
+//   - `*TAdmin`: The new admin API instance.
+//   - `error`: An error if no access token is configured.
+func NewAdmin(aConfig *TAdminConfig, aConfName string, aHandler *TProxyHandler, aLogger *TLogger) (*TAdmin, error) {
+	if "" == aConfig.Token {
+		return nil, fmt.Errorf("admin API requires a token")
+	}
+	admin := &TAdmin{
+		confName:  aConfName,
+		handler:   aHandler,
+		logger:    aLogger,
+		mux:       http.NewServeMux(),
+		stream:    NewEventStream(),
+		token:     aConfig.Token,
+		readToken: aConfig.ReadToken,
+	}
+	admin.mux.HandleFunc(`/api/routes`, admin.routes)
+	admin.mux.HandleFunc(`/api/routes/`, admin.route)
+	admin.mux.HandleFunc(`/api/maintenance`, admin.maintenance)
+	admin.mux.HandleFunc(`/api/reload`, admin.reload)
+	admin.mux.HandleFunc(`/api/acme/cert`, admin.acmeCert)
+	admin.mux.HandleFunc(`/api/acme/dns`, admin.acmeDNS)
+	admin.mux.HandleFunc(`/api/acme/http`, admin.acmeHTTP)
+	admin.mux.HandleFunc(`/api/certs`, admin.certStates)
+	admin.mux.HandleFunc(`/api/cluster`, admin.clusterStatus)
+	admin.mux.HandleFunc(`/api/failover`, admin.failoverState)
+	admin.mux.HandleFunc(`/api/purge`, admin.purge)
+	admin.mux.HandleFunc(`/api/slo`, admin.slo)
+	admin.mux.HandleFunc(`/api/slots/`, admin.slot)
+	admin.mux.HandleFunc(`/api/health/backends`, admin.healthBackends)
+	admin.mux.HandleFunc(`/api/routetable`, admin.routetable)
+	admin.mux.HandleFunc(`/api/events`, admin.events)
+	admin.mux.HandleFunc(`/status`, admin.status)
+	admin.mux.HandleFunc(`/healthz`, admin.healthz)
+	admin.mux.HandleFunc(`/readyz`, admin.readyz)
+	admin.registerDiagnostics()
+
+	return admin, nil
+} // NewAdmin()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `ListenAndServe()` runs the admin API on the TCP address or Unix
+// socket given in `aConfig`, blocking until the listener fails.
+//
+// Parameters:
+//   - `aConfig`: The admin listener configuration.
+//
+// Returns:
+//   - `error`: The listener's terminal error.
+func (ad *TAdmin) ListenAndServe(aConfig *TAdminConfig) error {
+	var (
+		listener net.Listener
+		err      error
+	)
+	if "" != aConfig.Socket {
+		_ = os.Remove(aConfig.Socket)
+		if listener, err = net.Listen(`unix`, aConfig.Socket); nil == err {
+			err = os.Chmod(aConfig.Socket, 0660)
+		}
+	} else {
+		listener, err = net.Listen(`tcp`, aConfig.Addr)
+		if (nil == err) && ("" != aConfig.ClientCA) {
+			var tlsConf *tls.Config
+			if tlsConf, err = adminTLSConfig(aConfig); nil == err {
+				listener = tls.NewListener(listener, tlsConf)
+			}
+		}
+	}
+	if nil != err {
+		return fmt.Errorf("admin listener: %w", err)
+	}
+
+	return http.Serve(listener, ad)
+} // ListenAndServe()
+
+// `ServeHTTP()` checks the caller's bearer token and role, then
+// dispatches the request to the respective API endpoint.
+//
+// The primary token grants full access; the optional read-only
+// token is limited to `GET`/`HEAD` requests, so monitoring callers
+// can't mutate live traffic.
+//
+// Parameters:
+//   - `aWriter`: Used to send the response to the client.
+//   - `aRequest`: The incoming API request.
+func (ad *TAdmin) ServeHTTP(aWriter http.ResponseWriter, aRequest *http.Request) {
+	auth := aRequest.Header.Get(`Authorization`)
+	token, ok := strings.CutPrefix(auth, `Bearer `)
+	if !ok {
+		http.Error(aWriter, `unauthorised`, http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case 1 == subtle.ConstantTimeCompare([]byte(token), []byte(ad.token)):
+		// full access
+
+	case ("" != ad.readToken) && (1 == subtle.ConstantTimeCompare(
+		[]byte(token), []byte(ad.readToken))):
+		if (http.MethodGet != aRequest.Method) &&
+			(http.MethodHead != aRequest.Method) {
+			http.Error(aWriter, `read-only token`, http.StatusForbidden)
+			return
+		}
+
+	default:
+		http.Error(aWriter, `unauthorised`, http.StatusUnauthorized)
+		return
+	}
+
+	ad.mux.ServeHTTP(aWriter, aRequest)
+} // ServeHTTP()
+
+// `Events()` returns the admin API's live event broadcaster, so
+// other subsystems (e.g. the health checker) can publish into the
+// `/api/events` stream.
+func (ad *TAdmin) Events() *TEventStream {
+	return ad.stream
+} // Events()
+
+// `events()` handles `GET /api/events` streaming proxy events as
+// Server-Sent Events until the client disconnects.
+func (ad *TAdmin) events(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	ad.stream.ServeHTTP(aWriter, aRequest)
+} // events()
+
+// `SetHealth()` attaches the backend health checker whose state the
+// readiness endpoint reports.
+//
+// Parameters:
+//   - `aChecker`: The running health checker instance.
+func (ad *TAdmin) SetHealth(aChecker *THealthChecker) {
+	ad.health = aChecker
+} // SetHealth()
+
+// `healthz()` handles `GET /healthz` reporting plain liveness.
+func (ad *TAdmin) healthz(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	sendJSON(aWriter, map[string]string{`status`: `ok`})
+} // healthz()
+
+// `readyz()` handles `GET /readyz` reporting whether the proxy has a
+// configuration loaded and enough healthy backends to serve traffic.
+func (ad *TAdmin) readyz(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	ready := (0 < len(ad.handler.Routes())) &&
+		!ad.handler.InMaintenance()
+	if ready && (nil != ad.health) {
+		ready = ad.health.Ready()
+	}
+	if !ready {
+		aWriter.WriteHeader(http.StatusServiceUnavailable)
+	}
+	sendJSON(aWriter, map[string]bool{`ready`: ready})
+} // readyz()
+
+// `healthBackends()` handles `GET /api/health/backends` exporting
+// the full health state of every upstream (last check, latency,
+// consecutive failures, ejection) for external monitoring.
+func (ad *TAdmin) healthBackends(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	if nil == ad.health {
+		sendJSON(aWriter, map[string]THealthStatus{})
+		return
+	}
+	sendJSON(aWriter, ad.health.Snapshot())
+} // healthBackends()
+
+// `maintenance()` handles `POST /api/maintenance?on=<bool>`.
+func (ad *TAdmin) maintenance(aWriter http.ResponseWriter, aRequest *http.Request) {
+	switch aRequest.Method {
+	case http.MethodGet:
+		sendJSON(aWriter, map[string]bool{
+			`maintenance`: ad.handler.InMaintenance()})
+
+	case http.MethodPost:
+		on := `true` == aRequest.FormValue(`on`)
+		ad.handler.SetMaintenance(on)
+		ad.audit.Log(aRequest.RemoteAddr, `maintenance`,
+			fmt.Sprintf("set to %v", on))
+		ad.stream.Publish(`maintenance`, fmt.Sprintf("active=%v", on))
+		sendJSON(aWriter, map[string]bool{`maintenance`: on})
+
+	default:
+		methodNotAllowed(aWriter, http.MethodGet, http.MethodPost)
+	}
+} // maintenance()
+
+// `purge()` handles `POST /api/purge?host=&prefix=&key=` dropping
+// cached responses by host, URL path prefix, or surrogate key.
+func (ad *TAdmin) purge(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodPost != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodPost)
+		return
+	}
+
+	host := aRequest.FormValue(`host`)
+	prefix := aRequest.FormValue(`prefix`)
+	key := aRequest.FormValue(`key`)
+	purged := ad.handler.PurgeCache(host, prefix, key)
+	ad.audit.Log(aRequest.RemoteAddr, `cache-purge`, fmt.Sprintf(
+		"host=%q prefix=%q key=%q: %d entries", host, prefix, key, purged))
+	sendJSON(aWriter, map[string]int{`purged`: purged})
+} // purge()
+
+// `reload()` handles `POST /api/reload` re-reading the configuration
+// file and applying the new routes without a restart.
+func (ad *TAdmin) reload(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodPost != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodPost)
+		return
+	}
+
+	conf, err := LoadConfig(ad.confName)
+	if nil == err {
+		err = ad.handler.Reload(conf)
+	}
+	if nil != err {
+		ad.logger.Error(``, `config reload failed: `+err.Error())
+		ad.audit.Log(aRequest.RemoteAddr, `reload`,
+			`failed: `+err.Error())
+		ad.stream.Publish(`reload`, `failed: `+err.Error())
+		http.Error(aWriter, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	ad.audit.Log(aRequest.RemoteAddr, `reload`,
+		fmt.Sprintf("applied %q with %d hosts", ad.confName, len(conf.Hosts)))
+	ad.stream.Publish(`reload`,
+		fmt.Sprintf("%d hosts applied", len(conf.Hosts)))
+	sendJSON(aWriter, map[string]int{`hosts`: len(conf.Hosts)})
+} // reload()
+
+// `route()` handles `PUT|DELETE /api/routes/<hostname>`.
+func (ad *TAdmin) route(aWriter http.ResponseWriter, aRequest *http.Request) {
+	hostname := strings.TrimPrefix(aRequest.URL.Path, `/api/routes/`)
+	if "" == hostname {
+		http.Error(aWriter, `missing hostname`, http.StatusBadRequest)
+		return
+	}
+
+	switch aRequest.Method {
+	case http.MethodPut:
+		var body struct {
+			Target string `json:"target"`
+		}
+		if err := json.NewDecoder(aRequest.Body).Decode(&body); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ad.handler.AddRoute(hostname, body.Target); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		ad.audit.Log(aRequest.RemoteAddr, `route-add`,
+			hostname+` -> `+body.Target)
+		ad.stream.Publish(`route-add`, hostname+` -> `+body.Target)
+		sendJSON(aWriter, map[string]string{hostname: body.Target})
+
+	case http.MethodDelete:
+		ad.handler.RemoveRoute(hostname)
+		ad.audit.Log(aRequest.RemoteAddr, `route-delete`, hostname)
+		ad.stream.Publish(`route-delete`, hostname)
+		aWriter.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(aWriter, http.MethodPut, http.MethodDelete)
+	}
+} // route()
+
+// `slot()` handles `GET|POST /api/slots/<hostname>` reading or
+// atomically switching the host's active deployment slot for
+// blue/green cutovers.
+func (ad *TAdmin) slot(aWriter http.ResponseWriter, aRequest *http.Request) {
+	hostname := strings.TrimPrefix(aRequest.URL.Path, `/api/slots/`)
+	if "" == hostname {
+		http.Error(aWriter, `missing hostname`, http.StatusBadRequest)
+		return
+	}
+
+	switch aRequest.Method {
+	case http.MethodGet:
+		slots, active, ok := ad.handler.hostSlots(hostname)
+		if !ok {
+			http.Error(aWriter, `unknown host`, http.StatusNotFound)
+			return
+		}
+		sendJSON(aWriter, map[string]any{
+			`active`: active, `slots`: slots})
+
+	case http.MethodPost:
+		slot := aRequest.FormValue(`slot`)
+		if err := ad.handler.SwitchSlot(hostname, slot); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		ad.audit.Log(aRequest.RemoteAddr, `slot-switch`,
+			hostname+` -> `+slot)
+		ad.stream.Publish(`slot-switch`, hostname+` -> `+slot)
+		sendJSON(aWriter, map[string]string{hostname: slot})
+
+	default:
+		methodNotAllowed(aWriter, http.MethodGet, http.MethodPost)
+	}
+} // slot()
+
+// `routetable()` handles `GET|PUT /api/routetable` exporting the
+// active routing table as JSON resp. validating and atomically
+// applying a new one, so external orchestration tools can drive the
+// proxy.
+func (ad *TAdmin) routetable(aWriter http.ResponseWriter, aRequest *http.Request) {
+	switch aRequest.Method {
+	case http.MethodGet:
+		sendJSON(aWriter, ad.handler.ExportHosts())
+
+	case http.MethodPut:
+		hosts := make(map[string]*THostConfig)
+		if err := json.NewDecoder(aRequest.Body).Decode(&hosts); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ad.handler.ImportHosts(hosts); nil != err {
+			http.Error(aWriter, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		ad.audit.Log(aRequest.RemoteAddr, `routetable-import`,
+			fmt.Sprintf("%d hosts applied", len(hosts)))
+		ad.stream.Publish(`routetable-import`,
+			fmt.Sprintf("%d hosts applied", len(hosts)))
+		sendJSON(aWriter, map[string]int{`hosts`: len(hosts)})
+
+	default:
+		methodNotAllowed(aWriter, http.MethodGet, http.MethodPut)
+	}
+} // routetable()
+
+// `routes()` handles `GET /api/routes` listing all configured routes.
+func (ad *TAdmin) routes(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	sendJSON(aWriter, ad.handler.Routes())
+} // routes()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `adminTLSConfig()` builds the mTLS setup of the admin listener:
+// its own certificate plus the CA pool a caller's client certificate
+// must be signed by.
+func adminTLSConfig(aConfig *TAdminConfig) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(aConfig.CertFile, aConfig.KeyFile)
+	if nil != err {
+		return nil, fmt.Errorf("admin TLS certificate: %w", err)
+	}
+	pemData, err := os.ReadFile(aConfig.ClientCA)
+	if nil != err {
+		return nil, fmt.Errorf("admin client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("admin client CA: no certificates in %q",
+			aConfig.ClientCA)
+	}
+
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+	}, nil
+} // adminTLSConfig()
+
+// `methodNotAllowed()` rejects a request with a proper `Allow` header.
+func methodNotAllowed(aWriter http.ResponseWriter, aAllowed ...string) {
+	aWriter.Header().Set(`Allow`, strings.Join(aAllowed, `, `))
+	http.Error(aWriter, `method not allowed`, http.StatusMethodNotAllowed)
+} // methodNotAllowed()
+
+// `sendJSON()` writes `aData` as a JSON response body.
+func sendJSON(aWriter http.ResponseWriter, aData any) {
+	aWriter.Header().Set(`Content-Type`, `application/json`)
+	_ = json.NewEncoder(aWriter).Encode(aData)
+} // sendJSON()
+
+/* _EoF_ */
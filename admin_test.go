@@ -0,0 +1,54 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAdminAuthorization(t *testing.T) {
+	ph, _ := NewProxyHandler()
+	admin, err := NewAdmin(&TAdminConfig{
+		Token:     `secret-rw`,
+		ReadToken: `secret-ro`,
+	}, `reprox.json`, ph, &TLogger{})
+	if nil != err {
+		t.Fatalf(`NewAdmin() error = %v`, err)
+	}
+
+	call := func(aMethod, aPath, aToken string) int {
+		req := httptest.NewRequest(aMethod, `http://admin`+aPath, nil)
+		if "" != aToken {
+			req.Header.Set(`Authorization`, `Bearer `+aToken)
+		}
+		rec := httptest.NewRecorder()
+		admin.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if status := call(http.MethodGet, `/api/routes`, ``); http.StatusUnauthorized != status {
+		t.Errorf(`missing token status = %d, want 401`, status)
+	}
+	if status := call(http.MethodGet, `/api/routes`, `wrong`); http.StatusUnauthorized != status {
+		t.Errorf(`wrong token status = %d, want 401`, status)
+	}
+	if status := call(http.MethodGet, `/api/routes`, `secret-ro`); http.StatusOK != status {
+		t.Errorf(`read token GET status = %d, want 200`, status)
+	}
+	if status := call(http.MethodPost, `/api/maintenance`, `secret-ro`); http.StatusForbidden != status {
+		t.Errorf(`read token POST status = %d, want 403`, status)
+	}
+	if status := call(http.MethodPost, `/api/maintenance`, `secret-rw`); http.StatusOK != status {
+		t.Errorf(`full token POST status = %d, want 200`, status)
+	}
+} // TestAdminAuthorization()
+
+/* _EoF_ */
@@ -0,0 +1,196 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+type (
+	// `TAlertConfig` holds the latency budget monitoring settings.
+	TAlertConfig struct {
+		// P95Millis alerts when a host's p95 latency exceeds this
+		// many milliseconds; zero disables the latency check.
+		P95Millis int64 `json:"p95Millis"`
+
+		// ErrorPercent alerts when a host's 5xx rate exceeds this
+		// percentage; zero disables the error rate check.
+		ErrorPercent float64 `json:"errorPercent"`
+
+		// WindowSec is the sliding window size (default 300).
+		WindowSec int `json:"windowSec"`
+
+		// CooldownSec is the minimum pause between alerts for the
+		// same host (default 600).
+		CooldownSec int `json:"cooldownSec"`
+
+		// WebhookURL optionally receives a JSON POST per alert;
+		// without it alerts only go to the error log.
+		WebhookURL string `json:"webhookURL,omitempty"`
+	}
+
+	// `tAlertSample` is one measured request of the sliding window.
+	tAlertSample struct {
+		when    time.Time
+		latency time.Duration
+		failed  bool
+	}
+
+	// `TAlertMonitor` watches per-host latency percentiles and error
+	// rates over a sliding window, firing a webhook (or error log
+	// entry) when a configured budget is exceeded.
+	TAlertMonitor struct {
+		mtx       sync.Mutex
+		client    *http.Client
+		conf      *TAlertConfig
+		cooldown  time.Duration
+		lastAlert map[string]time.Time
+		lastEval  time.Time
+		logger    *TLogger
+		samples   map[string][]tAlertSample
+		window    time.Duration
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newAlertMonitor()` creates the latency budget monitor described
+// by `aConfig`.
+func newAlertMonitor(aConfig *TAlertConfig, aLogger *TLogger) *TAlertMonitor {
+	window := time.Duration(aConfig.WindowSec) * time.Second
+	if 0 >= window {
+		window = 5 * time.Minute
+	}
+	cooldown := time.Duration(aConfig.CooldownSec) * time.Second
+	if 0 >= cooldown {
+		cooldown = 10 * time.Minute
+	}
+
+	return &TAlertMonitor{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		conf:      aConfig,
+		cooldown:  cooldown,
+		lastAlert: make(map[string]time.Time),
+		logger:    aLogger,
+		samples:   make(map[string][]tAlertSample),
+		window:    window,
+	}
+} // newAlertMonitor()
+
+// --------------------------------------------------------------------------
+// TAlertMonitor methods:
+
+// `evaluate()` checks all hosts' windows against the configured
+// budgets; the caller must hold `am.mtx`.
+func (am *TAlertMonitor) evaluate(aNow time.Time) {
+	cutoff := aNow.Add(-am.window)
+	for hostname, samples := range am.samples {
+		// drop samples that left the window:
+		keep := 0
+		for ; (keep < len(samples)) && samples[keep].when.Before(cutoff); keep++ {
+		}
+		samples = samples[keep:]
+		am.samples[hostname] = samples
+		if 0 == len(samples) {
+			delete(am.samples, hostname)
+			continue
+		}
+		if aNow.Sub(am.lastAlert[hostname]) < am.cooldown {
+			continue
+		}
+
+		failed := 0
+		latencies := make([]time.Duration, len(samples))
+		for idx, sample := range samples {
+			latencies[idx] = sample.latency
+			if sample.failed {
+				failed++
+			}
+		}
+		sort.Slice(latencies, func(i, j int) bool {
+			return latencies[i] < latencies[j]
+		})
+		p95 := latencies[(len(latencies)*95)/100]
+		errRate := float64(failed) * 100 / float64(len(samples))
+
+		var reason string
+		if (0 < am.conf.P95Millis) &&
+			(p95.Milliseconds() > am.conf.P95Millis) {
+			reason = fmt.Sprintf("p95 latency %v exceeds budget %dms",
+				p95.Round(time.Millisecond), am.conf.P95Millis)
+		} else if (0 < am.conf.ErrorPercent) &&
+			(errRate > am.conf.ErrorPercent) {
+			reason = fmt.Sprintf("error rate %.1f%% exceeds budget %.1f%%",
+				errRate, am.conf.ErrorPercent)
+		} else {
+			continue
+		}
+
+		am.lastAlert[hostname] = aNow
+		go am.fire(hostname, reason, p95, errRate)
+	}
+} // evaluate()
+
+// `fire()` delivers one alert to the error log and the webhook.
+func (am *TAlertMonitor) fire(aHostname, aReason string, aP95 time.Duration, aErrRate float64) {
+	am.logger.Error(aHostname, `latency budget alert: `+aReason)
+	if "" == am.conf.WebhookURL {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		`host`:         aHostname,
+		`reason`:       aReason,
+		`p95Millis`:    aP95.Milliseconds(),
+		`errorPercent`: aErrRate,
+		`time`:         time.Now().Format(time.RFC3339),
+	})
+	if nil != err {
+		return
+	}
+	resp, err := am.client.Post(am.conf.WebhookURL,
+		`application/json`, bytes.NewReader(payload))
+	if nil != err {
+		am.logger.Error(aHostname, `alert webhook failed: `+err.Error())
+		return
+	}
+	_ = resp.Body.Close()
+} // fire()
+
+// `record()` adds one finished request to the sliding window and
+// re-evaluates the budgets at most every ten seconds.
+//
+// Parameters:
+//   - `aHostname`: The virtual host the request belonged to.
+//   - `aLatency`: The total time spent serving the request.
+//   - `aStatus`: The HTTP status sent to the client.
+func (am *TAlertMonitor) record(aHostname string, aLatency time.Duration, aStatus int) {
+	now := time.Now()
+
+	am.mtx.Lock()
+	defer am.mtx.Unlock()
+	am.samples[aHostname] = append(am.samples[aHostname], tAlertSample{
+		when:    now,
+		latency: aLatency,
+		failed:  500 <= aStatus,
+	})
+	if 10*time.Second <= now.Sub(am.lastEval) {
+		am.lastEval = now
+		am.evaluate(now)
+	}
+} // record()
+
+/* _EoF_ */
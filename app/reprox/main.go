@@ -0,0 +1,464 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// The reprox binary runs the reverse proxy on the standard HTTP and
+// HTTPS ports, reading its setup from a JSON configuration file.
+package main
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/mwat56/reprox"
+	"github.com/mwat56/reprox/events"
+)
+
+// `exit()` terminates the program with an error message.
+func exit(aMessage string) {
+	slog.Error(aMessage)
+	os.Exit(1)
+} // exit()
+
+// `setupSlog()` configures the process-wide structured logger used
+// for the proxy's internal diagnostics; the Apache-style access log
+// format is unaffected.
+func setupSlog(aLevel, aFormat string) {
+	var level slog.Level
+	switch strings.ToLower(aLevel) {
+	case `debug`:
+		level = slog.LevelDebug
+	case `warn`:
+		level = slog.LevelWarn
+	case `error`:
+		level = slog.LevelError
+	default:
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if `json` == strings.ToLower(aFormat) {
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+} // setupSlog()
+
+// `createServ()` prepares an HTTP server listening on `aAddr`.
+//
+// `aCtx` is the server's lifetime context owned by `main()`; it is
+// only cancelled once the proxy shuts down. Per-request deadlines
+// are handled by the proxy handler itself, not by the server.
+func createServ(aHandler http.Handler, aAddr string, aCtx context.Context) *http.Server {
+	return &http.Server{
+		Addr:              aAddr,
+		Handler:           aHandler,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		BaseContext: func(net.Listener) context.Context {
+			return aCtx
+		},
+	}
+} // createServ()
+
+// `setupSignals()` installs the termination and log re-opening
+// signal handlers.
+//
+// On `SIGINT`/`SIGTERM` the servers stop accepting new connections,
+// in-flight requests (including hijacked WebSocket streams) are
+// drained for up to the configured grace period, and only then the
+// process exits.
+// On `SIGUSR2` a new reprox binary is exec'd with the listening
+// sockets handed over, and this process drains and exits.
+func setupSignals(aServers []*http.Server, aListeners []net.Listener,
+	aHandler *reprox.TProxyHandler, aLogger *reprox.TLogger, aGrace time.Duration) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM,
+		syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range sigChan {
+			switch sig {
+			case syscall.SIGUSR1:
+				if err := aLogger.Reopen(); nil != err {
+					slog.Error(`reopening logs`, `error`, err)
+				}
+				continue
+
+			case syscall.SIGUSR2:
+				slog.Info(`upgrading binary, handing over sockets`)
+				if err := startUpgrade(aListeners); nil != err {
+					slog.Error(`binary upgrade failed`, `error`, err)
+					continue
+				}
+
+			default:
+				slog.Info(`shutting down`, `signal`, sig.String())
+			}
+			sdNotify(`STOPPING=1`)
+			aHandler.Bus().Publish(events.TopicShutdown, sig.String())
+			shutdown(aServers, aHandler, aGrace)
+			_ = aLogger.Close()
+			os.Exit(0)
+		}
+	}()
+} // setupSignals()
+
+// `setupReload()` installs the `SIGHUP` handler re-reading the
+// configuration file, reporting the reload to systemd.
+func setupReload(aConfName string, aHandler *reprox.TProxyHandler, aAudit *reprox.TAuditLog) {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	go func() {
+		for range sigChan {
+			sdNotify(`RELOADING=1`)
+			conf, err := reprox.LoadConfig(aConfName)
+			if nil == err {
+				err = aHandler.Reload(conf)
+			}
+			if nil == err {
+				slog.Info(`configuration reloaded`, `config`, aConfName)
+				aAudit.Log(`SIGHUP`, `reload`, `applied `+aConfName)
+			} else {
+				slog.Error(`config reload failed`, `error`, err)
+				aAudit.Log(`SIGHUP`, `reload`, `failed: `+err.Error())
+			}
+			sdNotify(`READY=1`)
+		}
+	}()
+} // setupReload()
+
+// `shutdown()` gracefully stops all `aServers`, draining in-flight
+// requests for at most `aGrace`.
+func shutdown(aServers []*http.Server, aHandler *reprox.TProxyHandler, aGrace time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), aGrace)
+	defer cancel()
+
+	// persist dynamic state (ban list etc.) before draining:
+	if err := aHandler.SaveState(); nil != err {
+		slog.Error(`persisting state failed`, `error`, err)
+	}
+
+	// Stop accepting, wait for tracked in-flight requests, and
+	// close idle keep-alive connections:
+	var wg sync.WaitGroup
+	for _, server := range aServers {
+		wg.Add(1)
+		go func(aServer *http.Server) {
+			defer wg.Done()
+			_ = aServer.Shutdown(ctx)
+		}(server)
+	}
+	wg.Wait()
+
+	// `Shutdown()` doesn't wait for hijacked (WebSocket)
+	// connections, so drain those separately:
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for 0 < aHandler.Metrics().ActiveTotal() {
+		select {
+		case <-ctx.Done():
+			for _, server := range aServers {
+				_ = server.Close()
+			}
+			return
+		case <-ticker.C:
+		}
+	}
+} // shutdown()
+
+// `tlsConfig()` builds the TLS setup from the certificates of the
+// configured virtual hosts.
+func tlsConfig(aConfig *reprox.TConfig) (*tls.Config, error) {
+	// the host tree resolves wildcard names (`*.mwat.de`) to the
+	// most specific certificate, same as the HTTP router:
+	certs := reprox.NewHostTree[*tls.Certificate]()
+	count := 0
+	for hostname, host := range aConfig.Hosts {
+		if ("" == host.CertFile) || ("" == host.KeyFile) {
+			continue
+		}
+		cert, err := tls.LoadX509KeyPair(host.CertFile, host.KeyFile)
+		if nil != err {
+			return nil, fmt.Errorf("certificate for %q: %w", hostname, err)
+		}
+		certs.Set(hostname, &cert)
+		count++
+	}
+	if 0 == count {
+		return nil, nil
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(aHello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			if cert, ok := certs.Match(aHello.ServerName); ok {
+				return cert, nil
+			}
+			return nil, fmt.Errorf("no certificate for %q", aHello.ServerName)
+		},
+	}, nil
+} // tlsConfig()
+
+func main() {
+	confName := flag.String(`conf`, `reprox.json`,
+		`name of the JSON configuration file`)
+	devMode := flag.Bool(`dev`, false,
+		`serve HTTPS with a generated self-signed certificate`)
+	logLevel := flag.String(`loglevel`, `info`,
+		`diagnostic log level (debug/info/warn/error)`)
+	logFormat := flag.String(`logformat`, `text`,
+		`diagnostic log format (text/json)`)
+	flag.Parse()
+	setupSlog(*logLevel, *logFormat)
+
+	conf, err := reprox.LoadConfig(*confName)
+	if nil != err {
+		exit(err.Error())
+	}
+	logger, err := reprox.NewLogger(conf)
+	if nil != err {
+		exit(err.Error())
+	}
+	defer logger.Close()
+
+	options := []reprox.TOption{
+		reprox.WithLogger(logger), reprox.WithConfig(conf),
+		reprox.WithEventLogger(reprox.NewSlogAdapter(slog.Default())),
+	}
+	if "" != conf.StateDir {
+		store, err := reprox.NewFileStore(conf.StateDir)
+		if nil != err {
+			exit(err.Error())
+		}
+		defer store.Close()
+		options = append(options, reprox.WithStateStore(store))
+	}
+	handler, err := reprox.NewProxyHandler(options...)
+	if nil != err {
+		exit(err.Error())
+	}
+
+	var audit *reprox.TAuditLog
+	if "" != conf.AuditLog {
+		if audit, err = reprox.NewAuditLog(conf.AuditLog); nil != err {
+			exit(err.Error())
+		}
+		defer audit.Close()
+	}
+
+	checker := reprox.NewHealthChecker(conf.Health, handler)
+	checker.SetAudit(audit)
+	checker.Start()
+	defer checker.Stop()
+
+	for _, tcpConf := range conf.TCPProxies {
+		tcpProxy := reprox.NewTCPProxy(tcpConf, handler.Metrics(), logger)
+		defer tcpProxy.Close()
+		go func(aProxy *reprox.TTCPProxy, aListen string) {
+			if err := aProxy.ListenAndServe(); nil != err {
+				exit(`tcp proxy ` + aListen + `: ` + err.Error())
+			}
+		}(tcpProxy, tcpConf.Listen)
+	}
+
+	for _, udpConf := range conf.UDPRelays {
+		udpRelay := reprox.NewUDPRelay(udpConf, handler.Metrics(), logger)
+		defer udpRelay.Close()
+		go func(aRelay *reprox.TUDPRelay, aListen string) {
+			if err := aRelay.ListenAndServe(); nil != err {
+				exit(`udp relay ` + aListen + `: ` + err.Error())
+			}
+		}(udpRelay, udpConf.Listen)
+	}
+
+	if "" != conf.RoutesDir {
+		routesDir := reprox.NewRoutesDirWatcher(conf.RoutesDir, handler, logger)
+		routesDir.Start()
+		defer routesDir.Stop()
+	}
+
+	if nil != conf.Docker {
+		docker := reprox.NewDockerWatcher(conf.Docker, handler, logger)
+		docker.Start()
+		defer docker.Stop()
+	}
+
+	if nil != conf.Etcd {
+		etcd := reprox.NewEtcdWatcher(conf.Etcd, handler, logger)
+		etcd.Start()
+		defer etcd.Stop()
+	}
+
+	var cluster *reprox.TClusterSync
+	if nil != conf.Cluster {
+		if cluster, err = reprox.NewClusterSync(
+			conf.Cluster, handler, logger); nil != err {
+			exit(err.Error())
+		}
+		cluster.Start()
+		defer cluster.Stop()
+	}
+
+	var failover *reprox.TFailoverMonitor
+	if nil != conf.Failover {
+		if failover, err = reprox.NewFailoverMonitor(
+			conf.Failover, handler, logger); nil != err {
+			exit(err.Error())
+		}
+		failover.Start()
+		defer failover.Stop()
+	}
+
+	certWatcher := reprox.NewCertWatcher(conf.CertWatch, handler, logger)
+	certWatcher.SetAudit(audit)
+	certWatcher.Start()
+	defer certWatcher.Stop()
+
+	if nil != conf.Admin {
+		admin, err := reprox.NewAdmin(conf.Admin, *confName, handler, logger)
+		if nil != err {
+			exit(err.Error())
+		}
+		admin.SetAudit(audit)
+		admin.SetHealth(checker)
+		checker.SetEvents(admin.Events())
+		admin.SetCertWatcher(certWatcher)
+		if nil != cluster {
+			admin.SetCluster(cluster)
+		}
+		if nil != failover {
+			admin.SetFailover(failover)
+		}
+		if nil != conf.AcmeDNS {
+			provider, err := reprox.NewDNSProvider(conf.AcmeDNS)
+			if nil != err {
+				exit(err.Error())
+			}
+			admin.SetDNSProvider(provider)
+		}
+		go func() {
+			if err := admin.ListenAndServe(conf.Admin); nil != err {
+				exit(err.Error())
+			}
+		}()
+	}
+
+	grace := time.Duration(conf.GraceSec) * time.Second
+	if 0 >= grace {
+		grace = 30 * time.Second
+	}
+
+	lifeCtx, lifeCancel := context.WithCancel(context.Background())
+	defer lifeCancel()
+
+	// systemd socket activation takes precedence over sockets
+	// handed over by a SIGUSR2 binary upgrade:
+	inherited := sdListeners()
+	if nil == inherited {
+		inherited = inheritedListeners()
+	}
+
+	tlsConf, err := tlsConfig(conf)
+	if nil != err {
+		exit(err.Error())
+	}
+
+	if nil != tlsConf {
+		rotator, err := reprox.NewTicketRotator(conf.TicketKeys, tlsConf)
+		if nil != err {
+			exit(err.Error())
+		}
+		rotator.Start()
+		defer rotator.Stop()
+	}
+
+	specs := conf.ListenerSpecs()
+	if *devMode {
+		// dev/test: a throw-away mini-CA covers all hosts so the
+		// HTTPS path works without real certificates:
+		hostnames := make([]string, 0, len(conf.Hosts))
+		for hostname := range conf.Hosts {
+			hostnames = append(hostnames, hostname)
+		}
+		if tlsConf, err = reprox.DevTLSConfig(hostnames); nil != err {
+			exit(err.Error())
+		}
+		hasTLS := false
+		for _, spec := range specs {
+			hasTLS = hasTLS || spec.TLS
+		}
+		if !hasTLS {
+			specs = append(specs,
+				&reprox.TListenerConfig{Addr: `:443`, TLS: true})
+		}
+	}
+	servers := make([]*http.Server, 0, len(specs))
+	listeners := make([]net.Listener, 0, len(specs)) // raw, for handoff
+	serveLsts := make([]net.Listener, 0, len(specs)) // possibly wrapped
+	for idx, spec := range specs {
+		server := createServ(handler, spec.Addr, lifeCtx)
+		if spec.TLS {
+			if nil == tlsConf {
+				exit(fmt.Sprintf(
+					"listener %s: TLS requested but no certificates configured",
+					spec.Addr))
+			}
+			server.TLSConfig = tlsConf
+		}
+		lst, err := listen(inherited, idx, spec)
+		if nil != err {
+			exit(err.Error())
+		}
+		listeners = append(listeners, lst)
+		if spec.ProxyProtocol {
+			lst = reprox.NewProxyProtoListener(lst)
+		}
+		serveLsts = append(serveLsts, lst)
+		servers = append(servers, server)
+	}
+	// all privileged sockets are bound - become unprivileged:
+	if err := dropPrivileges(conf.User, conf.Group); nil != err {
+		exit(err.Error())
+	}
+
+	setupSignals(servers, listeners, handler, logger, grace)
+	setupReload(*confName, handler, audit)
+	sdWatchdog()
+	sdNotify(`READY=1`)
+
+	for idx := range servers {
+		go func(aServer *http.Server, aLst net.Listener) {
+			var err error
+			if nil != aServer.TLSConfig {
+				err = aServer.ServeTLS(aLst, ``, ``)
+			} else {
+				err = aServer.Serve(aLst)
+			}
+			if (nil != err) && (http.ErrServerClosed != err) {
+				exit(err.Error())
+			}
+		}(servers[idx], serveLsts[idx])
+	}
+	// Block until the signal handler terminates the process after
+	// draining; the servers run in their own goroutines above.
+	select {}
+} // main()
+
+/* _EoF_ */
@@ -0,0 +1,80 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package main
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// `dropPrivileges()` switches the process to the unprivileged
+// account `aUser`/`aGroup` after the low ports have been bound.
+//
+// Called as root with a configured user this reduces the blast
+// radius of a compromise; as non-root (e.g. with the binary granted
+// CAP_NET_BIND_SERVICE) it is a silent no-op. Already opened
+// listeners and log files stay usable after the switch.
+//
+// Parameters:
+//   - `aUser`: Name or numeric ID of the target user.
+//   - `aGroup`: Name or numeric ID of the target group (optional,
+//     defaulting to the user's primary group).
+//
+// Returns:
+//   - `error`: A possible error switching the IDs.
+func dropPrivileges(aUser, aGroup string) error {
+	if ("" == aUser) || (0 != os.Getuid()) {
+		return nil
+	}
+
+	account, err := user.Lookup(aUser)
+	if nil != err {
+		if account, err = user.LookupId(aUser); nil != err {
+			return fmt.Errorf("unknown user %q: %w", aUser, err)
+		}
+	}
+	uid, err := strconv.Atoi(account.Uid)
+	if nil != err {
+		return fmt.Errorf("user %q: %w", aUser, err)
+	}
+
+	gidStr := account.Gid
+	if "" != aGroup {
+		group, err := user.LookupGroup(aGroup)
+		if nil != err {
+			if group, err = user.LookupGroupId(aGroup); nil != err {
+				return fmt.Errorf("unknown group %q: %w", aGroup, err)
+			}
+		}
+		gidStr = group.Gid
+	}
+	gid, err := strconv.Atoi(gidStr)
+	if nil != err {
+		return fmt.Errorf("group %q: %w", aGroup, err)
+	}
+
+	// the order matters: supplementary groups and GID can only be
+	// changed while still being root:
+	if err = syscall.Setgroups([]int{gid}); nil != err {
+		return fmt.Errorf("setgroups: %w", err)
+	}
+	if err = syscall.Setgid(gid); nil != err {
+		return fmt.Errorf("setgid %d: %w", gid, err)
+	}
+	if err = syscall.Setuid(uid); nil != err {
+		return fmt.Errorf("setuid %d: %w", uid, err)
+	}
+
+	return nil
+} // dropPrivileges()
+
+/* _EoF_ */
@@ -0,0 +1,85 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package main
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// `sdListeners()` recovers listening sockets handed over by systemd
+// socket activation (LISTEN_FDS protocol), if any.
+//
+// Returns:
+//   - `[]net.Listener`: The activated listeners in unit file order.
+func sdListeners() []net.Listener {
+	pid, err := strconv.Atoi(os.Getenv(`LISTEN_PID`))
+	if (nil != err) || (os.Getpid() != pid) {
+		return nil
+	}
+	count, err := strconv.Atoi(os.Getenv(`LISTEN_FDS`))
+	if (nil != err) || (0 >= count) {
+		return nil
+	}
+	_ = os.Unsetenv(`LISTEN_PID`)
+	_ = os.Unsetenv(`LISTEN_FDS`)
+	_ = os.Unsetenv(`LISTEN_FDNAMES`)
+
+	listeners := make([]net.Listener, 0, count)
+	for idx := 0; idx < count; idx++ {
+		file := os.NewFile(uintptr(3+idx), `sd-listener`)
+		if nil == file {
+			continue
+		}
+		if listener, err := net.FileListener(file); nil == err {
+			listeners = append(listeners, listener)
+		}
+		_ = file.Close()
+	}
+
+	return listeners
+} // sdListeners()
+
+// `sdNotify()` sends `aState` (e.g. `READY=1`) to the service
+// manager's notification socket; it's a no-op outside systemd.
+func sdNotify(aState string) {
+	socket := os.Getenv(`NOTIFY_SOCKET`)
+	if "" == socket {
+		return
+	}
+
+	conn, err := net.Dial(`unixgram`, socket)
+	if nil != err {
+		return
+	}
+	defer conn.Close()
+	_, _ = conn.Write([]byte(aState))
+} // sdNotify()
+
+// `sdWatchdog()` starts the periodic watchdog ping if systemd
+// requested one via WATCHDOG_USEC.
+func sdWatchdog() {
+	usec, err := strconv.ParseInt(os.Getenv(`WATCHDOG_USEC`), 10, 64)
+	if (nil != err) || (0 >= usec) {
+		return
+	}
+	interval := time.Duration(usec) * time.Microsecond / 2
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sdNotify(`WATCHDOG=1`)
+		}
+	}()
+} // sdWatchdog()
+
+/* _EoF_ */
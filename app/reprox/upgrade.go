@@ -0,0 +1,154 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package main
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/mwat56/reprox"
+)
+
+// `upgradeEnv` tells a freshly exec'd reprox how many listening
+// sockets it inherited from the old process (as fds 3, 4, ...).
+const upgradeEnv = `REPROX_UPGRADE_FDS`
+
+// `inheritedListeners()` recovers the listening sockets passed down
+// by the previous reprox process, if any.
+//
+// Returns:
+//   - `[]net.Listener`: The inherited listeners in binding order.
+func inheritedListeners() []net.Listener {
+	count, err := strconv.Atoi(os.Getenv(upgradeEnv))
+	if (nil != err) || (0 >= count) {
+		return nil
+	}
+	_ = os.Unsetenv(upgradeEnv)
+
+	listeners := make([]net.Listener, 0, count)
+	for idx := 0; idx < count; idx++ {
+		file := os.NewFile(uintptr(3+idx), fmt.Sprintf("listener-%d", idx))
+		if nil == file {
+			continue
+		}
+		if listener, err := net.FileListener(file); nil == err {
+			listeners = append(listeners, listener)
+		}
+		_ = file.Close()
+	}
+
+	return listeners
+} // inheritedListeners()
+
+// `listen()` returns the inherited listener for `aSpec` at position
+// `aIdx`, or binds a fresh one.
+//
+// For filesystem Unix sockets a stale socket file is removed first
+// and the configured mode/owner are applied after binding.
+func listen(aInherited []net.Listener, aIdx int, aSpec *reprox.TListenerConfig) (net.Listener, error) {
+	if aIdx < len(aInherited) {
+		return aInherited[aIdx], nil
+	}
+
+	network := aSpec.Network()
+	if (`unix` == network) && ('@' != aSpec.Addr[0]) {
+		_ = os.Remove(aSpec.Addr)
+	}
+	listener, err := net.Listen(network, aSpec.Addr)
+	if nil != err {
+		return nil, err
+	}
+	if (`unix` == network) && ('@' != aSpec.Addr[0]) {
+		if err = applySocketPerms(aSpec); nil != err {
+			_ = listener.Close()
+			return nil, err
+		}
+	}
+
+	return listener, nil
+} // listen()
+
+// `applySocketPerms()` sets mode and owner of the Unix socket file.
+func applySocketPerms(aSpec *reprox.TListenerConfig) error {
+	mode := os.FileMode(0660)
+	if "" != aSpec.SocketMode {
+		parsed, err := strconv.ParseUint(aSpec.SocketMode, 8, 32)
+		if nil != err {
+			return fmt.Errorf("socket mode %q: %w", aSpec.SocketMode, err)
+		}
+		mode = os.FileMode(parsed)
+	}
+	if err := os.Chmod(aSpec.Addr, mode); nil != err {
+		return err
+	}
+	if "" == aSpec.SocketOwner {
+		return nil
+	}
+
+	account, err := user.Lookup(aSpec.SocketOwner)
+	if nil != err {
+		return fmt.Errorf("socket owner %q: %w", aSpec.SocketOwner, err)
+	}
+	uid, _ := strconv.Atoi(account.Uid)
+	gid, _ := strconv.Atoi(account.Gid)
+
+	return os.Chown(aSpec.Addr, uid, gid)
+} // applySocketPerms()
+
+// `startUpgrade()` exec's a new reprox binary handing over all
+// listening sockets, nginx/Caddy-style.
+//
+// The caller (the old process) keeps serving until its own graceful
+// shutdown finished, so no connection is ever refused during the
+// binary swap.
+//
+// Parameters:
+//   - `aListeners`: The listening sockets to pass to the child.
+//
+// Returns:
+//   - `error`: A possible error spawning the new process.
+func startUpgrade(aListeners []net.Listener) error {
+	binary, err := os.Executable()
+	if nil != err {
+		return fmt.Errorf("upgrade: %w", err)
+	}
+
+	files := []*os.File{os.Stdin, os.Stdout, os.Stderr}
+	passed := 0
+	for _, listener := range aListeners {
+		filer, ok := listener.(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+		file, err := filer.File()
+		if nil != err {
+			return fmt.Errorf("upgrade: %w", err)
+		}
+		files = append(files, file)
+		passed++
+	}
+
+	env := append(os.Environ(),
+		upgradeEnv+`=`+strconv.Itoa(passed))
+	proc, err := os.StartProcess(binary, os.Args, &os.ProcAttr{
+		Env:   env,
+		Files: files,
+	})
+	if nil != err {
+		return fmt.Errorf("upgrade: %w", err)
+	}
+	_ = proc.Release()
+
+	return nil
+} // startUpgrade()
+
+/* _EoF_ */
@@ -0,0 +1,108 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// `TAuditLog` is an append-only log of administrative actions:
+// configuration reloads, admin API mutations, backend ejections and
+// readmissions, and certificate changes.
+//
+// Unlike the access and error logs it is never rotated or sampled;
+// every entry carries a timestamp and the acting principal (an admin
+// client's address, `SIGHUP`, or an internal component name).
+//
+// A nil `*TAuditLog` is valid and discards all entries, so callers
+// don't have to guard every `Log()` call.
+type TAuditLog struct {
+	mtx  sync.Mutex
+	file io.WriteCloser
+}
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewAuditLog()` opens (or creates) the append-only audit log file
+// `aName`.
+//
+// Parameters:
+//   - `aName`: The audit log's file name.
+//
+// Returns:
+//   - `*TAuditLog`: The new audit log instance.
+//   - `error`: A possible error opening the file.
+func NewAuditLog(aName string) (*TAuditLog, error) {
+	file, err := os.OpenFile(aName,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if nil != err {
+		return nil, fmt.Errorf("audit log: %w", err)
+	}
+
+	return &TAuditLog{file: file}, nil
+} // NewAuditLog()
+
+// --------------------------------------------------------------------------
+// TAuditLog methods:
+
+// `Close()` closes the underlying log file.
+func (al *TAuditLog) Close() error {
+	if nil == al {
+		return nil
+	}
+	al.mtx.Lock()
+	defer al.mtx.Unlock()
+
+	if nil == al.file {
+		return nil
+	}
+	err := al.file.Close()
+	al.file = nil
+
+	return err
+} // Close()
+
+// `Log()` appends one audit entry.
+//
+// Parameters:
+//   - `aPrincipal`: Who acted, e.g. an admin client's address.
+//   - `aAction`: The short action name, e.g. `route-add`.
+//   - `aDetail`: A free-form description of what changed.
+func (al *TAuditLog) Log(aPrincipal, aAction, aDetail string) {
+	if nil == al {
+		return
+	}
+	al.mtx.Lock()
+	defer al.mtx.Unlock()
+
+	if nil == al.file {
+		return
+	}
+	_, _ = fmt.Fprintf(al.file, "%s %s %s: %s\n",
+		time.Now().Format(time.RFC3339), aPrincipal, aAction, aDetail)
+} // Log()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `SetAudit()` attaches the audit log recording all mutating admin
+// API calls.
+//
+// Parameters:
+//   - `aAudit`: The audit log to write to.
+func (ad *TAdmin) SetAudit(aAudit *TAuditLog) {
+	ad.audit = aAudit
+} // SetAudit()
+
+/* _EoF_ */
@@ -0,0 +1,94 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"testing"
+	"time"
+)
+
+func TestRouteUseBackup(t *testing.T) {
+	route := &tRoute{}
+	route.markPrimaryDown()
+	if route.useBackup() {
+		t.Error(`useBackup() without a configured backup`)
+	}
+
+	route.backup = &httputil.ReverseProxy{}
+	if !route.useBackup() {
+		t.Error(`useBackup() = false right after a primary failure`)
+	}
+
+	// past the retry period the primary is tried again:
+	route.downUntil.Store(time.Now().Add(-time.Second).UnixNano())
+	if route.useBackup() {
+		t.Error(`useBackup() = true past the retry period`)
+	}
+} // TestRouteUseBackup()
+
+func TestBackupFailover(t *testing.T) {
+	backup := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`backup answer`))
+		}))
+	defer backup.Close()
+
+	// a primary that's guaranteed down: grab a port, then free it.
+	primary := httptest.NewServer(http.NotFoundHandler())
+	primaryURL := primary.URL
+	primary.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target:       primaryURL,
+		BackupTarget: backup.URL,
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the first request hits the dead primary and fails, which
+	// marks the primary down:
+	if rec := get(); http.StatusBadGateway != rec.Code {
+		t.Fatalf(`first request status = %d, want 502`, rec.Code)
+	}
+
+	// subsequent requests are diverted to the backup:
+	rec := get()
+	if http.StatusOK != rec.Code {
+		t.Fatalf(`diverted request status = %d, want 200`, rec.Code)
+	}
+	if `backup answer` != rec.Body.String() {
+		t.Errorf(`diverted request answered %q`, rec.Body.String())
+	}
+
+	// once the retry period has passed, the primary is contacted
+	// again - and its renewed failure re-arms the diversion:
+	route := ph.routes.lookup(`example.org`)
+	if nil == route {
+		t.Fatal(`route lookup failed`)
+	}
+	route.downUntil.Store(time.Now().UnixNano() - 1)
+	if rec = get(); http.StatusBadGateway != rec.Code {
+		t.Errorf(`retry request status = %d, want 502`, rec.Code)
+	}
+	if rec = get(); `backup answer` != rec.Body.String() {
+		t.Errorf(`request after retry answered %q`, rec.Body.String())
+	}
+} // TestBackupFailover()
+
+/* _EoF_ */
@@ -0,0 +1,84 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// Package bench holds the performance regression benchmarks of the
+// reprox proxy; run them with
+//
+//	go test -bench . -benchmem ./bench/
+package bench
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mwat56/reprox"
+)
+
+// `newBenchProxy()` builds a proxy handler with `aRoutes` virtual
+// hosts all pointing at `aBackend`.
+func newBenchProxy(aB *testing.B, aRoutes int, aBackend string) *reprox.TProxyHandler {
+	routes := make(map[string]string, aRoutes)
+	for idx := 0; idx < aRoutes; idx++ {
+		routes[fmt.Sprintf("host-%d.example.org", idx)] = aBackend
+	}
+	ph, _ := reprox.NewProxyHandler(reprox.WithRoutes(routes))
+	if err := ph.BuildRoutes(); nil != err {
+		aB.Fatal(err)
+	}
+
+	return ph
+} // newBenchProxy()
+
+// `BenchmarkRouting10k` exercises the route lookup with 10 000
+// configured virtual hosts.
+func BenchmarkRouting10k(b *testing.B) {
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			aWriter.WriteHeader(http.StatusNoContent)
+		}))
+	defer backend.Close()
+	ph := newBenchProxy(b, 10000, backend.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for idx := 0; idx < b.N; idx++ {
+		req := httptest.NewRequest(http.MethodGet,
+			fmt.Sprintf("http://host-%d.example.org/", idx%10000), nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+	}
+} // BenchmarkRouting10k()
+
+// `BenchmarkProxyParallel` measures proxy throughput with concurrent
+// clients against a fake backend.
+func BenchmarkProxyParallel(b *testing.B) {
+	payload := make([]byte, 16<<10)
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write(payload)
+		}))
+	defer backend.Close()
+	ph := newBenchProxy(b, 8, backend.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(aPB *testing.PB) {
+		for aPB.Next() {
+			req := httptest.NewRequest(http.MethodGet,
+				`http://host-0.example.org/`, nil)
+			rec := httptest.NewRecorder()
+			ph.ServeHTTP(rec, req)
+			if http.StatusOK != rec.Code {
+				b.Fatalf(`status = %d`, rec.Code)
+			}
+		}
+	})
+} // BenchmarkProxyParallel()
+
+/* _EoF_ */
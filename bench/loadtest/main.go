@@ -0,0 +1,136 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// The loadtest binary spins up fake backends plus a reprox instance
+// and hammers it with concurrent requests, reporting throughput and
+// latency percentiles:
+//
+//	go run ./bench/loadtest -conns 64 -duration 30s -size 16384
+package main
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mwat56/reprox"
+)
+
+// `tResult` collects the measurements of one worker.
+type tResult struct {
+	latencies []time.Duration
+	bytes     int64
+	errors    int64
+}
+
+func main() {
+	backends := flag.Int(`backends`, 4, `number of fake backends`)
+	conns := flag.Int(`conns`, 32, `concurrent client connections`)
+	duration := flag.Duration(`duration`, 10*time.Second, `test duration`)
+	size := flag.Int(`size`, 16<<10, `response body size in bytes`)
+	flag.Parse()
+
+	payload := make([]byte, *size)
+	routes := make(map[string]string, *backends)
+	for idx := 0; idx < *backends; idx++ {
+		lst, err := net.Listen(`tcp`, `127.0.0.1:0`)
+		if nil != err {
+			log.Fatalln(`loadtest:`, err)
+		}
+		go func() {
+			_ = http.Serve(lst, http.HandlerFunc(
+				func(aWriter http.ResponseWriter, aRequest *http.Request) {
+					_, _ = aWriter.Write(payload)
+				}))
+		}()
+		routes[fmt.Sprintf("host-%d.test", idx)] =
+			`http://` + lst.Addr().String()
+	}
+
+	ph, err := reprox.NewProxyHandler(reprox.WithRoutes(routes))
+	if nil != err {
+		log.Fatalln(`loadtest:`, err)
+	}
+	proxyLst, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if nil != err {
+		log.Fatalln(`loadtest:`, err)
+	}
+	go func() { _ = http.Serve(proxyLst, ph) }()
+	proxyAddr := proxyLst.Addr().String()
+
+	var (
+		done    atomic.Bool
+		wg      sync.WaitGroup
+		results = make([]tResult, *conns)
+	)
+	started := time.Now()
+	for worker := 0; worker < *conns; worker++ {
+		wg.Add(1)
+		go func(aIdx int) {
+			defer wg.Done()
+			client := &http.Client{Timeout: 30 * time.Second}
+			hostname := fmt.Sprintf("host-%d.test", aIdx%*backends)
+			for !done.Load() {
+				reqStart := time.Now()
+				req, _ := http.NewRequest(http.MethodGet,
+					`http://`+proxyAddr+`/`, nil)
+				req.Host = hostname
+				resp, err := client.Do(req)
+				if nil != err {
+					results[aIdx].errors++
+					continue
+				}
+				copied, _ := io.Copy(io.Discard, resp.Body)
+				_ = resp.Body.Close()
+				results[aIdx].bytes += copied
+				results[aIdx].latencies = append(results[aIdx].latencies,
+					time.Since(reqStart))
+			}
+		}(worker)
+	}
+	time.Sleep(*duration)
+	done.Store(true)
+	wg.Wait()
+	elapsed := time.Since(started)
+
+	var (
+		all       []time.Duration
+		bytesSum  int64
+		errorsSum int64
+	)
+	for _, result := range results {
+		all = append(all, result.latencies...)
+		bytesSum += result.bytes
+		errorsSum += result.errors
+	}
+	if 0 == len(all) {
+		log.Fatalln(`loadtest: no successful requests`)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	pct := func(aP int) time.Duration {
+		return all[(len(all)-1)*aP/100].Round(time.Microsecond)
+	}
+
+	fmt.Printf("requests: %d  errors: %d  duration: %v\n",
+		len(all), errorsSum, elapsed.Round(time.Millisecond))
+	fmt.Printf("throughput: %.0f req/s  %.1f MiB/s\n",
+		float64(len(all))/elapsed.Seconds(),
+		float64(bytesSum)/elapsed.Seconds()/(1<<20))
+	fmt.Printf("latency: p50 %v  p95 %v  p99 %v  max %v\n",
+		pct(50), pct(95), pct(99), all[len(all)-1].Round(time.Microsecond))
+} // main()
+
+/* _EoF_ */
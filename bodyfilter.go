@@ -0,0 +1,130 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type (
+	// `TBodyRewrite` is one textual replacement applied to response
+	// bodies, e.g. rewriting internal URLs emitted by a legacy
+	// backend to their public form.
+	TBodyRewrite struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	// `tRewriteReader` applies the configured replacements to a
+	// response body as it streams through, holding back just enough
+	// bytes to catch patterns crossing read boundaries.
+	tRewriteReader struct {
+		src      io.ReadCloser
+		rewrites []TBodyRewrite
+		pending  []byte
+		out      []byte
+		keep     int // bytes held back for boundary matches
+		eof      bool
+	}
+)
+
+// --------------------------------------------------------------------------
+// tRewriteReader methods:
+
+// `newRewriteReader()` wraps `aBody` with the streaming replacer.
+func newRewriteReader(aBody io.ReadCloser, aRewrites []TBodyRewrite) *tRewriteReader {
+	keep := 0
+	for _, rewrite := range aRewrites {
+		if keep < len(rewrite.From) {
+			keep = len(rewrite.From)
+		}
+	}
+
+	return &tRewriteReader{
+		src:      aBody,
+		rewrites: aRewrites,
+		keep:     keep - 1,
+	}
+} // newRewriteReader()
+
+// `Close()` closes the underlying response body.
+func (rr *tRewriteReader) Close() error {
+	return rr.src.Close()
+} // Close()
+
+// `Read()` returns the next chunk of the rewritten body.
+func (rr *tRewriteReader) Read(aBuf []byte) (int, error) {
+	for (0 == len(rr.out)) && !rr.eof {
+		chunk := make([]byte, 32<<10)
+		read, err := rr.src.Read(chunk)
+		if 0 < read {
+			rr.pending = append(rr.pending, chunk[:read]...)
+		}
+		if nil != err {
+			rr.eof = true
+			if io.EOF != err {
+				return 0, err
+			}
+		}
+
+		for _, rewrite := range rr.rewrites {
+			rr.pending = bytes.ReplaceAll(rr.pending,
+				[]byte(rewrite.From), []byte(rewrite.To))
+		}
+		// hold back the tail so patterns crossing the next read
+		// boundary still get caught:
+		emit := len(rr.pending)
+		if !rr.eof {
+			emit -= rr.keep
+		}
+		if 0 < emit {
+			rr.out = append(rr.out, rr.pending[:emit]...)
+			rr.pending = rr.pending[emit:]
+		}
+	}
+
+	if 0 == len(rr.out) {
+		return 0, io.EOF
+	}
+	copied := copy(aBuf, rr.out)
+	rr.out = rr.out[copied:]
+
+	return copied, nil
+} // Read()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `rewriteBody()` installs the streaming replacer on `aResponse` if
+// it is an uncompressed textual body; compressed responses are left
+// alone (the proxy strips `Accept-Encoding` on rewritten routes so
+// backends answer uncompressed).
+func rewriteBody(aResponse *http.Response, aRewrites []TBodyRewrite) {
+	if (nil == aResponse.Body) ||
+		("" != aResponse.Header.Get(`Content-Encoding`)) {
+		return
+	}
+	cType := aResponse.Header.Get(`Content-Type`)
+	if !strings.HasPrefix(cType, `text/`) &&
+		!strings.Contains(cType, `xml`) &&
+		!strings.Contains(cType, `javascript`) &&
+		!strings.Contains(cType, `json`) {
+		return
+	}
+
+	aResponse.Body = newRewriteReader(aResponse.Body, aRewrites)
+	// the rewritten length is unknown - switch to streaming:
+	aResponse.ContentLength = -1
+	aResponse.Header.Del(`Content-Length`)
+} // rewriteBody()
+
+/* _EoF_ */
@@ -0,0 +1,53 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// `tDribbleReader` returns at most one byte per read, exercising the
+// pattern-across-boundary handling of the rewrite reader.
+type tDribbleReader struct {
+	src io.Reader
+}
+
+func (dr *tDribbleReader) Read(aBuf []byte) (int, error) {
+	if 0 == len(aBuf) {
+		return 0, nil
+	}
+	return dr.src.Read(aBuf[:1])
+} // Read()
+
+func (dr *tDribbleReader) Close() error { return nil }
+
+func TestRewriteReader(t *testing.T) {
+	rewrites := []TBodyRewrite{
+		{From: `http://10.0.0.1:8181`, To: `https://example.org`},
+		{From: `intern`, To: `public`},
+	}
+	input := `<a href="http://10.0.0.1:8181/page">intern</a> tail`
+	want := `<a href="https://example.org/page">public</a> tail`
+
+	for name, src := range map[string]io.ReadCloser{
+		`oneChunk`: io.NopCloser(strings.NewReader(input)),
+		`dribble`:  &tDribbleReader{src: strings.NewReader(input)},
+	} {
+		got, err := io.ReadAll(newRewriteReader(src, rewrites))
+		if nil != err {
+			t.Fatalf(`%s: ReadAll() error = %v`, name, err)
+		}
+		if want != string(got) {
+			t.Errorf("%s: rewritten body\n got: %s\nwant: %s",
+				name, got, want)
+		}
+	}
+} // TestRewriteReader()
+
+/* _EoF_ */
@@ -0,0 +1,411 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// Package btree implements a generic binary search tree mapping
+// ordered keys to arbitrary values.
+//
+// Keys are constrained to `cmp.Ordered` so all comparisons are
+// type-checked at compile time - no runtime type switches, no
+// `interface{}` assertions in calling code.
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"cmp"
+)
+
+type (
+	// `tNode` is one node of the tree.
+	tNode[K cmp.Ordered, V any] struct {
+		key    K
+		value  V
+		left   *tNode[K, V]
+		right  *tNode[K, V]
+		height int // height of the subtree rooted here
+	}
+
+	// `TTree` is a self-balancing (AVL) binary search tree mapping
+	// keys of the ordered type `K` to values of type `V`, so even
+	// sorted insertions keep lookups at O(log n).
+	//
+	// The zero value is not usable; create instances with `New()`.
+	// The tree is not safe for concurrent use.
+	TTree[K cmp.Ordered, V any] struct {
+		root *tNode[K, V]
+		size int
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `FromSorted()` builds a perfectly balanced tree from `aPairs` in
+// O(n), avoiding the per-insert rebalancing costs when loading a
+// large table (routes, ACLs) at startup.
+//
+// `aPairs` must be sorted by strictly ascending key; input not
+// meeting that precondition is loaded pair by pair instead (still
+// correct, just O(n log n)).
+//
+// Parameters:
+//   - `aPairs`: The key/value pairs in ascending key order.
+//
+// Returns:
+//   - `*TTree[K, V]`: The new tree holding all pairs.
+func FromSorted[K cmp.Ordered, V any](aPairs []TPair[K, V]) *TTree[K, V] {
+	tree := New[K, V]()
+	for idx := 1; len(aPairs) > idx; idx++ {
+		if aPairs[idx-1].Key >= aPairs[idx].Key {
+			// unsorted (or duplicate keys): take the slow path
+			for _, pair := range aPairs {
+				tree.Insert(pair.Key, pair.Value)
+			}
+			return tree
+		}
+	}
+	tree.root = buildBalanced(aPairs)
+	tree.size = len(aPairs)
+
+	return tree
+} // FromSorted()
+
+// `New()` creates an empty tree mapping `K` keys to `V` values.
+//
+// Returns:
+//   - `*TTree[K, V]`: The new empty tree.
+func New[K cmp.Ordered, V any]() *TTree[K, V] {
+	return &TTree[K, V]{}
+} // New()
+
+// --------------------------------------------------------------------------
+// TTree methods:
+
+// `Ascend()` calls `aVisit` for every key/value pair in ascending
+// key order until the callback returns `false` or all pairs were
+// visited.
+//
+// Parameters:
+//   - `aVisit`: The callback; return `false` to stop the walk.
+func (bt *TTree[K, V]) Ascend(aVisit func(aKey K, aValue V) bool) {
+	ascend(bt.root, aVisit)
+} // Ascend()
+
+// `AscendRange()` calls `aVisit` in ascending key order for every
+// pair whose key satisfies `aLo <= key < aHi`, until the callback
+// returns `false` or the range is exhausted.
+//
+// Parameters:
+//   - `aLo`: The inclusive lower bound.
+//   - `aHi`: The exclusive upper bound.
+//   - `aVisit`: The callback; return `false` to stop the scan.
+func (bt *TTree[K, V]) AscendRange(aLo, aHi K, aVisit func(aKey K, aValue V) bool) {
+	ascendRange(bt.root, aLo, aHi, aVisit)
+} // AscendRange()
+
+// `Descend()` calls `aVisit` for every key/value pair in descending
+// key order until the callback returns `false` or all pairs were
+// visited.
+//
+// Parameters:
+//   - `aVisit`: The callback; return `false` to stop the walk.
+func (bt *TTree[K, V]) Descend(aVisit func(aKey K, aValue V) bool) {
+	descend(bt.root, aVisit)
+} // Descend()
+
+// `Delete()` removes `aKey` from the tree.
+//
+// Parameters:
+//   - `aKey`: The key to remove.
+//
+// Returns:
+//   - `bool`: Whether the key was present.
+func (bt *TTree[K, V]) Delete(aKey K) bool {
+	var removed bool
+	bt.root, removed = remove(bt.root, aKey)
+	if removed {
+		bt.size--
+	}
+
+	return removed
+} // Delete()
+
+// `Insert()` stores `aValue` under `aKey`, replacing any value
+// already stored under that key.
+//
+// Parameters:
+//   - `aKey`: The key to store under.
+//   - `aValue`: The value to store.
+//
+// Returns:
+//   - `bool`: Whether the key was newly added (`false` on update).
+func (bt *TTree[K, V]) Insert(aKey K, aValue V) bool {
+	var added bool
+	bt.root, added = insert(bt.root, aKey, aValue)
+	if added {
+		bt.size++
+	}
+
+	return added
+} // Insert()
+
+// `Len()` returns the number of keys currently stored.
+func (bt *TTree[K, V]) Len() int {
+	return bt.size
+} // Len()
+
+// `Max()` returns the largest key and its value.
+//
+// Returns:
+//   - `K`: The largest key (zero value if the tree is empty).
+//   - `V`: Its stored value.
+//   - `bool`: Whether the tree was non-empty.
+func (bt *TTree[K, V]) Max() (K, V, bool) {
+	if nil == bt.root {
+		var key K
+		var value V
+		return key, value, false
+	}
+	node := bt.root
+	for nil != node.right {
+		node = node.right
+	}
+
+	return node.key, node.value, true
+} // Max()
+
+// `Min()` returns the smallest key and its value.
+//
+// Returns:
+//   - `K`: The smallest key (zero value if the tree is empty).
+//   - `V`: Its stored value.
+//   - `bool`: Whether the tree was non-empty.
+func (bt *TTree[K, V]) Min() (K, V, bool) {
+	if nil == bt.root {
+		var key K
+		var value V
+		return key, value, false
+	}
+	node := bt.root
+	for nil != node.left {
+		node = node.left
+	}
+
+	return node.key, node.value, true
+} // Min()
+
+// `Search()` looks up the value stored under `aKey`.
+//
+// Parameters:
+//   - `aKey`: The key to look up.
+//
+// Returns:
+//   - `V`: The stored value (zero value if absent).
+//   - `bool`: Whether the key was present.
+func (bt *TTree[K, V]) Search(aKey K) (V, bool) {
+	node := bt.root
+	for nil != node {
+		switch {
+		case aKey < node.key:
+			node = node.left
+		case aKey > node.key:
+			node = node.right
+		default:
+			return node.value, true
+		}
+	}
+	var value V
+
+	return value, false
+} // Search()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `insert()` stores `aValue` under `aKey` in the subtree rooted at
+// `aNode`, returning the new subtree root and whether the key was
+// newly added.
+func insert[K cmp.Ordered, V any](aNode *tNode[K, V], aKey K, aValue V) (*tNode[K, V], bool) {
+	if nil == aNode {
+		return newNode(aKey, aValue), true
+	}
+
+	var added bool
+	switch {
+	case aKey < aNode.key:
+		aNode.left, added = insert(aNode.left, aKey, aValue)
+	case aKey > aNode.key:
+		aNode.right, added = insert(aNode.right, aKey, aValue)
+	default:
+		aNode.value = aValue
+		return aNode, false
+	}
+
+	return rebalance(aNode), added
+} // insert()
+
+// `remove()` deletes `aKey` from the subtree rooted at `aNode`,
+// returning the new subtree root and whether the key was present.
+func remove[K cmp.Ordered, V any](aNode *tNode[K, V], aKey K) (*tNode[K, V], bool) {
+	if nil == aNode {
+		return nil, false
+	}
+
+	var removed bool
+	switch {
+	case aKey < aNode.key:
+		aNode.left, removed = remove(aNode.left, aKey)
+	case aKey > aNode.key:
+		aNode.right, removed = remove(aNode.right, aKey)
+	default:
+		removed = true
+		switch {
+		case nil == aNode.left:
+			return aNode.right, true
+		case nil == aNode.right:
+			return aNode.left, true
+		default:
+			// two children: replace by the in-order successor
+			// (the leftmost node of the right subtree):
+			successor := aNode.right
+			for nil != successor.left {
+				successor = successor.left
+			}
+			aNode.key, aNode.value = successor.key, successor.value
+			aNode.right, _ = remove(aNode.right, successor.key)
+		}
+	}
+
+	return rebalance(aNode), removed
+} // remove()
+
+// `ascend()` walks the subtree rooted at `aNode` in ascending key
+// order, reporting whether the walk should continue.
+func ascend[K cmp.Ordered, V any](aNode *tNode[K, V], aVisit func(K, V) bool) bool {
+	if nil == aNode {
+		return true
+	}
+
+	return ascend(aNode.left, aVisit) &&
+		aVisit(aNode.key, aNode.value) &&
+		ascend(aNode.right, aVisit)
+} // ascend()
+
+// `ascendRange()` walks the subtree rooted at `aNode` in ascending
+// key order, visiting only keys in `[aLo, aHi)` and reporting
+// whether the scan should continue.
+func ascendRange[K cmp.Ordered, V any](aNode *tNode[K, V], aLo, aHi K, aVisit func(K, V) bool) bool {
+	if nil == aNode {
+		return true
+	}
+
+	if aLo < aNode.key {
+		if !ascendRange(aNode.left, aLo, aHi, aVisit) {
+			return false
+		}
+	}
+	if (aLo <= aNode.key) && (aNode.key < aHi) {
+		if !aVisit(aNode.key, aNode.value) {
+			return false
+		}
+	}
+	if aNode.key < aHi {
+		return ascendRange(aNode.right, aLo, aHi, aVisit)
+	}
+
+	return true
+} // ascendRange()
+
+// `buildBalanced()` builds a perfectly balanced subtree from the
+// sorted `aPairs`, returning its root.
+func buildBalanced[K cmp.Ordered, V any](aPairs []TPair[K, V]) *tNode[K, V] {
+	if 0 == len(aPairs) {
+		return nil
+	}
+	mid := len(aPairs) >> 1
+	node := newNode(aPairs[mid].Key, aPairs[mid].Value)
+	node.left = buildBalanced(aPairs[:mid])
+	node.right = buildBalanced(aPairs[mid+1:])
+	node.height = 1 + max(height(node.left), height(node.right))
+
+	return node
+} // buildBalanced()
+
+// `descend()` walks the subtree rooted at `aNode` in descending key
+// order, reporting whether the walk should continue.
+func descend[K cmp.Ordered, V any](aNode *tNode[K, V], aVisit func(K, V) bool) bool {
+	if nil == aNode {
+		return true
+	}
+
+	return descend(aNode.right, aVisit) &&
+		aVisit(aNode.key, aNode.value) &&
+		descend(aNode.left, aVisit)
+} // descend()
+
+// `height()` returns the height of the subtree rooted at `aNode`
+// (zero for a nil subtree).
+func height[K cmp.Ordered, V any](aNode *tNode[K, V]) int {
+	if nil == aNode {
+		return 0
+	}
+
+	return aNode.height
+} // height()
+
+// `newNode()` creates a leaf node holding `aKey`/`aValue`.
+func newNode[K cmp.Ordered, V any](aKey K, aValue V) *tNode[K, V] {
+	return &tNode[K, V]{key: aKey, value: aValue, height: 1}
+} // newNode()
+
+// `rebalance()` restores the AVL invariant (subtree heights differ
+// by at most one) at `aNode`, returning the new subtree root.
+func rebalance[K cmp.Ordered, V any](aNode *tNode[K, V]) *tNode[K, V] {
+	aNode.height = 1 + max(height(aNode.left), height(aNode.right))
+	balance := height(aNode.left) - height(aNode.right)
+	switch {
+	case 1 < balance: // left-heavy
+		if height(aNode.left.left) < height(aNode.left.right) {
+			aNode.left = rotateLeft(aNode.left)
+		}
+		return rotateRight(aNode)
+
+	case -1 > balance: // right-heavy
+		if height(aNode.right.right) < height(aNode.right.left) {
+			aNode.right = rotateRight(aNode.right)
+		}
+		return rotateLeft(aNode)
+	}
+
+	return aNode
+} // rebalance()
+
+// `rotateLeft()` rotates the subtree rooted at `aNode` to the left,
+// returning the new subtree root.
+func rotateLeft[K cmp.Ordered, V any](aNode *tNode[K, V]) *tNode[K, V] {
+	pivot := aNode.right
+	aNode.right = pivot.left
+	pivot.left = aNode
+	aNode.height = 1 + max(height(aNode.left), height(aNode.right))
+	pivot.height = 1 + max(height(pivot.left), height(pivot.right))
+
+	return pivot
+} // rotateLeft()
+
+// `rotateRight()` rotates the subtree rooted at `aNode` to the
+// right, returning the new subtree root.
+func rotateRight[K cmp.Ordered, V any](aNode *tNode[K, V]) *tNode[K, V] {
+	pivot := aNode.left
+	aNode.left = pivot.right
+	pivot.right = aNode
+	aNode.height = 1 + max(height(aNode.left), height(aNode.right))
+	pivot.height = 1 + max(height(pivot.left), height(pivot.right))
+
+	return pivot
+} // rotateRight()
+
+/* _EoF_ */
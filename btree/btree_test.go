@@ -0,0 +1,238 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"testing"
+)
+
+func TestInsertSearch(t *testing.T) {
+	bt := New[string, int]()
+	if value, ok := bt.Search(`absent`); ok || (0 != value) {
+		t.Errorf(`empty tree Search() = (%d, %v)`, value, ok)
+	}
+
+	for idx, key := range []string{`mango`, `apple`, `plum`, `fig`} {
+		if !bt.Insert(key, idx) {
+			t.Errorf(`Insert(%q) reported an update`, key)
+		}
+	}
+	if 4 != bt.Len() {
+		t.Errorf(`Len() = %d, want 4`, bt.Len())
+	}
+	if value, ok := bt.Search(`plum`); !ok || (2 != value) {
+		t.Errorf(`Search(plum) = (%d, %v), want (2, true)`, value, ok)
+	}
+
+	// inserting an existing key updates in place:
+	if bt.Insert(`plum`, 42) {
+		t.Error(`Insert(plum) reported a new key`)
+	}
+	if value, _ := bt.Search(`plum`); 42 != value {
+		t.Errorf(`Search(plum) after update = %d, want 42`, value)
+	}
+	if 4 != bt.Len() {
+		t.Errorf(`Len() after update = %d, want 4`, bt.Len())
+	}
+} // TestInsertSearch()
+
+func TestDelete(t *testing.T) {
+	bt := New[int, string]()
+	for _, key := range []int{50, 30, 70, 20, 40, 60, 80} {
+		bt.Insert(key, `v`)
+	}
+
+	if bt.Delete(99) {
+		t.Error(`Delete(99) removed an absent key`)
+	}
+	// leaf, one child, and the two-children case:
+	for _, key := range []int{20, 30, 50} {
+		if !bt.Delete(key) {
+			t.Errorf(`Delete(%d) didn't find the key`, key)
+		}
+		if _, ok := bt.Search(key); ok {
+			t.Errorf(`Search(%d) still found the deleted key`, key)
+		}
+	}
+	if 4 != bt.Len() {
+		t.Errorf(`Len() = %d, want 4`, bt.Len())
+	}
+	// the remaining keys must still be reachable:
+	for _, key := range []int{40, 60, 70, 80} {
+		if _, ok := bt.Search(key); !ok {
+			t.Errorf(`Search(%d) lost a remaining key`, key)
+		}
+	}
+} // TestDelete()
+
+func TestMinMax(t *testing.T) {
+	bt := New[int, string]()
+	if _, _, ok := bt.Min(); ok {
+		t.Error(`Min() on empty tree reported a key`)
+	}
+	if _, _, ok := bt.Max(); ok {
+		t.Error(`Max() on empty tree reported a key`)
+	}
+
+	for _, key := range []int{5, 1, 9, 3, 7} {
+		bt.Insert(key, `v`)
+	}
+	if key, _, ok := bt.Min(); !ok || (1 != key) {
+		t.Errorf(`Min() = (%d, %v), want (1, true)`, key, ok)
+	}
+	if key, _, ok := bt.Max(); !ok || (9 != key) {
+		t.Errorf(`Max() = (%d, %v), want (9, true)`, key, ok)
+	}
+} // TestMinMax()
+
+func TestIterators(t *testing.T) {
+	bt := New[int, string]()
+	for _, key := range []int{5, 1, 9, 3, 7} {
+		bt.Insert(key, `v`)
+	}
+
+	var up []int
+	bt.Ascend(func(aKey int, _ string) bool {
+		up = append(up, aKey)
+		return true
+	})
+	if want := []int{1, 3, 5, 7, 9}; !equalInts(up, want) {
+		t.Errorf(`Ascend() visited %v, want %v`, up, want)
+	}
+
+	var down []int
+	bt.Descend(func(aKey int, _ string) bool {
+		down = append(down, aKey)
+		return true
+	})
+	if want := []int{9, 7, 5, 3, 1}; !equalInts(down, want) {
+		t.Errorf(`Descend() visited %v, want %v`, down, want)
+	}
+
+	// the range scan is half-open ([lo, hi)):
+	var ranged []int
+	bt.AscendRange(3, 9, func(aKey int, _ string) bool {
+		ranged = append(ranged, aKey)
+		return true
+	})
+	if want := []int{3, 5, 7}; !equalInts(ranged, want) {
+		t.Errorf(`AscendRange(3, 9) visited %v, want %v`, ranged, want)
+	}
+
+	// returning false must stop the walk early:
+	visits := 0
+	bt.Ascend(func(int, string) bool {
+		visits++
+		return 2 > visits
+	})
+	if 2 != visits {
+		t.Errorf(`early stop after %d visits, want 2`, visits)
+	}
+} // TestIterators()
+
+// `equalInts()` reports whether both slices hold the same sequence.
+func equalInts(aList, aWant []int) bool {
+	if len(aList) != len(aWant) {
+		return false
+	}
+	for idx, key := range aList {
+		if key != aWant[idx] {
+			return false
+		}
+	}
+
+	return true
+} // equalInts()
+
+func TestBalance(t *testing.T) {
+	bt := New[int, struct{}]()
+	const count = 1 << 12 // sorted insertion: the AVL worst case
+
+	for key := 0; count > key; key++ {
+		bt.Insert(key, struct{}{})
+	}
+	if count != bt.Len() {
+		t.Fatalf(`Len() = %d, want %d`, bt.Len(), count)
+	}
+	// an AVL tree of n keys is at most ~1.44*log2(n) high; for
+	// 4096 keys that's 18 - a degenerated list would be 4096:
+	if maxHeight := 18; maxHeight < bt.root.height {
+		t.Errorf(`height = %d, want <= %d`, bt.root.height, maxHeight)
+	}
+	checkInvariant(t, bt.root)
+
+	// deletions must keep the tree balanced, too:
+	for key := 0; count/2 > key; key++ {
+		if !bt.Delete(key) {
+			t.Fatalf(`Delete(%d) didn't find the key`, key)
+		}
+	}
+	checkInvariant(t, bt.root)
+	for key := count / 2; count > key; key++ {
+		if _, ok := bt.Search(key); !ok {
+			t.Fatalf(`Search(%d) lost a remaining key`, key)
+		}
+	}
+} // TestBalance()
+
+func TestFromSorted(t *testing.T) {
+	pairs := make([]TPair[int, struct{}], 1<<10)
+	for idx := range pairs {
+		pairs[idx] = TPair[int, struct{}]{Key: idx}
+	}
+	bt := FromSorted(pairs)
+	if len(pairs) != bt.Len() {
+		t.Fatalf(`Len() = %d, want %d`, bt.Len(), len(pairs))
+	}
+	// 1024 keys fit a perfectly balanced tree of height 11:
+	if 11 != bt.root.height {
+		t.Errorf(`height = %d, want 11`, bt.root.height)
+	}
+	checkInvariant(t, bt.root)
+	for _, probe := range []int{0, 511, 512, 1023} {
+		if _, ok := bt.Search(probe); !ok {
+			t.Errorf(`Search(%d) lost a key`, probe)
+		}
+	}
+
+	// unsorted input must still yield a correct tree:
+	unsorted := []TPair[int, struct{}]{{Key: 9}, {Key: 3}, {Key: 7}}
+	bt = FromSorted(unsorted)
+	if 3 != bt.Len() {
+		t.Errorf(`Len() = %d, want 3`, bt.Len())
+	}
+	if _, ok := bt.Search(7); !ok {
+		t.Error(`Search(7) lost a key of the unsorted input`)
+	}
+
+	if bt = FromSorted[int, struct{}](nil); 0 != bt.Len() {
+		t.Errorf(`empty bulk load: Len() = %d, want 0`, bt.Len())
+	}
+} // TestFromSorted()
+
+// `checkInvariant()` verifies heights and balance factors of the
+// whole subtree rooted at `aNode`, returning its actual height.
+func checkInvariant(aTest *testing.T, aNode *tNode[int, struct{}]) int {
+	if nil == aNode {
+		return 0
+	}
+	left := checkInvariant(aTest, aNode.left)
+	right := checkInvariant(aTest, aNode.right)
+	if expected := 1 + max(left, right); expected != aNode.height {
+		aTest.Errorf(`node %d: height = %d, want %d`,
+			aNode.key, aNode.height, expected)
+	}
+	if balance := left - right; (1 < balance) || (-1 > balance) {
+		aTest.Errorf(`node %d: balance factor = %d`, aNode.key, balance)
+	}
+
+	return 1 + max(left, right)
+} // checkInvariant()
+
+/* _EoF_ */
@@ -0,0 +1,131 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/gob"
+	"encoding/json"
+)
+
+// `TPair` is one key/value pair, used by the serialization methods
+// and the `FromSorted()` bulk loader.
+type TPair[K cmp.Ordered, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// --------------------------------------------------------------------------
+// TTree methods:
+
+// `GobDecode()` restores the tree contents from `aData` (written by
+// `GobEncode()`), replacing whatever the tree held before.
+//
+// Parameters:
+//   - `aData`: The gob-encoded tree contents.
+//
+// Returns:
+//   - `error`: A decoding problem (`nil` on success).
+func (bt *TTree[K, V]) GobDecode(aData []byte) error {
+	var pairs []TPair[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(aData)).Decode(&pairs); nil != err {
+		return err
+	}
+	// the pairs were written in ascending key order, so the O(n)
+	// balanced bulk build applies:
+	*bt = *FromSorted(pairs)
+
+	return nil
+} // GobDecode()
+
+// `GobEncode()` serializes the tree contents as a gob-encoded list
+// of key/value pairs in ascending key order.
+//
+// Returns:
+//   - `[]byte`: The encoded contents.
+//   - `error`: An encoding problem (`nil` on success).
+func (bt *TTree[K, V]) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(bt.pairs()); nil != err {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+} // GobEncode()
+
+// `MarshalJSON()` serializes the tree contents as a JSON array of
+// `{"key": …, "value": …}` objects in ascending key order.
+//
+// Returns:
+//   - `[]byte`: The encoded contents.
+//   - `error`: An encoding problem (`nil` on success).
+func (bt *TTree[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(bt.pairs())
+} // MarshalJSON()
+
+// `UnmarshalJSON()` restores the tree contents from `aData`
+// (written by `MarshalJSON()`), replacing whatever the tree held
+// before.
+//
+// Parameters:
+//   - `aData`: The JSON-encoded tree contents.
+//
+// Returns:
+//   - `error`: A decoding problem (`nil` on success).
+func (bt *TTree[K, V]) UnmarshalJSON(aData []byte) error {
+	var pairs []TPair[K, V]
+	if err := json.Unmarshal(aData, &pairs); nil != err {
+		return err
+	}
+	// serialized contents are in ascending key order, so the O(n)
+	// balanced bulk build applies (hand-edited files fall back to
+	// pair-wise insertion inside `FromSorted()`):
+	*bt = *FromSorted(pairs)
+
+	return nil
+} // UnmarshalJSON()
+
+// `pairs()` collects the tree contents in ascending key order.
+func (bt *TTree[K, V]) pairs() []TPair[K, V] {
+	pairs := make([]TPair[K, V], 0, bt.size)
+	bt.Ascend(func(aKey K, aValue V) bool {
+		pairs = append(pairs, TPair[K, V]{Key: aKey, Value: aValue})
+		return true
+	})
+
+	return pairs
+} // pairs()
+
+// --------------------------------------------------------------------------
+// TSafeTree methods:
+
+// `MarshalJSON()` serializes the tree contents while holding the
+// read lock, see `TTree.MarshalJSON()`.
+func (st *TSafeTree[K, V]) MarshalJSON() ([]byte, error) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	return st.tree.MarshalJSON()
+} // MarshalJSON()
+
+// `UnmarshalJSON()` restores the tree contents while holding the
+// write lock, see `TTree.UnmarshalJSON()`.
+func (st *TSafeTree[K, V]) UnmarshalJSON(aData []byte) error {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	if nil == st.tree {
+		st.tree = New[K, V]()
+	}
+
+	return st.tree.UnmarshalJSON(aData)
+} // UnmarshalJSON()
+
+/* _EoF_ */
@@ -0,0 +1,90 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	bt := New[string, int]()
+	bt.Insert(`banned.example`, 2)
+	bt.Insert(`abuse.example`, 7)
+
+	data, err := json.Marshal(bt)
+	if nil != err {
+		t.Fatalf(`Marshal() failed: %v`, err)
+	}
+	// the pairs appear in ascending key order:
+	want := `[{"key":"abuse.example","value":7},` +
+		`{"key":"banned.example","value":2}]`
+	if want != string(data) {
+		t.Errorf(`Marshal() = %s, want %s`, data, want)
+	}
+
+	restored := New[string, int]()
+	restored.Insert(`stale.example`, 1) // replaced by the unmarshal
+	if err = json.Unmarshal(data, restored); nil != err {
+		t.Fatalf(`Unmarshal() failed: %v`, err)
+	}
+	if 2 != restored.Len() {
+		t.Errorf(`Len() = %d, want 2`, restored.Len())
+	}
+	if value, ok := restored.Search(`abuse.example`); !ok || (7 != value) {
+		t.Errorf(`Search(abuse.example) = (%d, %v)`, value, ok)
+	}
+	if _, ok := restored.Search(`stale.example`); ok {
+		t.Error(`Unmarshal() kept the previous contents`)
+	}
+} // TestMarshalJSON()
+
+func TestMarshalGob(t *testing.T) {
+	bt := New[int, string]()
+	for _, key := range []int{5, 1, 9} {
+		bt.Insert(key, `v`)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(bt); nil != err {
+		t.Fatalf(`gob Encode() failed: %v`, err)
+	}
+	restored := New[int, string]()
+	if err := gob.NewDecoder(&buf).Decode(restored); nil != err {
+		t.Fatalf(`gob Decode() failed: %v`, err)
+	}
+	if 3 != restored.Len() {
+		t.Errorf(`Len() = %d, want 3`, restored.Len())
+	}
+	for _, key := range []int{1, 5, 9} {
+		if _, ok := restored.Search(key); !ok {
+			t.Errorf(`Search(%d) lost the key`, key)
+		}
+	}
+} // TestMarshalGob()
+
+func TestSafeTreeMarshal(t *testing.T) {
+	st := NewSafe[string, int]()
+	st.Insert(`a`, 1)
+	data, err := json.Marshal(st)
+	if nil != err {
+		t.Fatalf(`Marshal() failed: %v`, err)
+	}
+	restored := NewSafe[string, int]()
+	if err = json.Unmarshal(data, restored); nil != err {
+		t.Fatalf(`Unmarshal() failed: %v`, err)
+	}
+	if value, ok := restored.Search(`a`); !ok || (1 != value) {
+		t.Errorf(`Search(a) = (%d, %v), want (1, true)`, value, ok)
+	}
+} // TestSafeTreeMarshal()
+
+/* _EoF_ */
@@ -0,0 +1,160 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"cmp"
+	"sync"
+)
+
+// `TSafeTree` wraps a `TTree` with a read/write mutex so it can be
+// read concurrently by many goroutines (e.g. request handlers)
+// while being updated by another (e.g. the config-reload path).
+//
+// The zero value is not usable; create instances with `NewSafe()`.
+type TSafeTree[K cmp.Ordered, V any] struct {
+	mtx  sync.RWMutex
+	tree *TTree[K, V]
+}
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewSafe()` creates an empty concurrency-safe tree mapping `K`
+// keys to `V` values.
+//
+// Returns:
+//   - `*TSafeTree[K, V]`: The new empty tree.
+func NewSafe[K cmp.Ordered, V any]() *TSafeTree[K, V] {
+	return &TSafeTree[K, V]{tree: New[K, V]()}
+} // NewSafe()
+
+// --------------------------------------------------------------------------
+// TSafeTree methods:
+
+// `Ascend()` calls `aVisit` for every key/value pair in ascending
+// key order; the read lock is held for the whole walk, so the
+// callback must not call mutating methods of the same tree.
+//
+// Parameters:
+//   - `aVisit`: The callback; return `false` to stop the walk.
+func (st *TSafeTree[K, V]) Ascend(aVisit func(aKey K, aValue V) bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	st.tree.Ascend(aVisit)
+} // Ascend()
+
+// `AscendRange()` calls `aVisit` in ascending key order for every
+// pair whose key satisfies `aLo <= key < aHi`; the read lock is
+// held for the whole scan.
+//
+// Parameters:
+//   - `aLo`: The inclusive lower bound.
+//   - `aHi`: The exclusive upper bound.
+//   - `aVisit`: The callback; return `false` to stop the scan.
+func (st *TSafeTree[K, V]) AscendRange(aLo, aHi K, aVisit func(aKey K, aValue V) bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	st.tree.AscendRange(aLo, aHi, aVisit)
+} // AscendRange()
+
+// `Descend()` calls `aVisit` for every key/value pair in descending
+// key order; the read lock is held for the whole walk.
+//
+// Parameters:
+//   - `aVisit`: The callback; return `false` to stop the walk.
+func (st *TSafeTree[K, V]) Descend(aVisit func(aKey K, aValue V) bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	st.tree.Descend(aVisit)
+} // Descend()
+
+// `Delete()` removes `aKey` from the tree.
+//
+// Parameters:
+//   - `aKey`: The key to remove.
+//
+// Returns:
+//   - `bool`: Whether the key was present.
+func (st *TSafeTree[K, V]) Delete(aKey K) bool {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	return st.tree.Delete(aKey)
+} // Delete()
+
+// `Insert()` stores `aValue` under `aKey`, replacing any value
+// already stored under that key.
+//
+// Parameters:
+//   - `aKey`: The key to store under.
+//   - `aValue`: The value to store.
+//
+// Returns:
+//   - `bool`: Whether the key was newly added (`false` on update).
+func (st *TSafeTree[K, V]) Insert(aKey K, aValue V) bool {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	return st.tree.Insert(aKey, aValue)
+} // Insert()
+
+// `Len()` returns the number of keys currently stored.
+func (st *TSafeTree[K, V]) Len() int {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	return st.tree.Len()
+} // Len()
+
+// `Max()` returns the largest key and its value.
+//
+// Returns:
+//   - `K`: The largest key (zero value if the tree is empty).
+//   - `V`: Its stored value.
+//   - `bool`: Whether the tree was non-empty.
+func (st *TSafeTree[K, V]) Max() (K, V, bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	return st.tree.Max()
+} // Max()
+
+// `Min()` returns the smallest key and its value.
+//
+// Returns:
+//   - `K`: The smallest key (zero value if the tree is empty).
+//   - `V`: Its stored value.
+//   - `bool`: Whether the tree was non-empty.
+func (st *TSafeTree[K, V]) Min() (K, V, bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	return st.tree.Min()
+} // Min()
+
+// `Search()` looks up the value stored under `aKey`.
+//
+// Parameters:
+//   - `aKey`: The key to look up.
+//
+// Returns:
+//   - `V`: The stored value (zero value if absent).
+//   - `bool`: Whether the key was present.
+func (st *TSafeTree[K, V]) Search(aKey K) (V, bool) {
+	st.mtx.RLock()
+	defer st.mtx.RUnlock()
+
+	return st.tree.Search(aKey)
+} // Search()
+
+/* _EoF_ */
@@ -0,0 +1,122 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package btree
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSafeTree(t *testing.T) {
+	st := NewSafe[int, int]()
+
+	// concurrent writers and readers must not race each other:
+	var wg sync.WaitGroup
+	for worker := 0; 4 > worker; worker++ {
+		wg.Add(1)
+		go func(aBase int) {
+			defer wg.Done()
+			for idx := 0; 256 > idx; idx++ {
+				key := (aBase << 10) + idx
+				st.Insert(key, key)
+				if value, ok := st.Search(key); !ok || (key != value) {
+					t.Errorf(`Search(%d) = (%d, %v)`, key, value, ok)
+				}
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	if 1024 != st.Len() {
+		t.Errorf(`Len() = %d, want 1024`, st.Len())
+	}
+	if key, _, ok := st.Min(); !ok || (0 != key) {
+		t.Errorf(`Min() = (%d, %v), want (0, true)`, key, ok)
+	}
+	if key, _, ok := st.Max(); !ok || (((3 << 10) + 255) != key) {
+		t.Errorf(`Max() = (%d, %v)`, key, ok)
+	}
+	if !st.Delete(0) {
+		t.Error(`Delete(0) didn't find the key`)
+	}
+
+	count := 0
+	st.Ascend(func(int, int) bool {
+		count++
+		return true
+	})
+	if 1023 != count {
+		t.Errorf(`Ascend() visited %d keys, want 1023`, count)
+	}
+} // TestSafeTree()
+
+const benchKeys = 1 << 14
+
+// `benchTree()` returns a pre-filled safe tree for the benchmarks.
+func benchTree() *TSafeTree[string, int] {
+	st := NewSafe[string, int]()
+	for idx := 0; benchKeys > idx; idx++ {
+		st.Insert(strconv.Itoa(idx), idx)
+	}
+
+	return st
+} // benchTree()
+
+// `benchMap()` returns a pre-filled sync.Map for the benchmarks.
+func benchMap() *sync.Map {
+	var sm sync.Map
+	for idx := 0; benchKeys > idx; idx++ {
+		sm.Store(strconv.Itoa(idx), idx)
+	}
+
+	return &sm
+} // benchMap()
+
+func BenchmarkSafeTreeRead(b *testing.B) {
+	st := benchTree()
+	b.ResetTimer()
+	b.RunParallel(func(aPB *testing.PB) {
+		idx := 0
+		for aPB.Next() {
+			st.Search(strconv.Itoa(idx % benchKeys))
+			idx++
+		}
+	})
+} // BenchmarkSafeTreeRead()
+
+func BenchmarkSyncMapRead(b *testing.B) {
+	sm := benchMap()
+	b.ResetTimer()
+	b.RunParallel(func(aPB *testing.PB) {
+		idx := 0
+		for aPB.Next() {
+			sm.Load(strconv.Itoa(idx % benchKeys))
+			idx++
+		}
+	})
+} // BenchmarkSyncMapRead()
+
+func BenchmarkSafeTreeWrite(b *testing.B) {
+	st := benchTree()
+	b.ResetTimer()
+	for idx := 0; b.N > idx; idx++ {
+		st.Insert(strconv.Itoa(idx%benchKeys), idx)
+	}
+} // BenchmarkSafeTreeWrite()
+
+func BenchmarkSyncMapWrite(b *testing.B) {
+	sm := benchMap()
+	b.ResetTimer()
+	for idx := 0; b.N > idx; idx++ {
+		sm.Store(strconv.Itoa(idx%benchKeys), idx)
+	}
+} // BenchmarkSyncMapWrite()
+
+/* _EoF_ */
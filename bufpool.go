@@ -0,0 +1,50 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"sync"
+)
+
+type (
+	// `tBufferPool` recycles the copy buffers used by the reverse
+	// proxies when streaming response bodies, implementing
+	// `httputil.BufferPool` to relieve GC pressure under load.
+	tBufferPool struct {
+		pool sync.Pool
+	}
+)
+
+// `copyBufferSize` is the size of one pooled copy buffer; it
+// matches the default used by `io.Copy()`.
+const copyBufferSize = 32 << 10
+
+// `gBufferPool` is the shared buffer pool of all reverse proxies.
+var gBufferPool = &tBufferPool{
+	pool: sync.Pool{
+		New: func() any {
+			return make([]byte, copyBufferSize)
+		},
+	},
+}
+
+// --------------------------------------------------------------------------
+// tBufferPool methods:
+
+// `Get()` returns a copy buffer from the pool.
+func (bp *tBufferPool) Get() []byte {
+	return bp.pool.Get().([]byte)
+} // Get()
+
+// `Put()` hands a used copy buffer back to the pool.
+func (bp *tBufferPool) Put(aBuf []byte) {
+	bp.pool.Put(aBuf) //lint:ignore SA6002 - slice header allocation is negligible
+} // Put()
+
+/* _EoF_ */
@@ -0,0 +1,54 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBufferPool(t *testing.T) {
+	buf := gBufferPool.Get()
+	if copyBufferSize != len(buf) {
+		t.Errorf(`Get() buffer size = %d, want %d`, len(buf), copyBufferSize)
+	}
+	gBufferPool.Put(buf)
+} // TestBufferPool()
+
+// `BenchmarkProxyCopy` measures the proxy's body copy path with the
+// pooled buffers; run with `-benchmem` to see allocations/op.
+func BenchmarkProxyCopy(b *testing.B) {
+	payload := bytes.Repeat([]byte(`x`), 64<<10)
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write(payload)
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler(WithRoutes(map[string]string{
+		`bench.example.org`: backend.URL,
+	}))
+	if err := ph.BuildRoutes(); nil != err {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for idx := 0; idx < b.N; idx++ {
+		req := httptest.NewRequest(http.MethodGet,
+			`http://bench.example.org/`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		if http.StatusOK != rec.Code {
+			b.Fatalf(`status = %d`, rec.Code)
+		}
+	}
+} // BenchmarkProxyCopy()
+
+/* _EoF_ */
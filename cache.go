@@ -0,0 +1,548 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// `TCacheConfig` enables a small in-memory response cache for a
+	// virtual host, so frequently requested pages don't hit the
+	// backend on every single request.
+	TCacheConfig struct {
+		// MaxEntries caps the number of cached responses per host
+		// (default 1024); the oldest entry is evicted when full.
+		MaxEntries int `json:"maxEntries,omitempty"`
+
+		// MaxBodyKB is the largest response body cached, in KiB
+		// (default 256).
+		MaxBodyKB int `json:"maxBodyKB,omitempty"`
+
+		// DefaultTTLSec is the cache lifetime of responses without
+		// their own `max-age`; zero caches only responses that
+		// explicitly allow it via `Cache-Control`.
+		DefaultTTLSec int `json:"defaultTTLSec,omitempty"`
+
+		// StaleWhileRevalidateSec serves entries this long past
+		// their lifetime while a background refresh runs (RFC 5861),
+		// unless the backend states its own
+		// `stale-while-revalidate` value.
+		StaleWhileRevalidateSec int `json:"staleWhileRevalidateSec,omitempty"`
+
+		// StaleIfErrorSec serves entries this long past their
+		// lifetime when the backend fails (RFC 5861), unless the
+		// backend states its own `stale-if-error` value.
+		StaleIfErrorSec int `json:"staleIfErrorSec,omitempty"`
+	}
+
+	// `tCacheEntry` is one cached response.
+	tCacheEntry struct {
+		status       int
+		header       http.Header
+		body         []byte
+		keys         []string          // surrogate keys set by the backend
+		vary         map[string]string // selecting request headers (RFC 9111 §4.1)
+		stored       time.Time
+		ttl          time.Duration
+		swr          time.Duration // stale-while-revalidate window
+		sie          time.Duration // stale-if-error window
+		revalidating atomic.Bool   // a background refresh is running
+	}
+
+	// `tResponseCache` is the response store of one virtual host.
+	tResponseCache struct {
+		mtx     sync.Mutex
+		conf    *TCacheConfig
+		entries map[string]*tCacheEntry // URL path?query -> response
+	}
+)
+
+// `surrogateKeyHeader` names the response header backends use to tag
+// cached entries for targeted purging.
+const surrogateKeyHeader = `Surrogate-Key`
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newResponseCache()` creates an empty cache honouring `aConfig`,
+// filling in the usual defaults.
+func newResponseCache(aConfig *TCacheConfig) *tResponseCache {
+	if 0 >= aConfig.MaxEntries {
+		aConfig.MaxEntries = 1024
+	}
+	if 0 >= aConfig.MaxBodyKB {
+		aConfig.MaxBodyKB = 256
+	}
+
+	return &tResponseCache{
+		conf:    aConfig,
+		entries: make(map[string]*tCacheEntry),
+	}
+} // newResponseCache()
+
+// --------------------------------------------------------------------------
+// tCacheEntry methods:
+
+// `fresh()` reports whether the entry is still within its lifetime.
+func (ce *tCacheEntry) fresh(aNow time.Time) bool {
+	return aNow.Sub(ce.stored) < ce.ttl
+} // fresh()
+
+// `staleOnError()` reports whether the stale entry may still be
+// served in place of a failing backend (RFC 5861 `stale-if-error`).
+func (ce *tCacheEntry) staleOnError(aNow time.Time) bool {
+	return aNow.Sub(ce.stored) < (ce.ttl + ce.sie)
+} // staleOnError()
+
+// `staleOK()` reports whether the stale entry may still be served
+// while being refreshed (RFC 5861 `stale-while-revalidate`).
+func (ce *tCacheEntry) staleOK(aNow time.Time) bool {
+	return aNow.Sub(ce.stored) < (ce.ttl + ce.swr)
+} // staleOK()
+
+// `serve()` writes the cached entry to `aWriter`, omitting the body
+// for `HEAD` requests.
+func (ce *tCacheEntry) serve(aWriter http.ResponseWriter,
+	aRequest *http.Request, aStale bool) {
+	header := aWriter.Header()
+	for name, values := range ce.header {
+		header[name] = values
+	}
+	if aStale {
+		header.Set(`X-Cache`, `STALE`)
+	} else {
+		header.Set(`X-Cache`, `HIT`)
+	}
+	header.Set(`Age`, strconv.Itoa(
+		int(time.Since(ce.stored)/time.Second)))
+	if conditionalHit(aRequest, ce.header.Get(`ETag`),
+		ce.header.Get(`Last-Modified`)) {
+		aWriter.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	// single Range requests (media seeking, resumable downloads)
+	// are answered straight from the cached body:
+	header.Set(`Accept-Ranges`, `bytes`)
+	status, body := ce.status, ce.body
+	if spec := aRequest.Header.Get(`Range`); ("" != spec) &&
+		(http.StatusOK == ce.status) {
+		size := int64(len(ce.body))
+		start, length, ok := parseByteRange(spec, size)
+		switch {
+		case ok:
+			header.Set(`Content-Range`, fmt.Sprintf(
+				"bytes %d-%d/%d", start, start+length-1, size))
+			status, body = http.StatusPartialContent,
+				ce.body[start:start+length]
+
+		case strings.HasPrefix(spec, `bytes=`) &&
+			!strings.Contains(spec, `,`):
+			header.Set(`Content-Range`,
+				fmt.Sprintf("bytes */%d", size))
+			aWriter.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		} // multi-range and malformed specs: serve the full body
+	}
+	header.Set(`Content-Length`, strconv.Itoa(len(body)))
+	aWriter.WriteHeader(status)
+	if http.MethodHead != aRequest.Method {
+		_, _ = aWriter.Write(body)
+	}
+} // serve()
+
+// --------------------------------------------------------------------------
+// tResponseCache methods:
+
+// `get()` returns the cached response of `aKey` — possibly a stale
+// one still usable under its RFC 5861 windows — or nil; `aRequest`
+// must match the entry's `Vary` selection (RFC 9111 §4.1), so e.g. a
+// gzipped variant is never replayed to a client that can't decode it.
+func (rc *tResponseCache) get(aKey string, aRequest *http.Request) *tCacheEntry {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	entry, ok := rc.entries[aKey]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	if !entry.fresh(now) && !entry.staleOK(now) && !entry.staleOnError(now) {
+		delete(rc.entries, aKey)
+		return nil
+	}
+	for name, value := range entry.vary {
+		if value != aRequest.Header.Get(name) {
+			return nil // another variant; the entry stays for its own
+		}
+	}
+
+	return entry
+} // get()
+
+// `maybeStore()` caches `aResponse` if both it and its request are
+// cacheable, leaving the response body readable for the client.
+func (rc *tResponseCache) maybeStore(aResponse *http.Response) {
+	if !cacheableRequest(aResponse.Request) ||
+		(http.StatusOK != aResponse.StatusCode) {
+		return
+	}
+	if 0 < len(aResponse.Header.Values(`Set-Cookie`)) {
+		return // per-visitor state must never be replayed to others
+	}
+	// RFC 9111 §4.1: a response may only be reused for requests
+	// matching its `Vary` selection, so the storing request's values
+	// are kept with the entry and checked again in `get()`:
+	vary := make(map[string]string)
+	for _, names := range aResponse.Header.Values(`Vary`) {
+		for _, name := range strings.Split(names, `,`) {
+			if name = strings.TrimSpace(name); "" == name {
+				continue
+			}
+			if `*` == name {
+				return // never reusable for another request
+			}
+			name = http.CanonicalHeaderKey(name)
+			vary[name] = aResponse.Request.Header.Get(name)
+		}
+	}
+	ttl, swr, sie := parseCacheControl(aResponse.Header,
+		time.Duration(rc.conf.DefaultTTLSec)*time.Second,
+		time.Duration(rc.conf.StaleWhileRevalidateSec)*time.Second,
+		time.Duration(rc.conf.StaleIfErrorSec)*time.Second)
+	if 0 >= ttl {
+		return
+	}
+
+	limit := int64(rc.conf.MaxBodyKB) << 10
+	body, err := io.ReadAll(io.LimitReader(aResponse.Body, limit+1))
+	remainder := aResponse.Body
+	aResponse.Body = &tReplayReader{
+		Reader: io.MultiReader(bytes.NewReader(body), remainder),
+		closer: remainder,
+	}
+	if (nil != err) || (int64(len(body)) > limit) {
+		return // too large (or truncated): pass through uncached
+	}
+
+	// make every cached response revalidatable, so later
+	// `If-None-Match`/`If-Modified-Since` requests can be answered
+	// with `304` without contacting the backend:
+	if "" == aResponse.Header.Get(`ETag`) {
+		hash := sha256.Sum256(body)
+		aResponse.Header.Set(`ETag`,
+			`"`+hex.EncodeToString(hash[:])+`"`)
+	}
+	if "" == aResponse.Header.Get(`Last-Modified`) {
+		aResponse.Header.Set(`Last-Modified`,
+			time.Now().UTC().Format(http.TimeFormat))
+	}
+
+	entry := &tCacheEntry{
+		status: aResponse.StatusCode,
+		header: aResponse.Header.Clone(),
+		body:   body,
+		keys:   aResponse.Header.Values(surrogateKeyHeader),
+		vary:   vary,
+		stored: time.Now(),
+		ttl:    ttl,
+		swr:    swr,
+		sie:    sie,
+	}
+
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+	if (len(rc.entries) >= rc.conf.MaxEntries) &&
+		(nil == rc.entries[cacheKey(aResponse.Request)]) {
+		rc.evictOldest()
+	}
+	rc.entries[cacheKey(aResponse.Request)] = entry
+} // maybeStore()
+
+// `purge()` drops all entries matching `aPrefix` (a URL path prefix)
+// or tagged with the surrogate key `aKey`; with both empty the whole
+// cache is flushed.
+//
+// Returns:
+//   - `int`: The number of entries dropped.
+func (rc *tResponseCache) purge(aPrefix, aKey string) int {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	purged := 0
+	for path, entry := range rc.entries {
+		match := ("" == aPrefix) && ("" == aKey)
+		if ("" != aPrefix) && strings.HasPrefix(path, aPrefix) {
+			match = true
+		}
+		if ("" != aKey) && !match {
+			for _, key := range entry.keys {
+				if key == aKey {
+					match = true
+					break
+				}
+			}
+		}
+		if match {
+			delete(rc.entries, path)
+			purged++
+		}
+	}
+
+	return purged
+} // purge()
+
+// `evictOldest()` drops the oldest entry; the caller must hold
+// `rc.mtx`.
+func (rc *tResponseCache) evictOldest() {
+	var (
+		oldest    time.Time
+		oldestKey string
+	)
+	for path, entry := range rc.entries {
+		if ("" == oldestKey) || entry.stored.Before(oldest) {
+			oldest, oldestKey = entry.stored, path
+		}
+	}
+	if "" != oldestKey {
+		delete(rc.entries, oldestKey)
+	}
+} // evictOldest()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `cacheKey()` returns the cache key of `aRequest`: its URL path
+// plus query string.
+func cacheKey(aRequest *http.Request) string {
+	return aRequest.URL.RequestURI()
+} // cacheKey()
+
+// `parseByteRange()` parses a single-range `bytes=` specification
+// against a body of `aSize` bytes.
+//
+// Returns:
+//   - `int64`: The first byte position of the range.
+//   - `int64`: The length of the range.
+//   - `bool`: Whether the specification is valid and satisfiable.
+func parseByteRange(aSpec string, aSize int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(aSpec, `bytes=`)
+	if !ok || strings.Contains(spec, `,`) {
+		return 0, 0, false // multi-range isn't supported
+	}
+	from, to, ok := strings.Cut(spec, `-`)
+	if !ok {
+		return 0, 0, false
+	}
+
+	if "" == from { // suffix range: the last `to` bytes
+		length, err := strconv.ParseInt(to, 10, 64)
+		if (nil != err) || (0 >= length) {
+			return 0, 0, false
+		}
+		if length > aSize {
+			length = aSize
+		}
+		return aSize - length, length, 0 < length
+	}
+
+	start, err := strconv.ParseInt(from, 10, 64)
+	if (nil != err) || (0 > start) || (start >= aSize) {
+		return 0, 0, false
+	}
+	end := aSize - 1
+	if "" != to {
+		if end, err = strconv.ParseInt(to, 10, 64); nil != err {
+			return 0, 0, false
+		}
+		if end >= aSize {
+			end = aSize - 1
+		}
+	}
+	if end < start {
+		return 0, 0, false
+	}
+
+	return start, end - start + 1, true
+} // parseByteRange()
+
+// `conditionalHit()` reports whether `aRequest`'s conditional
+// headers match the cached entry, i.e. whether a `304 Not Modified`
+// answer suffices.
+func conditionalHit(aRequest *http.Request, aETag, aLastModified string) bool {
+	if inm := aRequest.Header.Get(`If-None-Match`); "" != inm {
+		if `*` == inm {
+			return true
+		}
+		for _, tag := range strings.Split(inm, `,`) {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), `W/`)
+			if ("" != aETag) && (tag == aETag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	ims := aRequest.Header.Get(`If-Modified-Since`)
+	if ("" == ims) || ("" == aLastModified) {
+		return false
+	}
+	since, err := http.ParseTime(ims)
+	if nil != err {
+		return false
+	}
+	modified, err := http.ParseTime(aLastModified)
+
+	return (nil == err) && !modified.After(since)
+} // conditionalHit()
+
+// `cacheableRequest()` reports whether `aRequest` may be answered
+// from (or stored into) the cache: an anonymous `GET`/`HEAD`.
+func cacheableRequest(aRequest *http.Request) bool {
+	if nil == aRequest {
+		return false
+	}
+	if (http.MethodGet != aRequest.Method) &&
+		(http.MethodHead != aRequest.Method) {
+		return false
+	}
+
+	return ("" == aRequest.Header.Get(`Authorization`)) &&
+		("" == aRequest.Header.Get(`Cookie`))
+} // cacheableRequest()
+
+// `parseCacheControl()` derives the cache lifetime and the RFC 5861
+// staleness windows from a response's `Cache-Control` header,
+// falling back to the given defaults for directives the backend
+// doesn't state; uncacheable responses yield a zero lifetime.
+func parseCacheControl(aHeader http.Header,
+	aTTL, aSWR, aSIE time.Duration) (rTTL, rSWR, rSIE time.Duration) {
+	rTTL, rSWR, rSIE = aTTL, aSWR, aSIE
+	for _, directive := range strings.Split(
+		strings.ToLower(aHeader.Get(`Cache-Control`)), `,`) {
+		directive = strings.TrimSpace(directive)
+		switch {
+		case (`no-store` == directive) || (`no-cache` == directive) ||
+			(`private` == directive):
+			return 0, 0, 0
+
+		case strings.HasPrefix(directive, `max-age=`):
+			rTTL = directiveSeconds(directive, rTTL)
+
+		case strings.HasPrefix(directive, `stale-while-revalidate=`):
+			rSWR = directiveSeconds(directive, rSWR)
+
+		case strings.HasPrefix(directive, `stale-if-error=`):
+			rSIE = directiveSeconds(directive, rSIE)
+		}
+	}
+
+	return rTTL, rSWR, rSIE
+} // parseCacheControl()
+
+// `directiveSeconds()` parses the numeric value of one
+// `Cache-Control` directive, keeping `aFallback` if it's malformed.
+func directiveSeconds(aDirective string, aFallback time.Duration) time.Duration {
+	_, value, _ := strings.Cut(aDirective, `=`)
+	if secs, err := strconv.Atoi(value); nil == err {
+		return time.Duration(secs) * time.Second
+	}
+
+	return aFallback
+} // directiveSeconds()
+
+// `tDiscardWriter` swallows a response; it serves as the sink of
+// background cache revalidations, where the refreshed response is
+// captured by the proxy's `ModifyResponse` hook instead.
+type tDiscardWriter struct {
+	header http.Header
+}
+
+// `Header()` returns the (discarded) response headers.
+func (dw *tDiscardWriter) Header() http.Header {
+	return dw.header
+} // Header()
+
+// `Write()` discards `aData`.
+func (dw *tDiscardWriter) Write(aData []byte) (int, error) {
+	return len(aData), nil
+} // Write()
+
+// `WriteHeader()` discards the status code.
+func (dw *tDiscardWriter) WriteHeader(int) {
+	// nothing to do
+} // WriteHeader()
+
+// `tStaleWriter` forwards a proxied response unless the backend
+// fails, in which case it serves the stale cache entry instead
+// (RFC 5861 `stale-if-error`).
+type tStaleWriter struct {
+	http.ResponseWriter
+	entry   *tCacheEntry
+	request *http.Request
+	served  bool // the stale copy was sent; discard everything else
+	wrote   bool // the response headers were already decided
+}
+
+// `Unwrap()` exposes the underlying writer for
+// `http.ResponseController`.
+func (sw *tStaleWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+} // Unwrap()
+
+// `Write()` forwards `aData` unless the stale copy took over.
+func (sw *tStaleWriter) Write(aData []byte) (int, error) {
+	if !sw.wrote {
+		sw.WriteHeader(http.StatusOK)
+	}
+	if sw.served {
+		return len(aData), nil
+	}
+
+	return sw.ResponseWriter.Write(aData)
+} // Write()
+
+// `WriteHeader()` forwards the status code, or switches to the
+// stale cache entry on a backend failure.
+func (sw *tStaleWriter) WriteHeader(aStatus int) {
+	if sw.wrote {
+		return
+	}
+	sw.wrote = true
+	if 500 <= aStatus {
+		sw.served = true
+		sw.entry.serve(sw.ResponseWriter, sw.request, true)
+		return
+	}
+	sw.ResponseWriter.WriteHeader(aStatus)
+} // WriteHeader()
+
+// `tReplayReader` re-assembles a partially consumed response body.
+type tReplayReader struct {
+	io.Reader
+	closer io.Closer
+}
+
+// `Close()` closes the original response body.
+func (rr *tReplayReader) Close() error {
+	return rr.closer.Close()
+} // Close()
+
+/* _EoF_ */
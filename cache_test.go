@@ -0,0 +1,237 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheServing(t *testing.T) {
+	var hits atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			hits.Add(1)
+			aWriter.Header().Set(`Cache-Control`, `max-age=60`)
+			aWriter.Header().Set(surrogateKeyHeader, `page-1`)
+			_, _ = aWriter.Write([]byte(`cached answer`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: backend.URL,
+		Cache:  &TCacheConfig{},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/page`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(); `cached answer` != rec.Body.String() {
+		t.Fatalf(`first response = %q`, rec.Body.String())
+	}
+	rec := get()
+	if `cached answer` != rec.Body.String() {
+		t.Fatalf(`second response = %q`, rec.Body.String())
+	}
+	if `HIT` != rec.Header().Get(`X-Cache`) {
+		t.Error(`second response wasn't served from the cache`)
+	}
+	if 1 != hits.Load() {
+		t.Errorf(`backend hits = %d, want 1`, hits.Load())
+	}
+
+	// the cached entry carries a generated strong ETag answering
+	// conditional requests without the backend:
+	etag := rec.Header().Get(`ETag`)
+	if "" == etag {
+		t.Fatal(`cached response lacks an ETag`)
+	}
+	req := httptest.NewRequest(http.MethodGet, `http://example.org/page`, nil)
+	req.Header.Set(`If-None-Match`, etag)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusNotModified != rec.Code {
+		t.Errorf(`conditional request status = %d, want 304`, rec.Code)
+	}
+
+	if purged := ph.PurgeCache(`example.org`, ``, `page-1`); 1 != purged {
+		t.Errorf(`PurgeCache() = %d, want 1`, purged)
+	}
+	_ = get()
+	if 2 != hits.Load() {
+		t.Errorf(`backend hits after purge = %d, want 2`, hits.Load())
+	}
+} // TestResponseCacheServing()
+
+func TestResponseCacheVary(t *testing.T) {
+	var hits atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			hits.Add(1)
+			aWriter.Header().Set(`Cache-Control`, `max-age=60`)
+			aWriter.Header().Set(`Vary`, `Accept-Language`)
+			if `de` == aRequest.Header.Get(`Accept-Language`) {
+				_, _ = aWriter.Write([]byte(`german body`))
+				return
+			}
+			_, _ = aWriter.Write([]byte(`plain body`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: backend.URL,
+		Cache:  &TCacheConfig{},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	get := func(aLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/page`, nil)
+		if "" != aLanguage {
+			req.Header.Set(`Accept-Language`, aLanguage)
+		}
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := get(`de`); `german body` != rec.Body.String() {
+		t.Fatalf(`first response = %q`, rec.Body.String())
+	}
+	// the cached variant must not reach a client that asked for
+	// something else (RFC 9111 §4.1):
+	rec := get(``)
+	if `plain body` != rec.Body.String() {
+		t.Fatalf(`plain client got %q`, rec.Body.String())
+	}
+	if 2 != hits.Load() {
+		t.Errorf(`backend hits = %d, want 2`, hits.Load())
+	}
+	// while a matching client is still served from the cache:
+	if rec = get(``); `HIT` != rec.Header().Get(`X-Cache`) {
+		t.Error(`matching variant wasn't served from the cache`)
+	}
+	if 2 != hits.Load() {
+		t.Errorf(`backend hits = %d, want 2`, hits.Load())
+	}
+} // TestResponseCacheVary()
+
+func TestResponseCacheSetCookie(t *testing.T) {
+	var hits atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			aWriter.Header().Set(`Cache-Control`, `max-age=60`)
+			http.SetCookie(aWriter, &http.Cookie{
+				Name: `session`, Value: strconv.Itoa(int(hits.Add(1)))})
+			_, _ = aWriter.Write([]byte(`personalised`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: backend.URL,
+		Cache:  &TCacheConfig{},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// a `Set-Cookie` response must never be cached - replaying it
+	// would hand the first visitor's session to everyone else:
+	first := get().Header().Get(`Set-Cookie`)
+	second := get()
+	if first == second.Header().Get(`Set-Cookie`) {
+		t.Error(`a visitor received another visitor's cookie`)
+	}
+	if "" != second.Header().Get(`X-Cache`) {
+		t.Error(`Set-Cookie response was served from the cache`)
+	}
+	if 2 != hits.Load() {
+		t.Errorf(`backend hits = %d, want 2`, hits.Load())
+	}
+} // TestResponseCacheSetCookie()
+
+func TestParseByteRange(t *testing.T) {
+	for _, tc := range []struct {
+		spec          string
+		start, length int64
+		ok            bool
+	}{
+		{`bytes=0-4`, 0, 5, true},
+		{`bytes=5-`, 5, 95, true},
+		{`bytes=-10`, 90, 10, true},
+		{`bytes=0-199`, 0, 100, true},
+		{`bytes=100-`, 0, 0, false},
+		{`bytes=4-2`, 0, 0, false},
+		{`bytes=0-4,10-14`, 0, 0, false},
+		{`lines=0-4`, 0, 0, false},
+	} {
+		start, length, ok := parseByteRange(tc.spec, 100)
+		if (tc.start != start) || (tc.length != length) || (tc.ok != ok) {
+			t.Errorf("parseByteRange(%q) = %d, %d, %v, want %d, %d, %v",
+				tc.spec, start, length, ok, tc.start, tc.length, tc.ok)
+		}
+	}
+} // TestParseByteRange()
+
+func TestResponseCacheStale(t *testing.T) {
+	cache := newResponseCache(&TCacheConfig{})
+	entry := &tCacheEntry{
+		stored: time.Now().Add(-2 * time.Second),
+		ttl:    time.Second,
+		swr:    10 * time.Second,
+		sie:    30 * time.Second,
+	}
+	now := time.Now()
+	if entry.fresh(now) {
+		t.Error(`fresh() = true past the lifetime`)
+	}
+	if !entry.staleOK(now) {
+		t.Error(`staleOK() = false inside the revalidate window`)
+	}
+	if !entry.staleOnError(now) {
+		t.Error(`staleOnError() = false inside the error window`)
+	}
+	cache.entries[`/page`] = entry
+	req := httptest.NewRequest(http.MethodGet, `http://example.org/page`, nil)
+	if nil == cache.get(`/page`, req) {
+		t.Error(`get() dropped a stale but still usable entry`)
+	}
+
+	entry.swr, entry.sie = 0, 0
+	if nil != cache.get(`/page`, req) {
+		t.Error(`get() returned an entry past all windows`)
+	}
+
+	header := http.Header{`Cache-Control`: []string{
+		`max-age=60, stale-while-revalidate=30, stale-if-error=120`}}
+	ttl, swr, sie := parseCacheControl(header, 0, 0, 0)
+	if (time.Minute != ttl) || (30*time.Second != swr) ||
+		(2*time.Minute != sie) {
+		t.Errorf(`parseCacheControl() = %v, %v, %v`, ttl, swr, sie)
+	}
+} // TestResponseCacheStale()
+
+/* _EoF_ */
@@ -0,0 +1,250 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	// `TCertWatchConfig` tunes the certificate expiry monitoring.
+	TCertWatchConfig struct {
+		// IntervalHours is how often the certificates are checked
+		// (default 12).
+		IntervalHours int `json:"intervalHours,omitempty"`
+
+		// WarnDays is the remaining lifetime below which warnings
+		// are logged and the webhook fires (default 14).
+		WarnDays int `json:"warnDays,omitempty"`
+
+		// WebhookURL optionally receives a JSON POST for every
+		// certificate below the warning threshold.
+		WebhookURL string `json:"webhookURL,omitempty"`
+	}
+
+	// `TCertStatus` is the expiry state of one host certificate.
+	TCertStatus struct {
+		NotAfter time.Time `json:"notAfter"`
+		DaysLeft int       `json:"daysLeft"`
+	}
+
+	// `TCertWatcher` periodically checks the `NotAfter` dates of
+	// all configured host certificates, so an expiring certificate
+	// is noticed weeks before browsers start complaining.
+	TCertWatcher struct {
+		mtx      sync.RWMutex
+		audit    *TAuditLog
+		handler  *TProxyHandler
+		logger   *TLogger
+		client   *http.Client
+		interval time.Duration
+		warn     time.Duration
+		webhook  string
+		done     chan struct{}
+		state    map[string]TCertStatus // hostname -> expiry state
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewCertWatcher()` creates a watcher checking the certificates of
+// `aHandler`'s virtual hosts according to `aConfig` (which may be
+// nil to use the defaults).
+//
+// Parameters:
+//   - `aConfig`: The expiry monitoring settings (or nil).
+//   - `aHandler`: The proxy handler whose certificates are checked.
+//   - `aLogger`: Used for the expiry warnings.
+//
+// Returns:
+//   - `*TCertWatcher`: The new (not yet started) watcher.
+func NewCertWatcher(aConfig *TCertWatchConfig, aHandler *TProxyHandler, aLogger *TLogger) *TCertWatcher {
+	if nil == aConfig {
+		aConfig = &TCertWatchConfig{}
+	}
+	interval := time.Duration(aConfig.IntervalHours) * time.Hour
+	if 0 >= interval {
+		interval = 12 * time.Hour
+	}
+	warn := time.Duration(aConfig.WarnDays) * 24 * time.Hour
+	if 0 >= warn {
+		warn = 14 * 24 * time.Hour
+	}
+
+	return &TCertWatcher{
+		handler:  aHandler,
+		logger:   aLogger,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		interval: interval,
+		warn:     warn,
+		webhook:  aConfig.WebhookURL,
+		done:     make(chan struct{}),
+		state:    make(map[string]TCertStatus),
+	}
+} // NewCertWatcher()
+
+// --------------------------------------------------------------------------
+// TCertWatcher methods:
+
+// `SetAudit()` attaches the audit log recording certificate changes.
+//
+// Parameters:
+//   - `aAudit`: The audit log to write to.
+func (cw *TCertWatcher) SetAudit(aAudit *TAuditLog) {
+	cw.audit = aAudit
+} // SetAudit()
+
+// `Snapshot()` returns a copy of the last known per-host expiry
+// states.
+func (cw *TCertWatcher) Snapshot() map[string]TCertStatus {
+	cw.mtx.RLock()
+	defer cw.mtx.RUnlock()
+
+	snapshot := make(map[string]TCertStatus, len(cw.state))
+	for hostname, status := range cw.state {
+		snapshot[hostname] = status
+	}
+
+	return snapshot
+} // Snapshot()
+
+// `Start()` runs the periodic check rounds until `Stop()` is called.
+func (cw *TCertWatcher) Start() {
+	go func() {
+		cw.checkAll()
+		ticker := time.NewTicker(cw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cw.checkAll()
+			case <-cw.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the periodic check rounds.
+func (cw *TCertWatcher) Stop() {
+	close(cw.done)
+} // Stop()
+
+// `checkAll()` inspects every configured host certificate once.
+func (cw *TCertWatcher) checkAll() {
+	state := make(map[string]TCertStatus)
+	for hostname, certFile := range cw.handler.hostCerts() {
+		notAfter, err := certNotAfter(certFile)
+		if nil != err {
+			cw.logger.Error(hostname, `certificate check: `+err.Error())
+			continue
+		}
+		left := time.Until(notAfter)
+		status := TCertStatus{
+			NotAfter: notAfter,
+			DaysLeft: int(left / (24 * time.Hour)),
+		}
+		state[hostname] = status
+		if left < cw.warn {
+			cw.logger.Error(hostname, fmt.Sprintf(
+				"certificate expires in %d days (%s)",
+				status.DaysLeft, notAfter.Format(time.RFC3339)))
+			cw.fire(hostname, status)
+		}
+	}
+
+	cw.mtx.Lock()
+	for hostname, status := range state {
+		old, ok := cw.state[hostname]
+		if ok && !old.NotAfter.Equal(status.NotAfter) {
+			cw.audit.Log(`certwatch`, `cert-change`, fmt.Sprintf(
+				"%s now expires %s (was %s)", hostname,
+				status.NotAfter.Format(time.RFC3339),
+				old.NotAfter.Format(time.RFC3339)))
+		}
+	}
+	cw.state = state
+	cw.mtx.Unlock()
+} // checkAll()
+
+// `fire()` posts one expiry warning to the configured webhook.
+func (cw *TCertWatcher) fire(aHostname string, aStatus TCertStatus) {
+	if "" == cw.webhook {
+		return
+	}
+	body, _ := json.Marshal(map[string]any{
+		`host`:     aHostname,
+		`notAfter`: aStatus.NotAfter,
+		`daysLeft`: aStatus.DaysLeft,
+	})
+	resp, err := cw.client.Post(cw.webhook, `application/json`,
+		bytes.NewReader(body))
+	if nil != err {
+		cw.logger.Error(aHostname, `expiry webhook: `+err.Error())
+		return
+	}
+	_ = resp.Body.Close()
+} // fire()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `SetCertWatcher()` hands the admin API the certificate watcher
+// backing the `/api/certs` endpoint.
+func (ad *TAdmin) SetCertWatcher(aWatcher *TCertWatcher) {
+	ad.certs = aWatcher
+} // SetCertWatcher()
+
+// `certStates()` handles `GET /api/certs` returning the per-host
+// certificate expiry states.
+func (ad *TAdmin) certStates(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+	if nil == ad.certs {
+		http.Error(aWriter, `no certificate watcher running`,
+			http.StatusNotImplemented)
+		return
+	}
+	sendJSON(aWriter, ad.certs.Snapshot())
+} // certStates()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `certNotAfter()` reads the leaf certificate of the PEM file
+// `aCertFile` returning its expiry date.
+func certNotAfter(aCertFile string) (time.Time, error) {
+	data, err := os.ReadFile(aCertFile)
+	if nil != err {
+		return time.Time{}, err
+	}
+	block, _ := pem.Decode(data)
+	if (nil == block) || (`CERTIFICATE` != block.Type) {
+		return time.Time{}, fmt.Errorf("no certificate in %q", aCertFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if nil != err {
+		return time.Time{}, err
+	}
+
+	return cert.NotAfter, nil
+} // certNotAfter()
+
+/* _EoF_ */
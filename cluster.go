@@ -0,0 +1,237 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// This file implements the optional cluster mode for small HA pairs:
+// one instance is the leader (edited via config file or admin API as
+// usual), the others are followers polling the leader's admin API and
+// atomically applying its routing table, so all replicas behind DNS
+// round-robin or a failover IP serve the same hosts.  The follower
+// additionally mirrors the leader's backend health view for the
+// operator's benefit; polling the existing admin endpoints keeps the
+// whole mode dependency-free and needs no extra listener.
+
+type (
+	// `TClusterConfig` describes this instance's role in a cluster;
+	// the section is only needed on followers.
+	TClusterConfig struct {
+		// LeaderURL is the base URL of the leader's admin API,
+		// e.g. `https://lead.mwat.de:8404`; setting it makes this
+		// instance a follower of that leader.
+		LeaderURL string `json:"leaderURL"`
+
+		// Token is the bearer token for the leader's admin API; a
+		// read-only token suffices.
+		Token string `json:"token"`
+
+		// IntervalSec is the polling interval (default 15).
+		IntervalSec int `json:"intervalSec,omitempty"`
+	}
+
+	// `TClusterStatus` is the follower's view of the cluster as
+	// reported by `GET /api/cluster`.
+	TClusterStatus struct {
+		Leader     string                   `json:"leader"`
+		LastSync   time.Time                `json:"lastSync"`
+		LastError  string                   `json:"lastError,omitempty"`
+		Hosts      int                      `json:"hosts"`
+		PeerHealth map[string]THealthStatus `json:"peerHealth,omitempty"`
+	}
+
+	// `TClusterSync` keeps a follower's routing table in sync with
+	// its leader by periodically polling the leader's admin API.
+	TClusterSync struct {
+		mtx         sync.RWMutex
+		conf        *TClusterConfig
+		handler     *TProxyHandler
+		logger      *TLogger
+		client      *http.Client
+		interval    time.Duration
+		done        chan struct{}
+		lastApplied []byte // the last imported host set, marshalled
+		status      TClusterStatus
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewClusterSync()` creates a follower syncing `aHandler`'s routes
+// from the leader given in `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The leader address and polling settings.
+//   - `aHandler`: The proxy handler to keep in sync.
+//   - `aLogger`: Used for synchronisation problems.
+//
+// Returns:
+//   - `*TClusterSync`: The new (not yet started) follower.
+//   - `error`: An error for incomplete configurations.
+func NewClusterSync(aConfig *TClusterConfig, aHandler *TProxyHandler, aLogger *TLogger) (*TClusterSync, error) {
+	if ("" == aConfig.LeaderURL) || ("" == aConfig.Token) {
+		return nil, fmt.Errorf("cluster sync needs a leaderURL and token")
+	}
+	interval := time.Duration(aConfig.IntervalSec) * time.Second
+	if 0 >= interval {
+		interval = 15 * time.Second
+	}
+
+	return &TClusterSync{
+		conf:     aConfig,
+		handler:  aHandler,
+		logger:   aLogger,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		interval: interval,
+		done:     make(chan struct{}),
+		status:   TClusterStatus{Leader: aConfig.LeaderURL},
+	}, nil
+} // NewClusterSync()
+
+// --------------------------------------------------------------------------
+// TClusterSync methods:
+
+// `Start()` runs the periodic sync rounds until `Stop()` is called.
+func (cs *TClusterSync) Start() {
+	go func() {
+		cs.syncRound()
+		ticker := time.NewTicker(cs.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				cs.syncRound()
+			case <-cs.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Status()` returns a copy of the follower's last known cluster
+// state.
+func (cs *TClusterSync) Status() TClusterStatus {
+	cs.mtx.RLock()
+	defer cs.mtx.RUnlock()
+
+	status := cs.status
+	status.PeerHealth = make(map[string]THealthStatus,
+		len(cs.status.PeerHealth))
+	for target, health := range cs.status.PeerHealth {
+		status.PeerHealth[target] = health
+	}
+
+	return status
+} // Status()
+
+// `Stop()` terminates the periodic sync rounds.
+func (cs *TClusterSync) Stop() {
+	close(cs.done)
+} // Stop()
+
+// `fail()` records a failed sync round, keeping the routes last
+// applied in service.
+func (cs *TClusterSync) fail(aErr error) {
+	cs.logger.Error(``, `cluster sync: `+aErr.Error())
+	cs.mtx.Lock()
+	cs.status.LastError = aErr.Error()
+	cs.mtx.Unlock()
+} // fail()
+
+// `fetch()` performs one authorised `GET` against the leader's admin
+// API, decoding the JSON response into `aTarget`.
+func (cs *TClusterSync) fetch(aPath string, aTarget any) error {
+	request, err := http.NewRequest(http.MethodGet,
+		cs.conf.LeaderURL+aPath, nil)
+	if nil != err {
+		return err
+	}
+	request.Header.Set(`Authorization`, `Bearer `+cs.conf.Token)
+	response, err := cs.client.Do(request)
+	if nil != err {
+		return err
+	}
+	defer response.Body.Close()
+	if http.StatusOK != response.StatusCode {
+		return fmt.Errorf("leader answered %s for %q",
+			response.Status, aPath)
+	}
+
+	return json.NewDecoder(response.Body).Decode(aTarget)
+} // fetch()
+
+// `syncRound()` polls the leader once, applying its routing table if
+// it changed since the last round.
+func (cs *TClusterSync) syncRound() {
+	hosts := make(map[string]*THostConfig)
+	if err := cs.fetch(`/api/routetable`, &hosts); nil != err {
+		cs.fail(err)
+		return
+	}
+	// a marshalled map is deterministic (sorted keys), so comparing
+	// the bytes avoids rebuilding unchanged routes every round:
+	applied, err := json.Marshal(hosts)
+	if nil != err {
+		cs.fail(err)
+		return
+	}
+	if !bytes.Equal(applied, cs.lastApplied) {
+		if err = cs.handler.ImportHosts(hosts); nil != err {
+			cs.fail(err)
+			return
+		}
+		cs.lastApplied = applied
+	}
+
+	// the leader's health view is mirrored for the operator only,
+	// so a failure here doesn't fail the round:
+	health := make(map[string]THealthStatus)
+	_ = cs.fetch(`/api/health/backends`, &health)
+
+	cs.mtx.Lock()
+	cs.status.LastSync = time.Now()
+	cs.status.LastError = ``
+	cs.status.Hosts = len(hosts)
+	cs.status.PeerHealth = health
+	cs.mtx.Unlock()
+} // syncRound()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `SetCluster()` hands the admin API the cluster follower backing
+// the `/api/cluster` endpoint.
+func (ad *TAdmin) SetCluster(aSync *TClusterSync) {
+	ad.cluster = aSync
+} // SetCluster()
+
+// `clusterStatus()` handles `GET /api/cluster` returning the
+// follower's view of the cluster.
+func (ad *TAdmin) clusterStatus(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+	if nil == ad.cluster {
+		http.Error(aWriter, `no cluster sync running`,
+			http.StatusNotImplemented)
+		return
+	}
+	sendJSON(aWriter, ad.cluster.Status())
+} // clusterStatus()
+
+/* _EoF_ */
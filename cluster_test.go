@@ -0,0 +1,88 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClusterSync(t *testing.T) {
+	leaderHandler, err := NewProxyHandler(WithRoutes(map[string]string{
+		`www.mwat.de`: `http://127.0.0.1:8181`,
+	}))
+	if nil != err {
+		t.Fatalf(`NewProxyHandler() failed: %v`, err)
+	}
+	leaderAdmin, err := NewAdmin(&TAdminConfig{Token: `secret`},
+		`reprox.json`, leaderHandler, &TLogger{})
+	if nil != err {
+		t.Fatalf(`NewAdmin() failed: %v`, err)
+	}
+	leader := httptest.NewServer(leaderAdmin)
+	defer leader.Close()
+
+	follower, _ := NewProxyHandler()
+	sync, err := NewClusterSync(&TClusterConfig{
+		LeaderURL: leader.URL, Token: `secret`}, follower, &TLogger{})
+	if nil != err {
+		t.Fatalf(`NewClusterSync() failed: %v`, err)
+	}
+
+	sync.syncRound()
+	if _, ok := follower.Routes()[`www.mwat.de`]; !ok {
+		t.Fatal(`follower missed the leader's route`)
+	}
+	status := sync.Status()
+	if (1 != status.Hosts) || ("" != status.LastError) ||
+		status.LastSync.IsZero() {
+		t.Errorf(`Status() = %+v`, status)
+	}
+
+	// an unchanged table must not be re-imported:
+	applied := sync.lastApplied
+	sync.syncRound()
+	if &applied[0] != &sync.lastApplied[0] {
+		t.Error(`unchanged host set was imported again`)
+	}
+
+	// a new leader-side route arrives with the next round:
+	if err = leaderHandler.AddRoute(`blog.mwat.de`,
+		`http://127.0.0.1:8282`); nil != err {
+		t.Fatalf(`AddRoute() failed: %v`, err)
+	}
+	sync.syncRound()
+	if _, ok := follower.Routes()[`blog.mwat.de`]; !ok {
+		t.Error(`follower missed the added route`)
+	}
+
+	// an unreachable leader keeps the routes and reports the error:
+	leader.Close()
+	sync.syncRound()
+	if status = sync.Status(); "" == status.LastError {
+		t.Error(`failed round left no error`)
+	}
+	if _, ok := follower.Routes()[`blog.mwat.de`]; !ok {
+		t.Error(`failed round dropped the synced routes`)
+	}
+} // TestClusterSync()
+
+func TestClusterSyncConfig(t *testing.T) {
+	ph, _ := NewProxyHandler()
+	if _, err := NewClusterSync(&TClusterConfig{
+		LeaderURL: `http://leader:8404`}, ph, &TLogger{}); nil == err {
+		t.Error(`NewClusterSync() accepted a configuration without token`)
+	}
+	if _, err := NewClusterSync(&TClusterConfig{
+		Token: `secret`}, ph, &TLogger{}); nil == err {
+		t.Error(`NewClusterSync() accepted a configuration without leader`)
+	}
+} // TestClusterSyncConfig()
+
+/* _EoF_ */
@@ -0,0 +1,580 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	// `TListenerConfig` describes one public listening socket.
+	TListenerConfig struct {
+		// Addr is the address to bind, e.g. `:80`,
+		// `192.0.2.1:8080`, or `[2001:db8::1]:443`.
+		//
+		// An address starting with `/` or `@` is treated as a
+		// (filesystem or abstract) Unix socket path.
+		Addr string `json:"addr"`
+
+		// SocketMode is the octal file mode of a filesystem Unix
+		// socket (default `0660`).
+		SocketMode string `json:"socketMode,omitempty"`
+
+		// SocketOwner is the user the Unix socket file is chown'd
+		// to (needs root; optional).
+		SocketOwner string `json:"socketOwner,omitempty"`
+
+		// TLS serves HTTPS on this listener using the certificates
+		// of the configured virtual hosts.
+		TLS bool `json:"tls,omitempty"`
+
+		// ProxyProtocol expects a HAProxy PROXY protocol v1 header
+		// on every connection (for setups behind a load balancer).
+		ProxyProtocol bool `json:"proxyProtocol,omitempty"`
+	}
+
+	// `TPriority` is the priority class of a virtual host's
+	// traffic, consulted when resources get scarce.
+	TPriority int
+
+	// `TBackendAuth` holds credentials the proxy injects into the
+	// `Authorization` header of all upstream requests, letting an
+	// internal service that requires authentication sit behind the
+	// proxy while the clients stay unauthenticated.
+	TBackendAuth struct {
+		// Type is the authentication scheme: `basic` or `bearer`.
+		Type string `json:"type"`
+
+		// Username/Password used with `basic` authentication.
+		Username string `json:"username,omitempty"`
+		Password string `json:"password,omitempty"`
+
+		// Token used with `bearer` authentication.
+		Token string `json:"token,omitempty"`
+	}
+
+	// `THostConfig` holds the proxy settings of a single virtual host.
+	THostConfig struct {
+		// Target is the backend URL the host's requests are sent to.
+		//
+		// A `srv://_service._proto.domain` (or `srv+https://`) URL
+		// instead builds the backend pool dynamically from the DNS
+		// SRV record, honouring its priorities and weights; a
+		// `consul://agent:8500/service?tag=x` (or `consul+https://`)
+		// URL does the same from a Consul health query, and a
+		// `k8s://namespace/service:port` (or `k8s+https://`) URL
+		// from a Kubernetes `Endpoints` object.
+		Target string `json:"target"`
+
+		// Aliases lists additional hostnames answered by this site,
+		// e.g. the `www.` variant of the canonical name.
+		Aliases []string `json:"aliases,omitempty"`
+
+		// RedirectAliases answers alias requests with a permanent
+		// redirect to the canonical name instead of serving them
+		// transparently.
+		RedirectAliases bool `json:"redirectAliases,omitempty"`
+
+		// StaticDir serves this host directly from a local directory
+		// instead of proxying (with strong content-hash ETags and
+		// conditional/Range request handling); `Target` is ignored
+		// when set.
+		StaticDir string `json:"staticDir,omitempty"`
+
+		// Schedule optionally activates or deactivates this host on
+		// a recurring cron-like schedule, see `TScheduleConfig`.
+		Schedule *TScheduleConfig `json:"schedule,omitempty"`
+
+		// Script is a rule script applied to this host's requests
+		// and responses (deny, redirect, header and path rewrites,
+		// backend selection); see the syntax notes in `script.go`.
+		Script string `json:"script,omitempty"`
+
+		// Slots optionally names alternative backend target sets
+		// ("deployment slots", e.g. `blue`/`green`) for this host;
+		// `ActiveSlot` picks which of them receives the traffic.
+		Slots map[string]string `json:"slots,omitempty"`
+
+		// ActiveSlot is the deployment slot currently serving; an
+		// empty value (or one missing from `Slots`) uses `Target`.
+		// It can be flipped atomically via the admin API for
+		// instant blue/green cutover and rollback.
+		ActiveSlot string `json:"activeSlot,omitempty"`
+
+		// BackupTarget is an optional standby backend URL that only
+		// receives traffic while the primary `Target` is failing
+		// (nginx `backup` semantics): after a failed primary contact
+		// requests are diverted to it for a short period before the
+		// primary is tried again.
+		BackupTarget string `json:"backupTarget,omitempty"`
+
+		// AccessLog optionally overrides the global access log file
+		// for this virtual host.
+		AccessLog string `json:"accessLog,omitempty"`
+
+		// ErrorLog optionally overrides the global error log file
+		// for this virtual host.
+		ErrorLog string `json:"errorLog,omitempty"`
+
+		// CertFile/KeyFile optionally hold the host's TLS certificate.
+		CertFile string `json:"certFile,omitempty"`
+		KeyFile  string `json:"keyFile,omitempty"`
+
+		// Cache optionally enables a small in-memory response cache
+		// for this host, see `TCacheConfig`; cached entries can be
+		// invalidated via the admin API or `PURGE` requests.
+		Cache *TCacheConfig `json:"cache,omitempty"`
+
+		// Cookies optionally enforces attributes on `Set-Cookie`
+		// headers sent by the backend.
+		Cookies *TCookieConfig `json:"cookies,omitempty"`
+
+		// BodyRewrites are textual replacements applied to the
+		// host's (uncompressed) text/HTML response bodies.
+		BodyRewrites []TBodyRewrite `json:"bodyRewrites,omitempty"`
+
+		// EarlyHints are `Link` header values (e.g.
+		// `</app.css>; rel=preload; as=style`) sent to HTTP/1.1+
+		// clients as a `103 Early Hints` response before the backend
+		// is contacted, so browsers can start preloading while the
+		// backend is still processing.
+		EarlyHints []string `json:"earlyHints,omitempty"`
+
+		// RequestTimeoutSec overrides the global request timeout
+		// for this host; `-1` disables the timeout entirely (e.g.
+		// for long-running downloads or streaming endpoints).
+		RequestTimeoutSec int `json:"requestTimeoutSec,omitempty"`
+
+		// RewriteRedirects rewrites absolute `Location` and
+		// `Content-Location` headers pointing at the internal
+		// backend address to the public host and scheme.
+		RewriteRedirects bool `json:"rewriteRedirects,omitempty"`
+
+		// Overrides maps special request paths (e.g. `/robots.txt`
+		// or `/.well-known/security.txt`) to responses served by
+		// the proxy itself instead of the backend, see
+		// `TOverrideConfig`.  A path ending in a slash matches the
+		// whole subtree below it; the longest matching prefix wins,
+		// an exact path beats any prefix.
+		Overrides map[string]*TOverrideConfig `json:"overrides,omitempty"`
+
+		// Methods optionally restricts the HTTP methods accepted
+		// for this host (e.g. `GET`/`HEAD` for a read-only mirror);
+		// everything else is answered with `405` before it reaches
+		// the backend.  An empty list allows all methods.
+		Methods []string `json:"methods,omitempty"`
+
+		// MethodOverrides opts in to the `X-HTTP-Method-Override`
+		// header (and `_method` form field) translation for `POST`
+		// requests, listing the methods that may be assumed this
+		// way (e.g. `PUT`/`DELETE`).  An empty list disables the
+		// translation entirely.
+		MethodOverrides []string `json:"methodOverrides,omitempty"`
+
+		// MaxConcurrent caps the parallel requests sent to this
+		// host's backend (0: unlimited); the overflow is queued
+		// according to `queueDepth` and `queueWaitSec`.
+		MaxConcurrent int `json:"maxConcurrent,omitempty"`
+
+		// QueueDepth is how many requests may wait for a free
+		// backend slot (0: none, reject immediately).
+		QueueDepth int `json:"queueDepth,omitempty"`
+
+		// QueueWaitSec is the longest a queued request waits
+		// before giving up with `503` (default 5).
+		QueueWaitSec int `json:"queueWaitSec,omitempty"`
+
+		// MaxKBPerSec optionally caps the egress bandwidth of this
+		// host (token bucket, shared by all concurrent responses)
+		// so one busy download site can't saturate the uplink.
+		MaxKBPerSec int `json:"maxKBPerSec,omitempty"`
+
+		// Auth optionally injects credentials into all requests
+		// sent to this host's backend.
+		Auth *TBackendAuth `json:"auth,omitempty"`
+
+		// Priority is the host's traffic priority class (`high`,
+		// `normal`, or `low`; default `normal`): low priority
+		// traffic is shed first when the proxy is overloaded.
+		Priority string `json:"priority,omitempty"`
+
+		// ProxyURL routes connections to this backend through an
+		// outbound proxy: `http://`, `https://`, or `socks5://`
+		// URLs (with optional user:pass) are supported.
+		ProxyURL string `json:"proxyURL,omitempty"`
+
+		// HTTP2 requires HTTP/2 towards this host's backend:
+		// `https` URLs get an HTTP/2-only transport (no HTTP/1.1
+		// fallback), `http` URLs are spoken to with the built-in
+		// prior-knowledge h2c client - e.g. for gRPC backends.
+		HTTP2 bool `json:"http2,omitempty"`
+
+		// AnnounceTrailers adds `TE: trailers` to upstream
+		// requests so backends know they may send HTTP trailers
+		// (which the proxy forwards to the client).
+		AnnounceTrailers bool `json:"announceTrailers,omitempty"`
+
+		// Director optionally mutates every outgoing request to
+		// this host's backend (set programmatically, not via JSON).
+		Director func(*http.Request) `json:"-"`
+
+		// ModifyResponse optionally mutates every response from
+		// this host's backend before it reaches the client; a
+		// returned error triggers the proxy's error handling.
+		ModifyResponse func(*http.Response) error `json:"-"`
+	}
+
+	// `TConfig` holds the complete runtime configuration of the proxy
+	// as read from the JSON configuration file.
+	TConfig struct {
+		// AcmeWebroot is a directory served for ACME HTTP-01
+		// challenges: requests for `/.well-known/acme-challenge/*`
+		// are answered from it (certbot webroot mode) instead of
+		// being proxied.
+		AcmeWebroot string `json:"acmeWebroot,omitempty"`
+
+		// AcmeCache optionally shares ACME challenge tokens and
+		// issued certificates between clustered proxy instances,
+		// see `TAcmeCacheConfig`.
+		AcmeCache *TAcmeCacheConfig `json:"acmeCache,omitempty"`
+
+		// AcmeDNS optionally configures the DNS provider used for
+		// ACME DNS-01 challenges (required for wildcard certs).
+		AcmeDNS *TAcmeDNSConfig `json:"acmeDNS,omitempty"`
+
+		// Admin optionally enables the administrative API listener.
+		Admin *TAdminConfig `json:"admin,omitempty"`
+
+		// Alerts optionally enables latency budget monitoring.
+		Alerts *TAlertConfig `json:"alerts,omitempty"`
+
+		// CertWatch optionally tunes the certificate expiry
+		// monitoring (warn threshold, check interval, webhook).
+		CertWatch *TCertWatchConfig `json:"certWatch,omitempty"`
+
+		// Cluster optionally makes this instance a follower syncing
+		// its routing table from a leader, see `TClusterConfig`.
+		Cluster *TClusterConfig `json:"cluster,omitempty"`
+
+		// Docker optionally auto-registers routes from the labels
+		// of locally running Docker containers.
+		Docker *TDockerConfig `json:"docker,omitempty"`
+
+		// Etcd optionally syncs the routing table with an etcd
+		// key prefix shared by several proxy instances.
+		Etcd *TEtcdConfig `json:"etcd,omitempty"`
+
+		// Failover optionally makes this instance a standby
+		// heartbeating an active peer, see `TFailoverConfig`.
+		Failover *TFailoverConfig `json:"failover,omitempty"`
+
+		// Health optionally tunes the backend health checks.
+		Health *THealthConfig `json:"health,omitempty"`
+
+		// AccessLog is the default access log file used by all hosts
+		// that don't configure their own one.
+		AccessLog string `json:"accessLog"`
+
+		// AccessLogFormat is an Apache `LogFormat` style directive
+		// string for the access log lines (see `compileLogFormat()`
+		// for the supported directives, including the proxy
+		// extensions `%{backend}x` and `%{latency_ms}x`); empty
+		// means the classic `combined` format.
+		AccessLogFormat string `json:"accessLogFormat,omitempty"`
+
+		// AuditLog is an append-only log file recording all
+		// administrative actions (config reloads, admin API
+		// mutations, backend ejections, certificate changes).
+		AuditLog string `json:"auditLog,omitempty"`
+
+		// ErrorLog is the default error log file used by all hosts
+		// that don't configure their own one.
+		ErrorLog string `json:"errorLog"`
+
+		// Listeners are the public sockets served by the proxy;
+		// without any the classic `:80` (plus `:443` when TLS
+		// certificates are configured) are used.
+		Listeners []*TListenerConfig `json:"listeners,omitempty"`
+
+		// Limits optionally rejects oversized or malformed requests
+		// (absurd header counts, invalid Host values) with `400` or
+		// `431` before they reach a backend, see `TLimitsConfig`.
+		Limits *TLimitsConfig `json:"limits,omitempty"`
+
+		// RoutesDir is a `routes.d/` style directory of per-site
+		// JSON files watched and merged into the routing table at
+		// runtime; dropping or removing a file there publishes or
+		// unpublishes its sites.
+		RoutesDir string `json:"routesDir,omitempty"`
+
+		// LogRotation optionally enables the built-in size/time
+		// based rotation of all log files.
+		LogRotation *TLogRotation `json:"logRotation,omitempty"`
+
+		// LogSampling optionally reduces the access log volume by
+		// sampling successful requests and suppressing noisy paths.
+		LogSampling *TLogSampling `json:"logSampling,omitempty"`
+
+		// DrainSec keeps a removed virtual host being served for
+		// this many seconds after an admin removal or a config
+		// reload dropped it, so in-flight requests and sticky
+		// sessions finish instead of being cut off (default 0).
+		DrainSec int `json:"drainSec,omitempty"`
+
+		// GraceSec is the maximum number of seconds a shutdown
+		// waits for in-flight requests to finish (default 30).
+		GraceSec int `json:"graceSec,omitempty"`
+
+		// Hosts maps the incoming hostnames to their respective
+		// virtual host configuration.
+		Hosts map[string]*THostConfig `json:"hosts"`
+
+		// Shedding optionally enables adaptive load shedding when
+		// the proxy process itself is saturated.
+		Shedding *TShedConfig `json:"shedding,omitempty"`
+
+		// SLO optionally enables per-host availability and latency
+		// compliance reporting, see `TSLOConfig`.
+		SLO *TSLOConfig `json:"slo,omitempty"`
+
+		// StateDir names a directory where dynamic state (e.g. the
+		// auto-ban list) is persisted across restarts; empty keeps
+		// all dynamic state in memory only.
+		StateDir string `json:"stateDir,omitempty"`
+
+		// TCPProxies are generic TCP (L4) forwardings served next
+		// to the HTTP listeners, fronting non-HTTP services.
+		TCPProxies []*TTCPProxyConfig `json:"tcpProxies,omitempty"`
+
+		// UDPRelays are UDP port forwardings served next to the
+		// TCP/HTTP listeners, e.g. for DNS or syslog backends.
+		UDPRelays []*TUDPRelayConfig `json:"udpRelays,omitempty"`
+
+		// TicketKeys optionally tunes the TLS session ticket key
+		// rotation, including a shared key file for multi-instance
+		// setups.
+		TicketKeys *TTicketKeyConfig `json:"ticketKeys,omitempty"`
+
+		// Transport optionally tunes the upstream HTTP client
+		// (100-continue handshake, connection pooling).
+		Transport *TTransportConfig `json:"transport,omitempty"`
+
+		// TrustedProxies lists the CIDRs (or plain IPs) of load
+		// balancers in front of this proxy whose forwarding headers
+		// (`X-Forwarded-*`, `X-Real-IP`, `Forwarded`) are to be
+		// believed; from all other clients they are stripped.
+		TrustedProxies []string `json:"trustedProxies,omitempty"`
+
+		// RequestTimeoutSec limits the total time spent serving a
+		// single proxied request; zero means no limit. Hosts may
+		// override it via their own `requestTimeoutSec`.
+		RequestTimeoutSec int `json:"requestTimeoutSec,omitempty"`
+
+		// User/Group name the unprivileged account the proxy
+		// switches to after binding the low ports as root.
+		User  string `json:"user,omitempty"`
+		Group string `json:"group,omitempty"`
+	}
+)
+
+// the traffic priority classes, dropped in reverse order under load:
+const (
+	PrioHigh TPriority = iota
+	PrioNormal
+	PrioLow
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `LoadConfig()` reads the JSON configuration from `aFilename`
+// returning the parsed configuration and a possible error.
+//
+// Relative log filenames are resolved against the directory of the
+// configuration file.
+//
+// Parameters:
+//   - `aFilename`: Name of the JSON configuration file to read.
+//
+// Returns:
+//   - `*TConfig`: The parsed proxy configuration.
+//   - `error`: A possible processing error.
+func LoadConfig(aFilename string) (*TConfig, error) {
+	srcData, err := os.ReadFile(aFilename)
+	if nil != err {
+		return nil, fmt.Errorf("%w: reading %q: %w",
+			ErrConfigInvalid, aFilename, err)
+	}
+
+	conf := &TConfig{}
+	if err = json.Unmarshal(srcData, conf); nil != err {
+		return nil, fmt.Errorf("%w: parsing %q: %w",
+			ErrConfigInvalid, aFilename, err)
+	}
+	if 0 == len(conf.Hosts) {
+		return nil, fmt.Errorf("%w: %q: no hosts configured",
+			ErrConfigInvalid, aFilename)
+	}
+
+	baseDir := filepath.Dir(aFilename)
+	conf.AccessLog = absFilename(baseDir, conf.AccessLog)
+	conf.AuditLog = absFilename(baseDir, conf.AuditLog)
+	conf.ErrorLog = absFilename(baseDir, conf.ErrorLog)
+	conf.AcmeWebroot = absFilename(baseDir, conf.AcmeWebroot)
+	if nil != conf.AcmeCache {
+		conf.AcmeCache.Dir = absFilename(baseDir, conf.AcmeCache.Dir)
+	}
+	conf.RoutesDir = absFilename(baseDir, conf.RoutesDir)
+	conf.StateDir = absFilename(baseDir, conf.StateDir)
+	if nil != conf.TicketKeys {
+		conf.TicketKeys.KeyFile = absFilename(baseDir, conf.TicketKeys.KeyFile)
+	}
+	// hostname keys and aliases are matched in their normalized
+	// (lowercase, punycode) form, so IDN hosts may be configured
+	// in either notation:
+	hosts := make(map[string]*THostConfig, len(conf.Hosts))
+	for hostname, host := range conf.Hosts {
+		host.AccessLog = absFilename(baseDir, host.AccessLog)
+		host.ErrorLog = absFilename(baseDir, host.ErrorLog)
+		host.StaticDir = absFilename(baseDir, host.StaticDir)
+		host.Script = absFilename(baseDir, host.Script)
+		for _, override := range host.Overrides {
+			if nil != override {
+				override.File = absFilename(baseDir, override.File)
+			}
+		}
+		for idx, alias := range host.Aliases {
+			host.Aliases[idx] = normalizeHostname(alias)
+		}
+		hosts[normalizeHostname(hostname)] = host
+	}
+	conf.Hosts = hosts
+
+	return conf, nil
+} // LoadConfig()
+
+// --------------------------------------------------------------------------
+// THostConfig methods:
+
+// `allowsMethod()` reports whether `aMethod` is accepted by this
+// host; an empty `Methods` list allows everything.
+func (hc *THostConfig) allowsMethod(aMethod string) bool {
+	if 0 == len(hc.Methods) {
+		return true
+	}
+	for _, method := range hc.Methods {
+		if strings.EqualFold(method, aMethod) {
+			return true
+		}
+	}
+
+	return false
+} // allowsMethod()
+
+// `effectiveTarget()` returns the backend URL currently serving this
+// host: the active deployment slot's target if one is selected,
+// otherwise the plain `Target`.
+func (hc *THostConfig) effectiveTarget() string {
+	if "" != hc.ActiveSlot {
+		if target, ok := hc.Slots[hc.ActiveSlot]; ok {
+			return target
+		}
+	}
+
+	return hc.Target
+} // effectiveTarget()
+
+// `priority()` returns the host's parsed priority class.
+func (hc *THostConfig) priority() TPriority {
+	switch hc.Priority {
+	case `high`:
+		return PrioHigh
+
+	case `low`:
+		return PrioLow
+	}
+
+	return PrioNormal
+} // priority()
+
+// --------------------------------------------------------------------------
+// TBackendAuth methods:
+
+// `header()` returns the ready-made `Authorization` header value for
+// the configured credentials, or an empty string for an unknown type.
+func (ba *TBackendAuth) header() string {
+	switch ba.Type {
+	case `basic`:
+		creds := ba.Username + `:` + ba.Password
+		return `Basic ` + base64.StdEncoding.EncodeToString([]byte(creds))
+
+	case `bearer`:
+		return `Bearer ` + ba.Token
+	}
+
+	return ``
+} // header()
+
+// --------------------------------------------------------------------------
+// TConfig methods:
+
+// `Network()` returns the network the listener binds: `unix` for
+// socket paths, `tcp` otherwise.
+func (lc *TListenerConfig) Network() string {
+	if ("" != lc.Addr) &&
+		(('/' == lc.Addr[0]) || ('@' == lc.Addr[0])) {
+		return `unix`
+	}
+
+	return `tcp`
+} // Network()
+
+// `ListenerSpecs()` returns the effective list of public listeners.
+//
+// Without explicitly configured listeners the classic setup is
+// used: plain HTTP on `:80`, plus HTTPS on `:443` as soon as at
+// least one virtual host has a certificate configured.
+func (c *TConfig) ListenerSpecs() []*TListenerConfig {
+	if 0 < len(c.Listeners) {
+		return c.Listeners
+	}
+
+	specs := []*TListenerConfig{{Addr: `:80`}}
+	for _, host := range c.Hosts {
+		if ("" != host.CertFile) && ("" != host.KeyFile) {
+			specs = append(specs, &TListenerConfig{Addr: `:443`, TLS: true})
+			break
+		}
+	}
+
+	return specs
+} // ListenerSpecs()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `absFilename()` turns `aFilename` into an absolute path relative
+// to `aBaseDir` unless it is empty, already absolute, or names a
+// non-file log target like `syslog:` or `journald:`.
+func absFilename(aBaseDir, aFilename string) string {
+	if ("" == aFilename) || filepath.IsAbs(aFilename) ||
+		isLogScheme(aFilename) {
+		return aFilename
+	}
+
+	return filepath.Join(aBaseDir, aFilename)
+} // absFilename()
+
+/* _EoF_ */
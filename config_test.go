@@ -0,0 +1,86 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	confName := filepath.Join(dir, `reprox.json`)
+	confText := `{
+	"accessLog": "access.log",
+	"errorLog": "error.log",
+	"hosts": {
+		"example.org": {
+			"target": "http://127.0.0.1:8181",
+			"accessLog": "example.access.log"
+		},
+		"other.example.org": {
+			"target": "http://127.0.0.1:8282"
+		}
+	}
+}`
+	if err := os.WriteFile(confName, []byte(confText), 0600); nil != err {
+		t.Fatal(err)
+	}
+
+	conf, err := LoadConfig(confName)
+	if nil != err {
+		t.Fatalf(`LoadConfig() error = %v`, err)
+	}
+	if w := filepath.Join(dir, `access.log`); w != conf.AccessLog {
+		t.Errorf(`LoadConfig() AccessLog = %q, want %q`, conf.AccessLog, w)
+	}
+	if 2 != len(conf.Hosts) {
+		t.Fatalf(`LoadConfig() hosts = %d, want 2`, len(conf.Hosts))
+	}
+	host := conf.Hosts[`example.org`]
+	if w := filepath.Join(dir, `example.access.log`); w != host.AccessLog {
+		t.Errorf(`LoadConfig() host AccessLog = %q, want %q`, host.AccessLog, w)
+	}
+	if "" != conf.Hosts[`other.example.org`].AccessLog {
+		t.Errorf(`LoadConfig() unexpected host AccessLog override`)
+	}
+
+	if _, err = LoadConfig(filepath.Join(dir, `missing.json`)); nil == err {
+		t.Error(`LoadConfig() expected error for missing file`)
+	}
+} // TestLoadConfig()
+
+func TestNewLoggerSharedWriters(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, `shared.log`)
+	conf := &TConfig{
+		AccessLog: shared,
+		ErrorLog:  filepath.Join(dir, `error.log`),
+		Hosts: map[string]*THostConfig{
+			"one.example.org": {Target: `http://127.0.0.1:8181`, AccessLog: shared},
+			"two.example.org": {Target: `http://127.0.0.1:8282`},
+		},
+	}
+
+	lg, err := NewLogger(conf)
+	if nil != err {
+		t.Fatalf(`NewLogger() error = %v`, err)
+	}
+	defer lg.Close()
+
+	// The host override pointing at the global file must reuse the
+	// very same writer instance:
+	if lg.access[`one.example.org`] != lg.defAccess {
+		t.Error(`NewLogger() expected shared writer for identical filenames`)
+	}
+	if 2 != len(lg.files) {
+		t.Errorf(`NewLogger() open files = %d, want 2`, len(lg.files))
+	}
+} // TestNewLoggerSharedWriters()
+
+/* _EoF_ */
@@ -0,0 +1,123 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// `tConsulEntry` is the subset of a Consul
+	// `/v1/health/service/<name>` answer the pool cares about.
+	tConsulEntry struct {
+		Node struct {
+			Address string
+		}
+		Service struct {
+			Address string
+			Port    int
+			Weights struct {
+				Passing int
+			}
+		}
+	}
+)
+
+// `gConsulClient` is the HTTP client used for Consul catalog queries.
+var gConsulClient = &http.Client{Timeout: 10 * time.Second}
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newConsulPool()` creates an upstream pool fed by a Consul health
+// query.
+//
+// The target URL names the Consul agent and service, e.g.
+// `consul://127.0.0.1:8500/web?tag=primary&dc=dc1`; only passing
+// instances are used, and backends get `https` URLs when the
+// `consul+https` scheme is given.
+func newConsulPool(aTarget *url.URL, aLogger *TLogger) *tUpstreamPool {
+	scheme := `http`
+	if `consul+https` == aTarget.Scheme {
+		scheme = `https`
+	}
+	service := strings.Trim(aTarget.Path, `/`)
+	query := url.Values{`passing`: {`1`}}
+	if tag := aTarget.Query().Get(`tag`); "" != tag {
+		query.Set(`tag`, tag)
+	}
+	if dc := aTarget.Query().Get(`dc`); "" != dc {
+		query.Set(`dc`, dc)
+	}
+	catalogURL := fmt.Sprintf(`http://%s/v1/health/service/%s?%s`,
+		aTarget.Host, service, query.Encode())
+
+	return &tUpstreamPool{
+		service: service,
+		lookup: func() ([]tPoolTarget, error) {
+			return consulLookup(catalogURL, scheme)
+		},
+		logger: aLogger,
+	}
+} // newConsulPool()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `consulLookup()` queries `aCatalogURL` turning the passing service
+// instances into pool targets with `aScheme` backend URLs.
+func consulLookup(aCatalogURL, aScheme string) ([]tPoolTarget, error) {
+	resp, err := gConsulClient.Get(aCatalogURL)
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("consul answered %s", resp.Status)
+	}
+
+	var entries []tConsulEntry
+	if err = json.NewDecoder(resp.Body).Decode(&entries); nil != err {
+		return nil, err
+	}
+
+	targets := make([]tPoolTarget, 0, len(entries))
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if "" == address {
+			address = entry.Node.Address
+		}
+		if ("" == address) || (0 == entry.Service.Port) {
+			continue
+		}
+		weight := entry.Service.Weights.Passing
+		if 0 >= weight {
+			weight = 1
+		}
+		targets = append(targets, tPoolTarget{
+			target: &url.URL{
+				Scheme: aScheme,
+				Host: net.JoinHostPort(address,
+					strconv.Itoa(entry.Service.Port)),
+			},
+			weight: weight,
+		})
+	}
+
+	return targets, nil
+} // consulLookup()
+
+/* _EoF_ */
@@ -0,0 +1,80 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	// `TCookieConfig` describes how `Set-Cookie` headers from the
+	// backend are adjusted before reaching the client.
+	TCookieConfig struct {
+		// Secure forces the `Secure` attribute on cookies set via
+		// HTTPS connections.
+		Secure bool `json:"secure,omitempty"`
+
+		// SameSite forces the given SameSite value (`lax`,
+		// `strict`, or `none`); empty keeps the backend's value.
+		SameSite string `json:"sameSite,omitempty"`
+
+		// Domain rewrites the cookie `Domain` attribute (to match
+		// the public site); empty keeps the backend's value, `-`
+		// removes the attribute entirely (host-only cookie).
+		Domain string `json:"domain,omitempty"`
+
+		// Path rewrites the cookie `Path` attribute; empty keeps
+		// the backend's value.
+		Path string `json:"path,omitempty"`
+	}
+)
+
+// `enforceCookieAttrs()` rewrites all `Set-Cookie` headers of
+// `aResponse` according to `aConf`.
+//
+// Parameters:
+//   - `aResponse`: The backend response to fix up in place.
+//   - `aConf`: The cookie attribute rules of the route.
+func enforceCookieAttrs(aResponse *http.Response, aConf *TCookieConfig) {
+	cookies := aResponse.Cookies()
+	if 0 == len(cookies) {
+		return
+	}
+	viaTLS := (nil != aResponse.Request) && (nil != aResponse.Request.TLS)
+
+	aResponse.Header.Del(`Set-Cookie`)
+	for _, cookie := range cookies {
+		if aConf.Secure && viaTLS {
+			cookie.Secure = true
+		}
+		switch strings.ToLower(aConf.SameSite) {
+		case `lax`:
+			cookie.SameSite = http.SameSiteLaxMode
+		case `strict`:
+			cookie.SameSite = http.SameSiteStrictMode
+		case `none`:
+			cookie.SameSite = http.SameSiteNoneMode
+			cookie.Secure = true // required by the spec
+		}
+		switch aConf.Domain {
+		case ``: // keep
+		case `-`:
+			cookie.Domain = ``
+		default:
+			cookie.Domain = aConf.Domain
+		}
+		if "" != aConf.Path {
+			cookie.Path = aConf.Path
+		}
+		aResponse.Header.Add(`Set-Cookie`, cookie.String())
+	}
+} // enforceCookieAttrs()
+
+/* _EoF_ */
@@ -0,0 +1,92 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEnforceCookieAttrs(t *testing.T) {
+	response := func(aTLS bool, aCookies ...string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		if aTLS {
+			req.TLS = &tls.ConnectionState{}
+		}
+		resp := &http.Response{
+			Header:  http.Header{},
+			Request: req,
+		}
+		for _, cookie := range aCookies {
+			resp.Header.Add(`Set-Cookie`, cookie)
+		}
+		return resp
+	}
+
+	for _, tc := range []struct {
+		name    string
+		conf    TCookieConfig
+		tls     bool
+		cookies []string
+		want    []string
+	}{
+		{name: `add missing attributes`,
+			conf:    TCookieConfig{Secure: true, SameSite: `lax`},
+			tls:     true,
+			cookies: []string{`session=abc`},
+			want:    []string{`session=abc; Secure; SameSite=Lax`}},
+		{name: `keep present attributes`,
+			conf:    TCookieConfig{Secure: true, SameSite: `lax`},
+			tls:     true,
+			cookies: []string{`session=abc; Path=/app; Secure; SameSite=Lax`},
+			want:    []string{`session=abc; Path=/app; Secure; SameSite=Lax`}},
+		{name: `secure only via TLS`,
+			conf:    TCookieConfig{Secure: true},
+			cookies: []string{`session=abc`},
+			want:    []string{`session=abc`}},
+		{name: `samesite none implies secure`,
+			conf:    TCookieConfig{SameSite: `none`},
+			cookies: []string{`session=abc`},
+			want:    []string{`session=abc; Secure; SameSite=None`}},
+		{name: `attribute case is irrelevant`,
+			conf:    TCookieConfig{SameSite: `STRICT`},
+			cookies: []string{`session=abc; samesite=lax`},
+			want:    []string{`session=abc; SameSite=Strict`}},
+		{name: `domain rewritten`,
+			conf:    TCookieConfig{Domain: `example.org`},
+			cookies: []string{`session=abc; Domain=backend.local`},
+			want:    []string{`session=abc; Domain=example.org`}},
+		{name: `domain removed`,
+			conf:    TCookieConfig{Domain: `-`},
+			cookies: []string{`session=abc; Domain=backend.local`},
+			want:    []string{`session=abc`}},
+		{name: `multiple cookies rewritten`,
+			conf: TCookieConfig{SameSite: `lax`, Path: `/`},
+			cookies: []string{
+				`session=abc; Path=/app`,
+				`theme=dark`,
+			},
+			want: []string{
+				`session=abc; Path=/; SameSite=Lax`,
+				`theme=dark; Path=/; SameSite=Lax`,
+			}},
+	} {
+		resp := response(tc.tls, tc.cookies...)
+		enforceCookieAttrs(resp, &tc.conf)
+		got := resp.Header.Values(`Set-Cookie`)
+		if strings.Join(tc.want, "\n") != strings.Join(got, "\n") {
+			t.Errorf("%s:\ngot  %q\nwant %q", tc.name, got, tc.want)
+		}
+	}
+} // TestEnforceCookieAttrs()
+
+/* _EoF_ */
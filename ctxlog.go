@@ -0,0 +1,124 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type (
+	// `tCtxKey` is the private type of this package's context keys,
+	// so they can't collide with keys of other packages.
+	tCtxKey int
+
+	// `TRequestLogger` is a request-scoped logger carrying the
+	// correlation fields of one request (its ID, virtual host, and
+	// selected backend), so every line logged while serving the
+	// request can be matched up afterwards.
+	//
+	// It travels with the request's context; middlewares and
+	// embedders retrieve it via `RequestLogger()`.  All methods are
+	// safe to call on a nil receiver (they simply do nothing).
+	TRequestLogger struct {
+		logger   *TLogger
+		id       string
+		hostname string
+		backend  string
+	}
+)
+
+// `gCtxLoggerKey` keys the request logger in a request's context.
+const gCtxLoggerKey = tCtxKey(1)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRequestLogger()` creates the scoped logger of one request.
+func newRequestLogger(aLogger *TLogger, aID, aHostname string) *TRequestLogger {
+	return &TRequestLogger{
+		logger:   aLogger,
+		id:       aID,
+		hostname: aHostname,
+	}
+} // newRequestLogger()
+
+// --------------------------------------------------------------------------
+// TRequestLogger methods:
+
+// `Error()` writes `aMessage` to the error log of the request's
+// virtual host, prefixed with the request's correlation fields.
+//
+// Parameters:
+//   - `aMessage`: The text to log.
+func (rl *TRequestLogger) Error(aMessage string) {
+	if (nil == rl) || (nil == rl.logger) {
+		return
+	}
+	prefix := ``
+	if "" != rl.id {
+		prefix = `[request ` + rl.id + `] `
+	}
+	if "" != rl.backend {
+		prefix += `[backend ` + rl.backend + `] `
+	}
+	rl.logger.Error(rl.hostname, prefix+aMessage)
+} // Error()
+
+// `ID()` returns the request's unique correlation ID.
+func (rl *TRequestLogger) ID() string {
+	if nil == rl {
+		return ``
+	}
+
+	return rl.id
+} // ID()
+
+// `setBackend()` records the backend selected for the request.
+func (rl *TRequestLogger) setBackend(aBackend string) {
+	if nil != rl {
+		rl.backend = aBackend
+	}
+} // setBackend()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `RequestLogger()` returns the request-scoped logger stored in
+// `aContext`, or nil if the context doesn't carry one; the returned
+// logger may be used (and is safe) even when nil.
+//
+// Parameters:
+//   - `aContext`: The context of the request being served.
+//
+// Returns:
+//   - `*TRequestLogger`: The request's scoped logger (may be nil).
+func RequestLogger(aContext context.Context) *TRequestLogger {
+	rl, _ := aContext.Value(gCtxLoggerKey).(*TRequestLogger)
+
+	return rl
+} // RequestLogger()
+
+// `withRequestLogger()` stores `aLogger` in `aContext`.
+func withRequestLogger(aContext context.Context, aLogger *TRequestLogger) context.Context {
+	return context.WithValue(aContext, gCtxLoggerKey, aLogger)
+} // withRequestLogger()
+
+// `newRequestID()` returns a short unique correlation ID.
+func newRequestID() string {
+	var raw [8]byte
+	if _, err := rand.Read(raw[:]); nil != err {
+		return `00000000`
+	}
+
+	return hex.EncodeToString(raw[:])
+} // newRequestID()
+
+/* _EoF_ */
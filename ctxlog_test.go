@@ -0,0 +1,42 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	rl := newRequestLogger(nil, `cafe0123`, `example.org`)
+	ctx := withRequestLogger(context.Background(), rl)
+	if got := RequestLogger(ctx); rl != got {
+		t.Errorf(`RequestLogger() = %v, want %v`, got, rl)
+	}
+	if got := RequestLogger(context.Background()); nil != got {
+		t.Errorf(`RequestLogger() = %v, want nil`, got)
+	}
+	if `cafe0123` != rl.ID() {
+		t.Errorf(`ID() = %q`, rl.ID())
+	}
+
+	// nil receivers must be safe no-ops:
+	var nilLogger *TRequestLogger
+	nilLogger.Error(`ignored`)
+	nilLogger.setBackend(`ignored`)
+	if "" != nilLogger.ID() {
+		t.Error(`nil logger returned a non-empty ID`)
+	}
+
+	if id := newRequestID(); 16 != len(id) {
+		t.Errorf(`newRequestID() length = %d, want 16`, len(id))
+	}
+} // TestRequestLogger()
+
+/* _EoF_ */
@@ -0,0 +1,98 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+)
+
+// `DevTLSConfig()` builds an in-memory TLS setup for local
+// development: a throw-away mini-CA signs one certificate covering
+// `aHostnames` (plus `localhost` and the loopback addresses), so
+// the full HTTPS path can be exercised without real certificates.
+//
+// Nothing is written to disk and the certificates expire after a
+// week — this is strictly for dev/test setups.
+//
+// Parameters:
+//   - `aHostnames`: The hostnames the certificate must cover.
+//
+// Returns:
+//   - `*tls.Config`: The ready-to-serve TLS configuration.
+//   - `error`: A possible key or certificate generation error.
+func DevTLSConfig(aHostnames []string) (*tls.Config, error) {
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(7 * 24 * time.Hour)
+
+	// the throw-away mini-CA:
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if nil != err {
+		return nil, fmt.Errorf("generating CA key: %w", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: `reprox dev CA`},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader,
+		caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if nil != err {
+		return nil, fmt.Errorf("creating CA certificate: %w", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if nil != err {
+		return nil, err
+	}
+
+	// the leaf certificate covering all configured hostnames:
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if nil != err {
+		return nil, fmt.Errorf("generating leaf key: %w", err)
+	}
+	dnsNames := append([]string{`localhost`}, aHostnames...)
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: `reprox dev`},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     dnsNames,
+		IPAddresses: []net.IP{
+			net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader,
+		leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	if nil != err {
+		return nil, fmt.Errorf("creating dev certificate: %w", err)
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{leafDER, caDER},
+			PrivateKey:  leafKey,
+		}},
+	}, nil
+} // DevTLSConfig()
+
+/* _EoF_ */
@@ -0,0 +1,64 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+)
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `registerDiagnostics()` exposes the runtime profiling endpoints on
+// the admin mux.
+//
+// These are deliberately wired to the admin listener only — the
+// public proxy listeners never serve the default mux — so production
+// performance problems can be profiled without exposing internals.
+func (ad *TAdmin) registerDiagnostics() {
+	ad.mux.HandleFunc(`/debug/pprof/`, pprof.Index)
+	ad.mux.HandleFunc(`/debug/pprof/cmdline`, pprof.Cmdline)
+	ad.mux.HandleFunc(`/debug/pprof/profile`, pprof.Profile)
+	ad.mux.HandleFunc(`/debug/pprof/symbol`, pprof.Symbol)
+	ad.mux.HandleFunc(`/debug/pprof/trace`, pprof.Trace)
+	ad.mux.Handle(`/debug/vars`, expvar.Handler())
+	ad.mux.HandleFunc(`/debug/stack`, ad.stackDump)
+} // registerDiagnostics()
+
+// `stackDump()` handles `GET /debug/stack` writing the stack traces
+// of all goroutines plus a short runtime summary.
+func (ad *TAdmin) stackDump(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	aWriter.Header().Set(`Content-Type`, `text/plain; charset=utf-8`)
+	fmt.Fprintf(aWriter, "goroutines: %d\nheap: %d KiB\ngc runs: %d\n\n",
+		runtime.NumGoroutine(), memStats.HeapAlloc>>10, memStats.NumGC)
+
+	buf := make([]byte, 1<<20)
+	for {
+		written := runtime.Stack(buf, true)
+		if written < len(buf) {
+			_, _ = aWriter.Write(buf[:written])
+			return
+		}
+		buf = make([]byte, len(buf)<<1)
+	}
+} // stackDump()
+
+/* _EoF_ */
@@ -0,0 +1,187 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"math/rand"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type (
+	// `tPoolTarget` is one discovered backend of an upstream pool.
+	tPoolTarget struct {
+		target *url.URL
+		weight int
+	}
+
+	// `tUpstreamPool` is a dynamically discovered set of backends
+	// serving one virtual host; requests are spread over the
+	// targets by their discovered weights.
+	//
+	// The pool re-resolves itself in the background whenever a
+	// request finds its data stale, so no extra watcher goroutine
+	// has to be managed across config reloads.
+	tUpstreamPool struct {
+		mtx       sync.Mutex
+		service   string // the watched service (for log messages)
+		lookup    func() ([]tPoolTarget, error)
+		targets   []tPoolTarget
+		total     int // sum of all weights
+		resolved  time.Time
+		resolving bool
+		logger    *TLogger
+	}
+)
+
+// `poolRefreshInterval` is how long discovered backends are used
+// before the pool re-resolves its source.
+const poolRefreshInterval = 30 * time.Second
+
+// --------------------------------------------------------------------------
+// constructor functions:
+
+// `newPool()` creates the upstream pool matching `aTarget`'s
+// discovery scheme, or `nil` for a fixed backend URL; the initial
+// resolution happens synchronously so startup errors surface
+// immediately in the error log.
+func newPool(aTarget *url.URL, aLogger *TLogger) *tUpstreamPool {
+	var pool *tUpstreamPool
+	switch aTarget.Scheme {
+	case `srv`, `srv+https`:
+		pool = newSrvPool(aTarget, aLogger)
+
+	case `consul`, `consul+https`:
+		pool = newConsulPool(aTarget, aLogger)
+
+	case `k8s`, `k8s+https`:
+		pool = newK8sPool(aTarget, aLogger)
+
+	default:
+		return nil
+	}
+	pool.refresh()
+
+	return pool
+} // newPool()
+
+// `newSrvPool()` creates an upstream pool fed by the DNS SRV record
+// named by `aTarget`'s host part.
+//
+// Backend targets use `http` for a `srv://` URL and `https` for a
+// `srv+https://` URL.
+func newSrvPool(aTarget *url.URL, aLogger *TLogger) *tUpstreamPool {
+	scheme := `http`
+	if `srv+https` == aTarget.Scheme {
+		scheme = `https`
+	}
+	service := aTarget.Host
+
+	return &tUpstreamPool{
+		service: service,
+		lookup:  func() ([]tPoolTarget, error) { return srvLookup(service, scheme) },
+		logger:  aLogger,
+	}
+} // newSrvPool()
+
+// --------------------------------------------------------------------------
+// tUpstreamPool methods:
+
+// `pick()` returns a weighted random backend of the pool, or `nil`
+// while no backend is known; stale pool data triggers an
+// asynchronous re-resolution.
+func (up *tUpstreamPool) pick() *url.URL {
+	up.mtx.Lock()
+	if (poolRefreshInterval < time.Since(up.resolved)) && !up.resolving {
+		up.resolving = true
+		go up.refresh()
+	}
+	defer up.mtx.Unlock()
+	if 0 == len(up.targets) {
+		return nil
+	}
+	if 0 >= up.total {
+		return up.targets[rand.Intn(len(up.targets))].target
+	}
+
+	pick := rand.Intn(up.total)
+	for _, target := range up.targets {
+		if pick -= target.weight; 0 > pick {
+			return target.target
+		}
+	}
+
+	return up.targets[len(up.targets)-1].target
+} // pick()
+
+// `refresh()` re-runs the pool's discovery lookup, replacing its
+// targets; on lookup errors the last known targets are kept.
+func (up *tUpstreamPool) refresh() {
+	targets, err := up.lookup()
+
+	up.mtx.Lock()
+	defer up.mtx.Unlock()
+	up.resolved, up.resolving = time.Now(), false
+	if nil != err {
+		if nil != up.logger {
+			up.logger.Error(up.service, `discovery: `+err.Error())
+		}
+		return
+	}
+
+	total := 0
+	for _, target := range targets {
+		total += target.weight
+	}
+	up.targets, up.total = targets, total
+} // refresh()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `srvLookup()` resolves the DNS SRV record `aService` into pool
+// targets using `aScheme`, keeping only the lowest-priority (i.e.
+// preferred) group.
+func srvLookup(aService, aScheme string) ([]tPoolTarget, error) {
+	_, records, err := net.LookupSRV(``, ``, aService)
+	if nil != err {
+		return nil, err
+	}
+
+	var best uint16
+	var targets []tPoolTarget
+	for _, record := range records {
+		if (0 == len(targets)) || (record.Priority < best) {
+			best = record.Priority
+			targets = nil
+		}
+		if record.Priority != best {
+			continue
+		}
+		host := record.Target
+		if ('.' == host[len(host)-1]) && (1 < len(host)) {
+			host = host[:len(host)-1]
+		}
+		targets = append(targets, tPoolTarget{
+			target: &url.URL{
+				Scheme: aScheme,
+				Host: net.JoinHostPort(host,
+					strconv.Itoa(int(record.Port))),
+			},
+			weight: int(record.Weight),
+		})
+	}
+
+	return targets, nil
+} // srvLookup()
+
+/* _EoF_ */
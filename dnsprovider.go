@@ -0,0 +1,228 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// `TAcmeDNSConfig` selects and configures the DNS provider used
+	// for ACME DNS-01 challenges, which (unlike HTTP-01) can obtain
+	// wildcard certificates.
+	TAcmeDNSConfig struct {
+		// Provider is the provider type: `rfc2136` or `webhook`.
+		Provider string `json:"provider"`
+
+		// Server is the authoritative nameserver receiving the
+		// RFC 2136 dynamic updates, e.g. `ns1.example.com:53`.
+		Server string `json:"server,omitempty"`
+
+		// Zone is the zone the updates are applied to.
+		Zone string `json:"zone,omitempty"`
+
+		// TSIGName/TSIGSecret authenticate the RFC 2136 updates;
+		// the secret is base64 (as in a BIND key file), the
+		// algorithm is HMAC-SHA256.
+		TSIGName   string `json:"tsigName,omitempty"`
+		TSIGSecret string `json:"tsigSecret,omitempty"`
+
+		// WebhookURL receives the `webhook` provider's requests: a
+		// JSON POST of `{"action", "fqdn", "value"}` for every
+		// record to create (`present`) or delete (`cleanup`).
+		WebhookURL string `json:"webhookURL,omitempty"`
+	}
+
+	// `TDNSProvider` creates and removes the `_acme-challenge` TXT
+	// records of DNS-01 challenges.
+	TDNSProvider interface {
+		// `Present()` publishes `aValue` as TXT record of
+		// `_acme-challenge.<aDomain>`.
+		Present(aDomain, aValue string) error
+
+		// `Cleanup()` removes the challenge record again.
+		Cleanup(aDomain, aValue string) error
+	}
+
+	// `tWebhookProvider` delegates the record handling to an
+	// external automation via plain HTTP.
+	tWebhookProvider struct {
+		url    string
+		client *http.Client
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewDNSProvider()` creates the DNS-01 provider selected by
+// `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The provider selection and settings.
+//
+// Returns:
+//   - `TDNSProvider`: The configured provider.
+//   - `error`: An error for unknown or incomplete configurations.
+func NewDNSProvider(aConfig *TAcmeDNSConfig) (TDNSProvider, error) {
+	switch aConfig.Provider {
+	case `rfc2136`:
+		return newRfc2136Provider(aConfig)
+
+	case `webhook`:
+		if "" == aConfig.WebhookURL {
+			return nil, fmt.Errorf("webhook provider needs a webhookURL")
+		}
+		return &tWebhookProvider{
+			url:    aConfig.WebhookURL,
+			client: &http.Client{Timeout: 30 * time.Second},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown DNS provider %q", aConfig.Provider)
+} // NewDNSProvider()
+
+// --------------------------------------------------------------------------
+// tWebhookProvider methods:
+
+// `Cleanup()` asks the webhook to remove the challenge record.
+func (wp *tWebhookProvider) Cleanup(aDomain, aValue string) error {
+	return wp.send(`cleanup`, aDomain, aValue)
+} // Cleanup()
+
+// `Present()` asks the webhook to publish the challenge record.
+func (wp *tWebhookProvider) Present(aDomain, aValue string) error {
+	return wp.send(`present`, aDomain, aValue)
+} // Present()
+
+// `send()` posts one action to the configured webhook.
+func (wp *tWebhookProvider) send(aAction, aDomain, aValue string) error {
+	body, _ := json.Marshal(map[string]string{
+		`action`: aAction,
+		`fqdn`:   acmeChallengeFQDN(aDomain),
+		`value`:  aValue,
+	})
+	resp, err := wp.client.Post(wp.url, `application/json`,
+		bytes.NewReader(body))
+	if nil != err {
+		return err
+	}
+	defer resp.Body.Close()
+	if (http.StatusOK != resp.StatusCode) &&
+		(http.StatusNoContent != resp.StatusCode) {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+
+	return nil
+} // send()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `SetDNSProvider()` hands the admin API the DNS-01 provider used
+// by the `/api/acme/dns` endpoint.
+func (ad *TAdmin) SetDNSProvider(aProvider TDNSProvider) {
+	ad.dns = aProvider
+} // SetDNSProvider()
+
+// `acmeDNS()` handles `POST /api/acme/dns` publishing or removing
+// a DNS-01 challenge record; this lets external ACME clients (e.g.
+// certbot's manual auth hooks) drive the configured provider.
+//
+// The JSON body carries `action` (`present` or `cleanup`), `domain`,
+// and either the ready TXT `value` or the raw `keyAuth` from which
+// the value is derived.
+func (ad *TAdmin) acmeDNS(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodPost != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodPost)
+		return
+	}
+	if nil == ad.dns {
+		http.Error(aWriter, `no DNS provider configured`,
+			http.StatusNotImplemented)
+		return
+	}
+
+	var body struct {
+		Action  string `json:"action"`
+		Domain  string `json:"domain"`
+		Value   string `json:"value"`
+		KeyAuth string `json:"keyAuth"`
+	}
+	if err := json.NewDecoder(aRequest.Body).Decode(&body); nil != err {
+		http.Error(aWriter, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if ("" == body.Value) && ("" != body.KeyAuth) {
+		body.Value = KeyAuthTXT(body.KeyAuth)
+	}
+	if ("" == body.Domain) || ("" == body.Value) {
+		http.Error(aWriter, `domain and value (or keyAuth) required`,
+			http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	switch body.Action {
+	case `present`:
+		err = ad.dns.Present(body.Domain, body.Value)
+
+	case `cleanup`:
+		err = ad.dns.Cleanup(body.Domain, body.Value)
+
+	default:
+		http.Error(aWriter, `unknown action`, http.StatusBadRequest)
+		return
+	}
+	ad.audit.Log(aRequest.RemoteAddr, `acme-dns`,
+		body.Action+` `+body.Domain)
+	if nil != err {
+		ad.logger.Error(`admin`, `acme dns: `+err.Error())
+		http.Error(aWriter, err.Error(), http.StatusBadGateway)
+		return
+	}
+	sendJSON(aWriter, map[string]string{
+		`fqdn`: acmeChallengeFQDN(body.Domain), `status`: `ok`})
+} // acmeDNS()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `acmeChallengeFQDN()` returns the challenge record name of
+// `aDomain`, stripping a possible wildcard label first.
+func acmeChallengeFQDN(aDomain string) string {
+	if (2 < len(aDomain)) && (`*.` == aDomain[:2]) {
+		aDomain = aDomain[2:]
+	}
+
+	return `_acme-challenge.` + aDomain + `.`
+} // acmeChallengeFQDN()
+
+// `KeyAuthTXT()` computes the TXT record value of a DNS-01
+// challenge from the ACME key authorisation string (RFC 8555,
+// section 8.4): the base64url encoded SHA-256 digest.
+//
+// Parameters:
+//   - `aKeyAuth`: The challenge's key authorisation.
+//
+// Returns:
+//   - `string`: The value to publish in the TXT record.
+func KeyAuthTXT(aKeyAuth string) string {
+	digest := sha256.Sum256([]byte(aKeyAuth))
+
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+} // KeyAuthTXT()
+
+/* _EoF_ */
@@ -0,0 +1,202 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// `TDockerConfig` enables auto-registration of routes from the
+	// labels of locally running Docker containers.
+	TDockerConfig struct {
+		// Socket is the Docker daemon's Unix socket (default
+		// `/var/run/docker.sock`).
+		Socket string `json:"socket,omitempty"`
+
+		// IntervalSec is the polling interval (default 15).
+		IntervalSec int `json:"intervalSec,omitempty"`
+	}
+
+	// `TDockerWatcher` keeps routes registered for all running
+	// containers carrying `reprox.host` (and optionally
+	// `reprox.port`) labels, so a freshly started container becomes
+	// reachable through the proxy without any config file edit.
+	TDockerWatcher struct {
+		mtx      sync.Mutex
+		client   *http.Client
+		interval time.Duration
+		handler  *TProxyHandler
+		logger   *TLogger
+		done     chan struct{}
+		applied  map[string]string // hostname -> target, as last applied
+	}
+
+	// `tDockerContainer` is the subset of a `/containers/json`
+	// entry the watcher cares about.
+	tDockerContainer struct {
+		Labels          map[string]string
+		NetworkSettings struct {
+			Networks map[string]struct {
+				IPAddress string
+			}
+		}
+	}
+)
+
+// labels read from the containers:
+const (
+	dockerHostLabel = `reprox.host`
+	dockerPortLabel = `reprox.port`
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewDockerWatcher()` creates a watcher registering routes for the
+// labelled containers of the local Docker daemon.
+//
+// Parameters:
+//   - `aConfig`: The Docker connection settings.
+//   - `aHandler`: The proxy handler whose routes are updated.
+//   - `aLogger`: Used to report sync problems.
+//
+// Returns:
+//   - `*TDockerWatcher`: The new (not yet started) watcher.
+func NewDockerWatcher(aConfig *TDockerConfig, aHandler *TProxyHandler, aLogger *TLogger) *TDockerWatcher {
+	socket := aConfig.Socket
+	if "" == socket {
+		socket = `/var/run/docker.sock`
+	}
+	interval := time.Duration(aConfig.IntervalSec) * time.Second
+	if 0 >= interval {
+		interval = 15 * time.Second
+	}
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(aCtx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(aCtx, `unix`, socket)
+			},
+		},
+	}
+
+	return &TDockerWatcher{
+		client:   client,
+		interval: interval,
+		handler:  aHandler,
+		logger:   aLogger,
+		done:     make(chan struct{}),
+		applied:  make(map[string]string),
+	}
+} // NewDockerWatcher()
+
+// --------------------------------------------------------------------------
+// TDockerWatcher methods:
+
+// `Start()` runs the periodic sync rounds until `Stop()` is called.
+func (dw *TDockerWatcher) Start() {
+	go func() {
+		dw.sync()
+		ticker := time.NewTicker(dw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				dw.sync()
+			case <-dw.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the periodic sync rounds.
+func (dw *TDockerWatcher) Stop() {
+	close(dw.done)
+} // Stop()
+
+// `fetch()` lists the labelled running containers, returning the
+// hostname to backend URL pairs they announce.
+func (dw *TDockerWatcher) fetch() (map[string]string, error) {
+	resp, err := dw.client.Get(`http://docker/containers/json`)
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("docker answered %s", resp.Status)
+	}
+
+	var containers []tDockerContainer
+	if err = json.NewDecoder(resp.Body).Decode(&containers); nil != err {
+		return nil, err
+	}
+
+	routes := make(map[string]string)
+	for _, container := range containers {
+		hostname := container.Labels[dockerHostLabel]
+		if "" == hostname {
+			continue
+		}
+		port := container.Labels[dockerPortLabel]
+		if "" == port {
+			port = `80`
+		}
+		for _, network := range container.NetworkSettings.Networks {
+			if "" != network.IPAddress {
+				routes[hostname] = `http://` +
+					net.JoinHostPort(network.IPAddress, port)
+				break
+			}
+		}
+	}
+
+	return routes, nil
+} // fetch()
+
+// `sync()` applies the discovered container routes, adding,
+// updating, and removing only what changed since last time.
+func (dw *TDockerWatcher) sync() {
+	routes, err := dw.fetch()
+	if nil != err {
+		dw.logger.Error(`docker`, `route sync: `+err.Error())
+		return
+	}
+
+	dw.mtx.Lock()
+	defer dw.mtx.Unlock()
+	for hostname, target := range routes {
+		if dw.applied[hostname] == target {
+			continue
+		}
+		if err = dw.handler.AddRoute(hostname, target); nil != err {
+			dw.logger.Error(`docker`, fmt.Sprintf(
+				"route %q: %v", hostname, err))
+			continue
+		}
+		dw.applied[hostname] = target
+	}
+	for hostname := range dw.applied {
+		if _, ok := routes[hostname]; !ok {
+			dw.handler.RemoveRoute(hostname)
+			delete(dw.applied, hostname)
+		}
+	}
+} // sync()
+
+/* _EoF_ */
@@ -0,0 +1,38 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import "errors"
+
+// The package's error categories; all errors returned by the proxy
+// wrap one of these sentinels, so embedders can branch on the
+// category with `errors.Is()` instead of matching message strings.
+var (
+	// `ErrNoRoute` is returned when an operation names a virtual
+	// host that has no route configured.
+	ErrNoRoute = errors.New(`no route configured`)
+
+	// `ErrInvalidTarget` is returned when a backend target is not
+	// a usable URL.
+	ErrInvalidTarget = errors.New(`invalid backend URL`)
+
+	// `ErrUnknownSlot` is returned when a slot switch names a
+	// deployment slot the host doesn't define.
+	ErrUnknownSlot = errors.New(`unknown deployment slot`)
+
+	// `ErrConfigInvalid` is returned when a configuration file
+	// can't be read, parsed, or fails validation.
+	ErrConfigInvalid = errors.New(`invalid configuration`)
+
+	// `ErrBackendDown` categorizes failed backend contacts; the
+	// proxy's error handler wraps transport errors with it.
+	ErrBackendDown = errors.New(`backend unreachable`)
+)
+
+/* _EoF_ */
@@ -0,0 +1,36 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorCategories(t *testing.T) {
+	ph, _ := NewProxyHandler()
+
+	if err := ph.AddRoute(`example.org`, `:not a url`); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf(`AddRoute() error = %v, want ErrInvalidTarget`, err)
+	}
+	if err := ph.SwitchSlot(`unknown.example`, ``); !errors.Is(err, ErrNoRoute) {
+		t.Errorf(`SwitchSlot() error = %v, want ErrNoRoute`, err)
+	}
+	if err := ph.AddRoute(`example.org`, `http://127.0.0.1:8080`); nil != err {
+		t.Fatalf(`AddRoute() error = %v`, err)
+	}
+	if err := ph.SwitchSlot(`example.org`, `green`); !errors.Is(err, ErrUnknownSlot) {
+		t.Errorf(`SwitchSlot() error = %v, want ErrUnknownSlot`, err)
+	}
+	if _, err := LoadConfig(`/nonexistent/reprox.json`); !errors.Is(err, ErrConfigInvalid) {
+		t.Errorf(`LoadConfig() error = %v, want ErrConfigInvalid`, err)
+	}
+} // TestErrorCategories()
+
+/* _EoF_ */
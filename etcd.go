@@ -0,0 +1,200 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// `TEtcdConfig` connects the routing table to an etcd cluster
+	// so several proxy instances share one dynamic route set.
+	TEtcdConfig struct {
+		// Endpoint is the etcd v3 HTTP gateway, e.g.
+		// `http://127.0.0.1:2379`.
+		Endpoint string `json:"endpoint"`
+
+		// Prefix is the key prefix holding the routes; each key
+		// below it is a hostname whose value is the backend URL
+		// (default `/reprox/routes/`).
+		Prefix string `json:"prefix,omitempty"`
+
+		// IntervalSec is the polling interval (default 15).
+		IntervalSec int `json:"intervalSec,omitempty"`
+	}
+
+	// `TEtcdWatcher` keeps the proxy's routing table in sync with
+	// the routes stored below an etcd key prefix.
+	//
+	// Routes from etcd are applied on top of the locally configured
+	// hosts; while etcd is unreachable the last known route set
+	// (and the local configuration) keeps being served.
+	TEtcdWatcher struct {
+		mtx      sync.Mutex
+		client   *http.Client
+		endpoint string
+		prefix   string
+		interval time.Duration
+		handler  *TProxyHandler
+		logger   *TLogger
+		done     chan struct{}
+		applied  map[string]string // hostname -> target, as last applied
+	}
+
+	// `tEtcdKV` is one key/value pair of an etcd range response.
+	tEtcdKV struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewEtcdWatcher()` creates a watcher syncing `aHandler`'s routes
+// with the etcd prefix configured in `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The etcd connection settings.
+//   - `aHandler`: The proxy handler whose routes are updated.
+//   - `aLogger`: Used to report sync problems.
+//
+// Returns:
+//   - `*TEtcdWatcher`: The new (not yet started) watcher.
+func NewEtcdWatcher(aConfig *TEtcdConfig, aHandler *TProxyHandler, aLogger *TLogger) *TEtcdWatcher {
+	prefix := aConfig.Prefix
+	if "" == prefix {
+		prefix = `/reprox/routes/`
+	}
+	interval := time.Duration(aConfig.IntervalSec) * time.Second
+	if 0 >= interval {
+		interval = 15 * time.Second
+	}
+
+	return &TEtcdWatcher{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: aConfig.Endpoint,
+		prefix:   prefix,
+		interval: interval,
+		handler:  aHandler,
+		logger:   aLogger,
+		done:     make(chan struct{}),
+		applied:  make(map[string]string),
+	}
+} // NewEtcdWatcher()
+
+// --------------------------------------------------------------------------
+// TEtcdWatcher methods:
+
+// `Start()` runs the periodic sync rounds until `Stop()` is called.
+func (ew *TEtcdWatcher) Start() {
+	go func() {
+		ew.sync()
+		ticker := time.NewTicker(ew.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ew.sync()
+			case <-ew.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the periodic sync rounds.
+func (ew *TEtcdWatcher) Stop() {
+	close(ew.done)
+} // Stop()
+
+// `fetch()` reads all routes below the configured prefix from etcd.
+func (ew *TEtcdWatcher) fetch() (map[string]string, error) {
+	// the range end is the prefix with its last byte incremented:
+	rangeEnd := []byte(ew.prefix)
+	rangeEnd[len(rangeEnd)-1]++
+	body, _ := json.Marshal(map[string]string{
+		`key`:       base64.StdEncoding.EncodeToString([]byte(ew.prefix)),
+		`range_end`: base64.StdEncoding.EncodeToString(rangeEnd),
+	})
+
+	resp, err := ew.client.Post(ew.endpoint+`/v3/kv/range`,
+		`application/json`, bytes.NewReader(body))
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("etcd answered %s", resp.Status)
+	}
+
+	var answer struct {
+		KVs []tEtcdKV `json:"kvs"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&answer); nil != err {
+		return nil, err
+	}
+
+	routes := make(map[string]string, len(answer.KVs))
+	for _, kv := range answer.KVs {
+		key, err := base64.StdEncoding.DecodeString(kv.Key)
+		if nil != err {
+			continue
+		}
+		value, err := base64.StdEncoding.DecodeString(kv.Value)
+		if nil != err {
+			continue
+		}
+		hostname := string(key[len(ew.prefix):])
+		if ("" != hostname) && (0 < len(value)) {
+			routes[hostname] = string(value)
+		}
+	}
+
+	return routes, nil
+} // fetch()
+
+// `sync()` applies the etcd route set on top of the local routes,
+// adding, updating, and removing only what changed since last time.
+func (ew *TEtcdWatcher) sync() {
+	routes, err := ew.fetch()
+	if nil != err {
+		// local fallback: keep serving the current route set
+		ew.logger.Error(`etcd`, `route sync: `+err.Error())
+		return
+	}
+
+	ew.mtx.Lock()
+	defer ew.mtx.Unlock()
+	for hostname, target := range routes {
+		if ew.applied[hostname] == target {
+			continue
+		}
+		if err = ew.handler.AddRoute(hostname, target); nil != err {
+			ew.logger.Error(`etcd`, fmt.Sprintf(
+				"route %q: %v", hostname, err))
+			continue
+		}
+		ew.applied[hostname] = target
+	}
+	for hostname := range ew.applied {
+		if _, ok := routes[hostname]; !ok {
+			ew.handler.RemoveRoute(hostname)
+			delete(ew.applied, hostname)
+		}
+	}
+} // sync()
+
+/* _EoF_ */
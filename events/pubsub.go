@@ -0,0 +1,383 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// Package events provides the small in-process pub/sub bus used to
+// broadcast proxy lifecycle events - configuration reloads, backend
+// health transitions, shutdown - to interested subsystems without
+// coupling them to each other.
+package events
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// The topics published by the proxy itself; embedders may add their
+// own topics freely.
+const (
+	// TopicReload is published after a configuration reload.
+	TopicReload = `config-reload`
+
+	// TopicHealth is published on backend health transitions.
+	TopicHealth = `backend-health`
+
+	// TopicFailover is published on promotion resp. demotion of a
+	// standby instance.
+	TopicFailover = `failover`
+
+	// TopicShutdown is published when the process starts draining.
+	TopicShutdown = `shutdown`
+)
+
+// `TDeliveryPolicy` selects how `Publish()` behaves when a
+// subscriber's channel buffer is full.
+type TDeliveryPolicy uint8
+
+const (
+	// DeliverDropOldest evicts the oldest queued message to make
+	// room for the new one (the default).
+	DeliverDropOldest = TDeliveryPolicy(iota)
+
+	// DeliverDrop discards the new message.
+	DeliverDrop
+
+	// DeliverBlock waits until the subscriber has room; a slow
+	// subscriber stalls the publisher and all later subscribers.
+	DeliverBlock
+)
+
+// `defaultBusBuffer` is the channel buffer used by `Subscribe()`.
+const defaultBusBuffer = 16
+
+type (
+	// `TMessage` is one published event.
+	TMessage struct {
+		// Topic is the channel the message was published on.
+		Topic string
+
+		// Data is the topic-specific payload.
+		Data any
+	}
+
+	// `TBusMetrics` counts message deliveries since bus creation.
+	TBusMetrics struct {
+		// Delivered is the number of messages handed to subscribers.
+		Delivered uint64
+
+		// Dropped is the number of messages discarded because a
+		// subscriber's buffer was full.
+		Dropped uint64
+	}
+
+	// `tSubscriber` is the internal state of one subscription.
+	tSubscriber struct {
+		ch     chan TMessage
+		policy TDeliveryPolicy
+	}
+
+	// `TSubscription` is the opaque handle of one registered
+	// subscriber, returned by the `Subscribe…()` methods.
+	TSubscription struct {
+		bus   *TPubSub
+		topic string
+		sub   *tSubscriber
+	}
+
+	// `TPubSub` is a topic-based publish/subscribe bus.
+	//
+	// All methods are safe for concurrent use; a nil bus is a
+	// valid no-op publisher.
+	TPubSub struct {
+		mtx       sync.RWMutex
+		subs      map[string][]*tSubscriber // topic -> subscribers
+		delivered atomic.Uint64
+		dropped   atomic.Uint64
+		closed    bool
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `New()` creates an empty pub/sub bus.
+func New() *TPubSub {
+	return &TPubSub{subs: make(map[string][]*tSubscriber)}
+} // New()
+
+// --------------------------------------------------------------------------
+// TPubSub methods:
+
+// `Close()` drops all subscriptions, closing their channels.
+// Afterwards publishes are silently discarded and new subscriptions
+// receive an already closed channel.
+func (ps *TPubSub) Close() {
+	if nil == ps {
+		return
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	for _, subs := range ps.subs {
+		for _, sub := range subs {
+			close(sub.ch)
+		}
+	}
+	ps.subs = make(map[string][]*tSubscriber)
+	ps.closed = true
+} // Close()
+
+// `Metrics()` returns the delivery counters accumulated so far.
+func (ps *TPubSub) Metrics() TBusMetrics {
+	if nil == ps {
+		return TBusMetrics{}
+	}
+
+	return TBusMetrics{
+		Delivered: ps.delivered.Load(),
+		Dropped:   ps.dropped.Load(),
+	}
+} // Metrics()
+
+// `Publish()` delivers `aData` to all subscribers whose topic or
+// wildcard pattern matches `aTopic`.
+//
+// Parameters:
+//   - `aTopic`: The topic to publish on.
+//   - `aData`: The topic-specific payload.
+func (ps *TPubSub) Publish(aTopic string, aData any) {
+	if nil == ps {
+		return
+	}
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+	if ps.closed {
+		return
+	}
+
+	msg := TMessage{Topic: aTopic, Data: aData}
+	for pattern, subs := range ps.subs {
+		if !topicMatches(pattern, aTopic) {
+			continue
+		}
+		for _, sub := range subs {
+			ps.deliver(sub, msg)
+		}
+	}
+} // Publish()
+
+// `Subscribe()` registers a new subscriber of `aTopic` with the
+// default buffer size and the drop-oldest delivery policy.
+//
+// Topic names may be hierarchical (segments separated by dots), and
+// `aTopic` may be a wildcard pattern: a `*` segment matches exactly
+// one topic segment, while a trailing `*` matches the whole rest of
+// the topic family (e.g. `backend.*` observes every `backend.…`
+// event without enumerating them).
+//
+// Parameters:
+//   - `aTopic`: The topic - or wildcard pattern - to subscribe to.
+//
+// Returns:
+//   - `*TSubscription`: The handle delivering the topic's messages.
+func (ps *TPubSub) Subscribe(aTopic string) *TSubscription {
+	return ps.SubscribeWith(aTopic, defaultBusBuffer, DeliverDropOldest)
+} // Subscribe()
+
+// `SubscribeCtx()` registers a new subscriber of `aTopic` that is
+// automatically unsubscribed - closing its channel - once `aCtx` is
+// cancelled, so request-scoped subscribers can't leak.
+//
+// Parameters:
+//   - `aCtx`: The context bounding the subscription's lifetime.
+//   - `aTopic`: The topic to subscribe to.
+//
+// Returns:
+//   - `*TSubscription`: The handle delivering the topic's messages.
+func (ps *TPubSub) SubscribeCtx(aCtx context.Context, aTopic string) *TSubscription {
+	subscription := ps.Subscribe(aTopic)
+	go func() {
+		<-aCtx.Done()
+		subscription.Cancel()
+	}()
+
+	return subscription
+} // SubscribeCtx()
+
+// `SubscribeWith()` registers a new subscriber of `aTopic` with an
+// explicit channel buffer size and delivery policy.
+//
+// Parameters:
+//   - `aTopic`: The topic to subscribe to.
+//   - `aBuffer`: The channel buffer size (min. 1).
+//   - `aPolicy`: The behaviour when the buffer is full.
+//
+// Returns:
+//   - `*TSubscription`: The handle delivering the topic's messages.
+func (ps *TPubSub) SubscribeWith(aTopic string, aBuffer int, aPolicy TDeliveryPolicy) *TSubscription {
+	if 1 > aBuffer {
+		aBuffer = 1
+	}
+	sub := &tSubscriber{
+		ch:     make(chan TMessage, aBuffer),
+		policy: aPolicy,
+	}
+	ps.mtx.Lock()
+	defer ps.mtx.Unlock()
+
+	if ps.closed {
+		close(sub.ch)
+	} else {
+		ps.subs[aTopic] = append(ps.subs[aTopic], sub)
+	}
+
+	return &TSubscription{bus: ps, topic: aTopic, sub: sub}
+} // SubscribeWith()
+
+// `Subscribers()` returns the number of current subscribers of
+// `aTopic` (counting only this exact topic/pattern, not wildcard
+// overlaps).
+//
+// Parameters:
+//   - `aTopic`: The topic (or pattern) subscribed to.
+//
+// Returns:
+//   - `int`: The number of subscribers.
+func (ps *TPubSub) Subscribers(aTopic string) int {
+	if nil == ps {
+		return 0
+	}
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+
+	return len(ps.subs[aTopic])
+} // Subscribers()
+
+// `Topics()` returns the sorted list of topics (and patterns)
+// currently having at least one subscriber.
+func (ps *TPubSub) Topics() []string {
+	if nil == ps {
+		return nil
+	}
+	ps.mtx.RLock()
+	defer ps.mtx.RUnlock()
+
+	topics := make([]string, 0, len(ps.subs))
+	for topic, subs := range ps.subs {
+		if 0 < len(subs) {
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+
+	return topics
+} // Topics()
+
+// --------------------------------------------------------------------------
+// TSubscription methods:
+
+// `C()` returns the channel delivering the subscription's messages.
+// It is closed by `Cancel()` and by the bus' `Close()`.
+func (sn *TSubscription) C() <-chan TMessage {
+	if nil == sn {
+		return nil
+	}
+
+	return sn.sub.ch
+} // C()
+
+// `Cancel()` removes the subscription from its bus and closes the
+// message channel; calling it more than once is harmless.
+func (sn *TSubscription) Cancel() {
+	if (nil == sn) || (nil == sn.bus) {
+		return
+	}
+	sn.bus.mtx.Lock()
+	defer sn.bus.mtx.Unlock()
+
+	subs := sn.bus.subs[sn.topic]
+	for idx, sub := range subs {
+		// comparing the internal pointer (rather than the channel)
+		// identifies the subscription unambiguously:
+		if sn.sub == sub {
+			sn.bus.subs[sn.topic] = append(subs[:idx], subs[idx+1:]...)
+			close(sub.ch)
+			return
+		}
+	}
+} // Cancel()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `deliver()` hands `aMessage` to `aSub` honouring its delivery
+// policy, updating the bus' delivery counters.
+func (ps *TPubSub) deliver(aSub *tSubscriber, aMessage TMessage) {
+	if DeliverBlock == aSub.policy {
+		aSub.ch <- aMessage
+		ps.delivered.Add(1)
+		return
+	}
+
+	select {
+	case aSub.ch <- aMessage:
+		ps.delivered.Add(1)
+		return
+	default:
+	}
+	if DeliverDropOldest == aSub.policy {
+		// evict the oldest queued message and retry once; with a
+		// concurrent reader the retry may still find the buffer
+		// full, in which case the new message is dropped:
+		select {
+		case <-aSub.ch:
+			ps.dropped.Add(1)
+		default:
+		}
+		select {
+		case aSub.ch <- aMessage:
+			ps.delivered.Add(1)
+			return
+		default:
+		}
+	}
+	ps.dropped.Add(1)
+} // deliver()
+
+// `topicMatches()` reports whether the published `aTopic` falls
+// under the subscription `aPattern`.
+func topicMatches(aPattern, aTopic string) bool {
+	if aPattern == aTopic {
+		return true
+	}
+	if !strings.Contains(aPattern, `*`) {
+		return false
+	}
+
+	pParts := strings.Split(aPattern, `.`)
+	tParts := strings.Split(aTopic, `.`)
+	for idx, part := range pParts {
+		if `*` == part {
+			if idx == (len(pParts) - 1) {
+				// a trailing `*` matches the remaining segment(s):
+				return idx < len(tParts)
+			}
+			continue // exactly one segment
+		}
+		if (idx >= len(tParts)) || (part != tParts[idx]) {
+			return false
+		}
+	}
+
+	return len(pParts) == len(tParts)
+} // topicMatches()
+
+/* _EoF_ */
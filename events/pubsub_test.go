@@ -0,0 +1,192 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package events
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPubSub(t *testing.T) {
+	bus := New()
+	sub1 := bus.Subscribe(TopicReload)
+	sub2 := bus.Subscribe(TopicReload)
+	other := bus.Subscribe(TopicHealth)
+
+	bus.Publish(TopicReload, 3)
+	for _, sub := range []*TSubscription{sub1, sub2} {
+		msg := <-sub.C()
+		if (TopicReload != msg.Topic) || (3 != msg.Data) {
+			t.Errorf(`received %+v, want {%s 3}`, msg, TopicReload)
+		}
+	}
+	select {
+	case msg := <-other.C():
+		t.Errorf(`health subscriber received %+v`, msg)
+	default:
+	}
+
+	// cancelled subscriptions are closed and no longer delivered to:
+	sub1.Cancel()
+	if _, open := <-sub1.C(); open {
+		t.Error(`cancelled channel wasn't closed`)
+	}
+	sub1.Cancel() // a second Cancel() must be a no-op
+	bus.Publish(TopicReload, 4)
+	if msg := <-sub2.C(); 4 != msg.Data {
+		t.Errorf(`remaining subscriber received %+v`, msg)
+	}
+
+	// closing the bus closes all remaining subscriptions:
+	bus.Close()
+	if _, open := <-sub2.C(); open {
+		t.Error(`Close() left a subscription open`)
+	}
+	sub2.Cancel() // harmless after Close()
+
+	// a nil bus is a valid no-op publisher:
+	var nilBus *TPubSub
+	nilBus.Publish(TopicShutdown, nil)
+	nilBus.Close()
+	var nilSub *TSubscription
+	nilSub.Cancel()
+	if nil != nilSub.C() {
+		t.Error(`nil subscription returned a channel`)
+	}
+} // TestPubSub()
+
+func TestDeliveryPolicies(t *testing.T) {
+	bus := New()
+
+	// drop-oldest: a full 1-slot buffer keeps only the newest message:
+	oldest := bus.SubscribeWith(TopicReload, 1, DeliverDropOldest)
+	bus.Publish(TopicReload, 1)
+	bus.Publish(TopicReload, 2)
+	if msg := <-oldest.C(); 2 != msg.Data {
+		t.Errorf(`drop-oldest delivered %+v, want 2`, msg)
+	}
+	oldest.Cancel()
+
+	// drop: a full buffer discards the new message:
+	drop := bus.SubscribeWith(TopicReload, 1, DeliverDrop)
+	bus.Publish(TopicReload, 3)
+	bus.Publish(TopicReload, 4)
+	if msg := <-drop.C(); 3 != msg.Data {
+		t.Errorf(`drop delivered %+v, want 3`, msg)
+	}
+	select {
+	case msg := <-drop.C():
+		t.Errorf(`drop delivered %+v, want nothing`, msg)
+	default:
+	}
+
+	metrics := bus.Metrics()
+	if (3 != metrics.Delivered) || (2 != metrics.Dropped) {
+		t.Errorf(`metrics = %+v, want {3 2}`, metrics)
+	}
+	if m := (*TPubSub)(nil).Metrics(); (0 != m.Delivered) || (0 != m.Dropped) {
+		t.Errorf(`nil bus metrics = %+v, want zero`, m)
+	}
+} // TestDeliveryPolicies()
+
+func TestTopicsLifecycle(t *testing.T) {
+	bus := New()
+	sub1 := bus.Subscribe(TopicReload)
+	_ = bus.Subscribe(TopicReload)
+	_ = bus.Subscribe(TopicHealth)
+
+	topics := bus.Topics()
+	if (2 != len(topics)) ||
+		(TopicHealth != topics[0]) || (TopicReload != topics[1]) {
+		t.Errorf(`Topics() = %v`, topics)
+	}
+	if count := bus.Subscribers(TopicReload); 2 != count {
+		t.Errorf(`Subscribers(%s) = %d, want 2`, TopicReload, count)
+	}
+	sub1.Cancel()
+	if count := bus.Subscribers(TopicReload); 1 != count {
+		t.Errorf(`Subscribers(%s) = %d, want 1`, TopicReload, count)
+	}
+
+	// a closed bus discards publishes and hands out closed channels:
+	bus.Close()
+	bus.Publish(TopicReload, `ignored`)
+	if 0 != len(bus.Topics()) {
+		t.Errorf(`Topics() after Close() = %v`, bus.Topics())
+	}
+	if _, open := <-bus.Subscribe(TopicReload).C(); open {
+		t.Error(`Subscribe() on closed bus returned an open channel`)
+	}
+
+	var nilBus *TPubSub
+	if (nil != nilBus.Topics()) || (0 != nilBus.Subscribers(TopicReload)) {
+		t.Error(`nil bus reported subscriptions`)
+	}
+} // TestTopicsLifecycle()
+
+func TestTopicMatches(t *testing.T) {
+	cases := []struct {
+		pattern, topic string
+		want           bool
+	}{
+		{`backend.health`, `backend.health`, true},
+		{`backend.health`, `backend.eject`, false},
+		{`backend.*`, `backend.health`, true},
+		{`backend.*`, `backend.health.eject`, true},
+		{`backend.*`, `backend`, false},
+		{`backend.*.eject`, `backend.health.eject`, true},
+		{`backend.*.eject`, `backend.health.readmit`, false},
+		{`backend.*.eject`, `backend.eject`, false},
+		{`*`, `anything`, true},
+		{`*.health`, `backend.health`, true},
+		{`*.health`, `health`, false},
+	}
+	for _, tc := range cases {
+		if got := topicMatches(tc.pattern, tc.topic); got != tc.want {
+			t.Errorf(`topicMatches(%q, %q) = %v, want %v`,
+				tc.pattern, tc.topic, got, tc.want)
+		}
+	}
+
+	// a wildcard subscriber observes the whole event family:
+	bus := New()
+	family := bus.Subscribe(`backend.*`)
+	bus.Publish(`backend.health.eject`, `host1`)
+	if msg := <-family.C(); `backend.health.eject` != msg.Topic {
+		t.Errorf(`wildcard subscriber received %+v`, msg)
+	}
+} // TestTopicMatches()
+
+func TestSubscribeCtx(t *testing.T) {
+	bus := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	sub := bus.SubscribeCtx(ctx, TopicReload)
+
+	bus.Publish(TopicReload, 1)
+	if msg := <-sub.C(); 1 != msg.Data {
+		t.Errorf(`received %+v, want 1`, msg)
+	}
+
+	// cancelling the context must close the channel:
+	cancel()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, open := <-sub.C():
+			if !open {
+				return
+			}
+		case <-deadline:
+			t.Fatal(`channel not closed after context cancellation`)
+		}
+	}
+} // TestSubscribeCtx()
+
+/* _EoF_ */
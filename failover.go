@@ -0,0 +1,297 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/mwat56/reprox/events"
+)
+
+// This file implements VRRP-style active/standby failover without
+// touching the network stack itself: the standby instance heartbeats
+// the active one over its admin API and, after a configured number of
+// missed beats, promotes itself by running an external script - which
+// typically moves a floating IP or updates a DNS record.  When the
+// peer comes back the standby demotes itself again through the
+// matching script, so the actual address takeover stays in the
+// operator's hands while reprox only provides the health signalling.
+
+type (
+	// `TFailoverConfig` describes the standby role watching an
+	// active peer; the section is only needed on the standby.
+	TFailoverConfig struct {
+		// PeerURL is the base URL of the active peer's admin API,
+		// e.g. `https://lead.mwat.de:8404`.
+		PeerURL string `json:"peerURL"`
+
+		// Token is the bearer token for the peer's admin API; a
+		// read-only token suffices.
+		Token string `json:"token"`
+
+		// IntervalSec is the heartbeat interval (default 2).
+		IntervalSec int `json:"intervalSec,omitempty"`
+
+		// FailAfter is the number of consecutively missed
+		// heartbeats triggering the promotion (default 3).
+		FailAfter int `json:"failAfter,omitempty"`
+
+		// OnPromote/OnDemote are external scripts run on the state
+		// transitions (e.g. moving a floating IP); each is called
+		// with the event name (`promote` resp. `demote`) as its
+		// only argument and the peer URL in `$REPROX_PEER`.
+		OnPromote string `json:"onPromote,omitempty"`
+		OnDemote  string `json:"onDemote,omitempty"`
+	}
+
+	// `TFailoverStatus` is the standby's failover state as reported
+	// by `GET /api/failover`.
+	TFailoverStatus struct {
+		Peer        string    `json:"peer"`
+		Active      bool      `json:"active"`
+		Failures    int       `json:"failures"`
+		LastContact time.Time `json:"lastContact"`
+		LastChange  time.Time `json:"lastChange"`
+	}
+
+	// `TFailoverMonitor` heartbeats the active peer and runs the
+	// promotion resp. demotion hooks on its state transitions.
+	TFailoverMonitor struct {
+		mtx      sync.RWMutex
+		conf     *TFailoverConfig
+		handler  *TProxyHandler
+		logger   *TLogger
+		client   *http.Client
+		interval time.Duration
+		done     chan struct{}
+		status   TFailoverStatus
+	}
+)
+
+// `failoverHookTimeout` limits how long a promotion resp. demotion
+// script may run.
+const failoverHookTimeout = 30 * time.Second
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewFailoverMonitor()` creates a standby monitor heartbeating the
+// active peer given in `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The peer address, thresholds, and hook scripts.
+//   - `aHandler`: The local proxy handler (its event bus receives
+//     the `failover` transitions).
+//   - `aLogger`: Used for heartbeat and hook problems.
+//
+// Returns:
+//   - `*TFailoverMonitor`: The new (not yet started) monitor.
+//   - `error`: An error for incomplete configurations.
+func NewFailoverMonitor(aConfig *TFailoverConfig, aHandler *TProxyHandler, aLogger *TLogger) (*TFailoverMonitor, error) {
+	if ("" == aConfig.PeerURL) || ("" == aConfig.Token) {
+		return nil, fmt.Errorf("failover needs a peerURL and token")
+	}
+	interval := time.Duration(aConfig.IntervalSec) * time.Second
+	if 0 >= interval {
+		interval = 2 * time.Second
+	}
+	if 0 >= aConfig.FailAfter {
+		aConfig.FailAfter = 3
+	}
+
+	return &TFailoverMonitor{
+		conf:     aConfig,
+		handler:  aHandler,
+		logger:   aLogger,
+		client:   &http.Client{Timeout: interval},
+		interval: interval,
+		done:     make(chan struct{}),
+		status:   TFailoverStatus{Peer: aConfig.PeerURL},
+	}, nil
+} // NewFailoverMonitor()
+
+// --------------------------------------------------------------------------
+// TFailoverMonitor methods:
+
+// `Promote()` forces this instance into the active role, running the
+// promotion hook; used by the heartbeat loop and the admin API.
+func (fm *TFailoverMonitor) Promote() {
+	fm.transition(true)
+} // Promote()
+
+// `Demote()` forces this instance back into the standby role,
+// running the demotion hook.
+func (fm *TFailoverMonitor) Demote() {
+	fm.transition(false)
+} // Demote()
+
+// `Start()` runs the heartbeat rounds until `Stop()` is called.
+func (fm *TFailoverMonitor) Start() {
+	go func() {
+		fm.heartbeat()
+		ticker := time.NewTicker(fm.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				fm.heartbeat()
+			case <-fm.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Status()` returns a copy of the current failover state.
+func (fm *TFailoverMonitor) Status() TFailoverStatus {
+	fm.mtx.RLock()
+	defer fm.mtx.RUnlock()
+
+	return fm.status
+} // Status()
+
+// `Stop()` terminates the heartbeat rounds.
+func (fm *TFailoverMonitor) Stop() {
+	close(fm.done)
+} // Stop()
+
+// `heartbeat()` probes the peer once, promoting after the configured
+// number of consecutive misses and demoting once the peer answers
+// again.
+func (fm *TFailoverMonitor) heartbeat() {
+	alive := fm.probe()
+
+	fm.mtx.Lock()
+	if alive {
+		fm.status.Failures = 0
+		fm.status.LastContact = time.Now()
+	} else {
+		fm.status.Failures++
+	}
+	promote := (!alive) && (!fm.status.Active) &&
+		(fm.status.Failures >= fm.conf.FailAfter)
+	demote := alive && fm.status.Active
+	fm.mtx.Unlock()
+
+	if promote {
+		fm.transition(true)
+	} else if demote {
+		fm.transition(false)
+	}
+} // heartbeat()
+
+// `probe()` sends one heartbeat request to the peer's liveness
+// endpoint.
+func (fm *TFailoverMonitor) probe() bool {
+	request, err := http.NewRequest(http.MethodGet,
+		fm.conf.PeerURL+`/healthz`, nil)
+	if nil != err {
+		return false
+	}
+	request.Header.Set(`Authorization`, `Bearer `+fm.conf.Token)
+	response, err := fm.client.Do(request)
+	if nil != err {
+		return false
+	}
+	_ = response.Body.Close()
+
+	return http.StatusOK == response.StatusCode
+} // probe()
+
+// `runHook()` executes one promotion resp. demotion script, logging
+// its output on failure.
+func (fm *TFailoverMonitor) runHook(aScript, aEvent string) {
+	if "" == aScript {
+		return
+	}
+	ctx, cancel := context.WithTimeout(
+		context.Background(), failoverHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, aScript, aEvent)
+	cmd.Env = append(cmd.Environ(), `REPROX_PEER=`+fm.conf.PeerURL)
+	if output, err := cmd.CombinedOutput(); nil != err {
+		fm.logger.Error(``, fmt.Sprintf("failover %s hook %q: %v: %s",
+			aEvent, aScript, err, output))
+	}
+} // runHook()
+
+// `transition()` switches between the standby and active roles,
+// running the respective hook and publishing the event.
+func (fm *TFailoverMonitor) transition(aActive bool) {
+	fm.mtx.Lock()
+	if aActive == fm.status.Active {
+		fm.mtx.Unlock()
+		return
+	}
+	fm.status.Active = aActive
+	fm.status.LastChange = time.Now()
+	fm.mtx.Unlock()
+
+	event := `demote`
+	script := fm.conf.OnDemote
+	if aActive {
+		event, script = `promote`, fm.conf.OnPromote
+	}
+	fm.logger.Error(``, `failover: `+event+` (peer `+fm.conf.PeerURL+`)`)
+	fm.runHook(script, event)
+	fm.handler.Bus().Publish(events.TopicFailover, event)
+} // transition()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `SetFailover()` hands the admin API the failover monitor backing
+// the `/api/failover` endpoint.
+func (ad *TAdmin) SetFailover(aMonitor *TFailoverMonitor) {
+	ad.failover = aMonitor
+} // SetFailover()
+
+// `failoverState()` handles `GET|POST /api/failover` reading the
+// failover state resp. forcing a transition via
+// `POST /api/failover?state=active|standby` (e.g. for a planned
+// switch-over during maintenance).
+func (ad *TAdmin) failoverState(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if nil == ad.failover {
+		http.Error(aWriter, `no failover monitor running`,
+			http.StatusNotImplemented)
+		return
+	}
+
+	switch aRequest.Method {
+	case http.MethodGet:
+		sendJSON(aWriter, ad.failover.Status())
+
+	case http.MethodPost:
+		state := aRequest.FormValue(`state`)
+		switch state {
+		case `active`:
+			ad.failover.Promote()
+		case `standby`:
+			ad.failover.Demote()
+		default:
+			http.Error(aWriter, `state must be active or standby`,
+				http.StatusBadRequest)
+			return
+		}
+		ad.audit.Log(aRequest.RemoteAddr, `failover`, `forced `+state)
+		ad.stream.Publish(`failover`, `forced `+state)
+		sendJSON(aWriter, ad.failover.Status())
+
+	default:
+		methodNotAllowed(aWriter, http.MethodGet, http.MethodPost)
+	}
+} // failoverState()
+
+/* _EoF_ */
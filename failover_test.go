@@ -0,0 +1,102 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mwat56/reprox/events"
+)
+
+func TestFailoverPromotion(t *testing.T) {
+	var down atomic.Bool
+	peer := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			if down.Load() {
+				aWriter.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = aWriter.Write([]byte(`{"status":"ok"}`))
+		}))
+	defer peer.Close()
+
+	dir := t.TempDir()
+	hookLog := filepath.Join(dir, `events.log`)
+	script := filepath.Join(dir, `hook.sh`)
+	if err := os.WriteFile(script,
+		[]byte("#!/bin/sh\necho \"$1\" >> "+hookLog+"\n"),
+		0o700); nil != err {
+		t.Fatalf(`writing hook script failed: %v`, err)
+	}
+
+	ph, _ := NewProxyHandler()
+	monitor, err := NewFailoverMonitor(&TFailoverConfig{
+		PeerURL: peer.URL, Token: `secret`, FailAfter: 2,
+		OnPromote: script, OnDemote: script}, ph, &TLogger{})
+	if nil != err {
+		t.Fatalf(`NewFailoverMonitor() failed: %v`, err)
+	}
+	sub := ph.Bus().Subscribe(events.TopicFailover)
+	defer sub.Cancel()
+
+	monitor.heartbeat() // peer alive: stay standby
+	if status := monitor.Status(); status.Active ||
+		status.LastContact.IsZero() {
+		t.Fatalf(`Status() after contact = %+v`, status)
+	}
+
+	down.Store(true)
+	monitor.heartbeat() // first miss: below the threshold
+	if monitor.Status().Active {
+		t.Fatal(`promoted after a single missed heartbeat`)
+	}
+	monitor.heartbeat() // second miss: promote
+	if !monitor.Status().Active {
+		t.Fatal(`not promoted after reaching the threshold`)
+	}
+	select {
+	case msg := <-sub.C():
+		if `promote` != msg.Data {
+			t.Errorf(`failover event = %v, want promote`, msg.Data)
+		}
+	case <-time.After(time.Second):
+		t.Error(`no failover event published`)
+	}
+
+	down.Store(false)
+	monitor.heartbeat() // peer back: demote
+	if monitor.Status().Active {
+		t.Fatal(`still active after the peer's return`)
+	}
+
+	if logged, err := os.ReadFile(hookLog); (nil != err) ||
+		("promote\ndemote\n" != string(logged)) {
+		t.Errorf(`hook log = (%q, %v), want promote+demote`, logged, err)
+	}
+} // TestFailoverPromotion()
+
+func TestFailoverConfig(t *testing.T) {
+	ph, _ := NewProxyHandler()
+	if _, err := NewFailoverMonitor(&TFailoverConfig{
+		PeerURL: `http://peer:8404`}, ph, &TLogger{}); nil == err {
+		t.Error(`NewFailoverMonitor() accepted a configuration without token`)
+	}
+	if _, err := NewFailoverMonitor(&TFailoverConfig{
+		Token: `secret`}, ph, &TLogger{}); nil == err {
+		t.Error(`NewFailoverMonitor() accepted a configuration without peer`)
+	}
+} // TestFailoverConfig()
+
+/* _EoF_ */
@@ -0,0 +1,3 @@
+module github.com/mwat56/reprox
+
+go 1.21
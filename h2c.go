@@ -0,0 +1,575 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `tH2CTransport` is a prior-knowledge HTTP/2 cleartext (h2c)
+	// client (RFC 9113) for backends that only speak HTTP/2
+	// without TLS, e.g. gRPC services behind this proxy.
+	//
+	// The implementation is deliberately small: one connection
+	// per request, no stream multiplexing, no server push, and
+	// response trailers are not forwarded.
+	tH2CTransport struct {
+		dialTimeout time.Duration
+	}
+
+	// `tH2Frame` is one HTTP/2 frame as read off the wire.
+	tH2Frame struct {
+		typ     byte
+		flags   byte
+		stream  uint32
+		payload []byte
+	}
+
+	// `tH2CConn` is the per-request connection state: the send
+	// windows are guarded by `mtx`, and `cond` wakes the body
+	// writer once the backend grants more window.
+	tH2CConn struct {
+		conn          net.Conn
+		mtx           sync.Mutex
+		cond          *sync.Cond
+		err           error // sticky; set once the connection is dead
+		connWindow    int
+		streamWindow  int
+		initialWindow int
+	}
+
+	// `tH2CBody` hands the piped response body to the caller and
+	// tears the connection down on `Close()`.
+	tH2CBody struct {
+		reader *io.PipeReader
+		hc     *tH2CConn
+	}
+
+	// `tH2Result` delivers the response (or the fatal error) from
+	// the frame reader to `RoundTrip()`.
+	tH2Result struct {
+		resp *http.Response
+		err  error
+	}
+)
+
+const (
+	// `h2cPreface` opens every client connection (RFC 9113 §3.4).
+	h2cPreface = "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"
+
+	// frame types (RFC 9113 §6):
+	h2FrameData         = 0x0
+	h2FrameHeaders      = 0x1
+	h2FrameRstStream    = 0x3
+	h2FrameSettings     = 0x4
+	h2FramePing         = 0x6
+	h2FrameGoAway       = 0x7
+	h2FrameWindowUpdate = 0x8
+	h2FrameContinuation = 0x9
+
+	// frame flags:
+	h2FlagAck        = 0x01 // SETTINGS, PING
+	h2FlagEndStream  = 0x01 // DATA, HEADERS
+	h2FlagEndHeaders = 0x04
+	h2FlagPadded     = 0x08
+	h2FlagPriority   = 0x20
+
+	// `h2SettingInitialWindowSize` is the only SETTINGS parameter
+	// this client needs to act upon.
+	h2SettingInitialWindowSize = 0x4
+
+	// `h2DefaultWindow` is the initial flow control window of
+	// both the connection and each stream (RFC 9113 §6.9.2).
+	h2DefaultWindow = 65535
+
+	// `h2MaxFrameSize` is the largest frame payload every peer
+	// must accept, used as our send limit.
+	h2MaxFrameSize = 16384
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newH2CTransport()` returns the transport handed to reverse
+// proxies whose backend is configured for cleartext HTTP/2.
+func newH2CTransport() *tH2CTransport {
+	return &tH2CTransport{
+		dialTimeout: 10 * time.Second,
+	}
+} // newH2CTransport()
+
+// --------------------------------------------------------------------------
+// tH2CTransport methods:
+
+// `RoundTrip()` implements `http.RoundTripper` by speaking
+// prior-knowledge h2c on a fresh connection to the backend.
+//
+// Parameters:
+//   - `aRequest`: The outgoing backend request.
+//
+// Returns:
+//   - `*http.Response`: The backend's response.
+//   - `error`: A connection or protocol failure.
+func (ht *tH2CTransport) RoundTrip(aRequest *http.Request) (*http.Response, error) {
+	if `http` != aRequest.URL.Scheme {
+		return nil, fmt.Errorf("h2c: unsupported scheme %q", aRequest.URL.Scheme)
+	}
+	addr := aRequest.URL.Host
+	if "" == aRequest.URL.Port() {
+		addr = net.JoinHostPort(aRequest.URL.Hostname(), `80`)
+	}
+	dialer := net.Dialer{Timeout: ht.dialTimeout}
+	conn, err := dialer.DialContext(aRequest.Context(), `tcp`, addr)
+	if nil != err {
+		return nil, err
+	}
+
+	hc := &tH2CConn{
+		conn:          conn,
+		connWindow:    h2DefaultWindow,
+		streamWindow:  h2DefaultWindow,
+		initialWindow: h2DefaultWindow,
+	}
+	hc.cond = sync.NewCond(&hc.mtx)
+	resp, err := hc.roundTrip(aRequest)
+	if nil != err {
+		hc.fail(err)
+		return nil, err
+	}
+
+	return resp, nil
+} // RoundTrip()
+
+// --------------------------------------------------------------------------
+// tH2CConn methods:
+
+// `applySettings()` handles a backend SETTINGS payload; only the
+// initial window size affects our sending behaviour.
+func (hc *tH2CConn) applySettings(aPayload []byte) {
+	hc.mtx.Lock()
+	defer hc.mtx.Unlock()
+
+	for ; 6 <= len(aPayload); aPayload = aPayload[6:] {
+		id := binary.BigEndian.Uint16(aPayload[:2])
+		value := int(binary.BigEndian.Uint32(aPayload[2:6]))
+		if h2SettingInitialWindowSize == id {
+			hc.streamWindow += value - hc.initialWindow
+			hc.initialWindow = value
+			hc.cond.Broadcast()
+		}
+	}
+} // applySettings()
+
+// `fail()` marks the connection dead with `aErr`, wakes all window
+// waiters, and closes the underlying socket.
+func (hc *tH2CConn) fail(aErr error) {
+	hc.mtx.Lock()
+	if nil == hc.err {
+		hc.err = aErr
+	}
+	hc.cond.Broadcast()
+	hc.mtx.Unlock()
+	_ = hc.conn.Close()
+} // fail()
+
+// `grow()` credits a WINDOW_UPDATE to the connection (stream 0) or
+// our single request stream.
+func (hc *tH2CConn) grow(aStream uint32, aDelta int) {
+	hc.mtx.Lock()
+	if 0 == aStream {
+		hc.connWindow += aDelta
+	} else {
+		hc.streamWindow += aDelta
+	}
+	hc.cond.Broadcast()
+	hc.mtx.Unlock()
+} // grow()
+
+// `readLoop()` consumes backend frames, delivering the response via
+// `aResult` and streaming DATA into `aBody`.
+func (hc *tH2CConn) readLoop(aResult chan<- tH2Result, aBody *io.PipeWriter) {
+	var (
+		block      []byte
+		decoder    tHpackDecoder
+		delivered  bool
+		endsStream bool
+	)
+	bail := func(aErr error) {
+		if delivered {
+			_ = aBody.CloseWithError(aErr)
+		} else {
+			aResult <- tH2Result{err: aErr}
+		}
+		hc.fail(aErr)
+	}
+
+	reader := bufio.NewReader(hc.conn)
+	for {
+		frame, err := h2ReadFrame(reader)
+		if nil != err {
+			bail(err)
+			return
+		}
+		switch frame.typ {
+		case h2FrameSettings:
+			if 0 != (frame.flags & h2FlagAck) {
+				continue
+			}
+			hc.applySettings(frame.payload)
+			_ = hc.writeFrame(h2FrameSettings, h2FlagAck, 0, nil)
+
+		case h2FramePing:
+			if 0 == (frame.flags & h2FlagAck) {
+				_ = hc.writeFrame(h2FramePing, h2FlagAck, 0, frame.payload)
+			}
+
+		case h2FrameWindowUpdate:
+			if 4 == len(frame.payload) {
+				hc.grow(frame.stream,
+					int(binary.BigEndian.Uint32(frame.payload)&0x7fffffff))
+			}
+
+		case h2FrameRstStream, h2FrameGoAway:
+			bail(fmt.Errorf("h2c: stream closed by the backend"))
+			return
+
+		case h2FrameHeaders, h2FrameContinuation:
+			payload := frame.payload
+			if h2FrameHeaders == frame.typ {
+				payload = h2StripPadding(frame.flags, payload, true)
+				endsStream = 0 != (frame.flags & h2FlagEndStream)
+			}
+			block = append(block, payload...)
+			if 0 == (frame.flags & h2FlagEndHeaders) {
+				continue
+			}
+			fields, hErr := decoder.decode(block)
+			block = nil
+			if nil != hErr {
+				bail(hErr)
+				return
+			}
+			if delivered { // trailers aren't forwarded - see doc above
+				if endsStream {
+					_ = aBody.Close()
+					return
+				}
+				continue
+			}
+			resp := h2Response(fields)
+			if nil == resp { // 1xx interim response
+				continue
+			}
+			delivered = true
+			aResult <- tH2Result{resp: resp}
+			if endsStream {
+				_ = aBody.Close()
+				return
+			}
+
+		case h2FrameData:
+			// credit the full payload (incl. padding) back so the
+			// backend never stalls on our receive window:
+			if 0 < len(frame.payload) {
+				hc.sendWindowUpdate(0, len(frame.payload))
+				hc.sendWindowUpdate(frame.stream, len(frame.payload))
+			}
+			data := h2StripPadding(frame.flags, frame.payload, false)
+			if _, wErr := aBody.Write(data); nil != wErr {
+				hc.fail(wErr)
+				return
+			}
+			if 0 != (frame.flags & h2FlagEndStream) {
+				_ = aBody.Close()
+				return
+			}
+		}
+	}
+} // readLoop()
+
+// `roundTrip()` performs the actual exchange once the connection
+// is established.
+func (hc *tH2CConn) roundTrip(aRequest *http.Request) (*http.Response, error) {
+	if _, err := io.WriteString(hc.conn, h2cPreface); nil != err {
+		return nil, err
+	}
+	if err := hc.writeFrame(h2FrameSettings, 0, 0, nil); nil != err {
+		return nil, err
+	}
+
+	endStream := (nil == aRequest.Body) || (http.NoBody == aRequest.Body)
+	block, first := h2RequestBlock(aRequest), true
+	for {
+		chunk := block
+		if h2MaxFrameSize < len(chunk) {
+			chunk = chunk[:h2MaxFrameSize]
+		}
+		block = block[len(chunk):]
+		typ, flags := byte(h2FrameContinuation), byte(0)
+		if first {
+			typ = h2FrameHeaders
+			if endStream {
+				flags |= h2FlagEndStream
+			}
+		}
+		if 0 == len(block) {
+			flags |= h2FlagEndHeaders
+		}
+		if err := hc.writeFrame(typ, flags, 1, chunk); nil != err {
+			return nil, err
+		}
+		if 0 == len(block) {
+			break
+		}
+		first = false
+	}
+
+	result := make(chan tH2Result, 1)
+	bodyReader, bodyWriter := io.Pipe()
+	go hc.readLoop(result, bodyWriter)
+	if !endStream {
+		// any write error here also kills the frame reader, which
+		// then reports the failure through `result`:
+		go func() { _ = hc.sendBody(aRequest.Body) }()
+	}
+
+	select {
+	case got := <-result:
+		if nil != got.err {
+			return nil, got.err
+		}
+		got.resp.Request = aRequest
+		got.resp.Body = &tH2CBody{reader: bodyReader, hc: hc}
+		return got.resp, nil
+
+	case <-aRequest.Context().Done():
+		return nil, aRequest.Context().Err()
+	}
+} // roundTrip()
+
+// `sendBody()` streams the request body as DATA frames, honouring
+// the backend's flow control windows.
+func (hc *tH2CConn) sendBody(aBody io.ReadCloser) error {
+	defer func() { _ = aBody.Close() }()
+
+	buf := make([]byte, h2MaxFrameSize)
+	for {
+		num, rErr := aBody.Read(buf)
+		for sent := 0; sent < num; {
+			avail, wErr := hc.waitWindow(num - sent)
+			if nil != wErr {
+				return wErr
+			}
+			if wErr = hc.writeFrame(h2FrameData, 0, 1,
+				buf[sent:sent+avail]); nil != wErr {
+				return wErr
+			}
+			sent += avail
+		}
+		if io.EOF == rErr {
+			return hc.writeFrame(h2FrameData, h2FlagEndStream, 1, nil)
+		}
+		if nil != rErr {
+			hc.fail(rErr)
+			return rErr
+		}
+	}
+} // sendBody()
+
+// `sendWindowUpdate()` grants `aDelta` receive window on `aStream`
+// back to the backend.
+func (hc *tH2CConn) sendWindowUpdate(aStream uint32, aDelta int) {
+	var payload [4]byte
+	binary.BigEndian.PutUint32(payload[:], uint32(aDelta))
+	_ = hc.writeFrame(h2FrameWindowUpdate, 0, aStream, payload[:])
+} // sendWindowUpdate()
+
+// `waitWindow()` blocks until some send window (at most `aWant`
+// octets) is available and reserves it.
+func (hc *tH2CConn) waitWindow(aWant int) (int, error) {
+	hc.mtx.Lock()
+	defer hc.mtx.Unlock()
+
+	for {
+		if nil != hc.err {
+			return 0, hc.err
+		}
+		if avail := min(hc.connWindow, hc.streamWindow); 0 < avail {
+			if avail > aWant {
+				avail = aWant
+			}
+			hc.connWindow -= avail
+			hc.streamWindow -= avail
+			return avail, nil
+		}
+		hc.cond.Wait()
+	}
+} // waitWindow()
+
+// `writeFrame()` serialises one frame onto the wire; concurrent
+// writers (body sender, reader loop acks) are interlocked by `mtx`.
+func (hc *tH2CConn) writeFrame(aType, aFlags byte, aStream uint32, aPayload []byte) error {
+	hc.mtx.Lock()
+	defer hc.mtx.Unlock()
+
+	if nil != hc.err {
+		return hc.err
+	}
+	head := []byte{
+		byte(len(aPayload) >> 16), byte(len(aPayload) >> 8), byte(len(aPayload)),
+		aType, aFlags,
+		byte(aStream >> 24), byte(aStream >> 16), byte(aStream >> 8), byte(aStream),
+	}
+	if _, err := hc.conn.Write(append(head, aPayload...)); nil != err {
+		hc.err = err
+		hc.cond.Broadcast()
+		return err
+	}
+
+	return nil
+} // writeFrame()
+
+// --------------------------------------------------------------------------
+// tH2CBody methods:
+
+// `Close()` releases the response body and tears down the
+// connection - there's no connection reuse to preserve.
+func (b *tH2CBody) Close() error {
+	_ = b.reader.Close()
+	b.hc.fail(io.ErrClosedPipe)
+
+	return nil
+} // Close()
+
+// `Read()` implements `io.Reader` for the piped response body.
+func (b *tH2CBody) Read(aBuf []byte) (int, error) {
+	return b.reader.Read(aBuf)
+} // Read()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `h2ReadFrame()` reads one complete frame from `aReader`.
+func h2ReadFrame(aReader io.Reader) (tH2Frame, error) {
+	var head [9]byte
+	if _, err := io.ReadFull(aReader, head[:]); nil != err {
+		return tH2Frame{}, err
+	}
+	length := (int(head[0]) << 16) | (int(head[1]) << 8) | int(head[2])
+	if (1 << 20) < length {
+		return tH2Frame{}, fmt.Errorf("h2c: oversized frame (%d bytes)", length)
+	}
+	frame := tH2Frame{
+		typ:    head[3],
+		flags:  head[4],
+		stream: binary.BigEndian.Uint32(head[5:9]) & 0x7fffffff,
+	}
+	if 0 < length {
+		frame.payload = make([]byte, length)
+		if _, err := io.ReadFull(aReader, frame.payload); nil != err {
+			return tH2Frame{}, err
+		}
+	}
+
+	return frame, nil
+} // h2ReadFrame()
+
+// `h2RequestBlock()` encodes `aRequest` as an HPACK header block
+// with the mandatory pseudo-header fields up front.
+func h2RequestBlock(aRequest *http.Request) []byte {
+	authority := aRequest.Host
+	if "" == authority {
+		authority = aRequest.URL.Host
+	}
+	block := hpackAppendField(nil, `:method`, aRequest.Method)
+	block = hpackAppendField(block, `:scheme`, `http`)
+	block = hpackAppendField(block, `:authority`, authority)
+	block = hpackAppendField(block, `:path`, aRequest.URL.RequestURI())
+	for name, values := range aRequest.Header {
+		lower := strings.ToLower(name)
+		switch lower { // connection-specific headers (RFC 9113 §8.2.2)
+		case `host`, `connection`, `proxy-connection`,
+			`keep-alive`, `transfer-encoding`, `upgrade`:
+			continue
+		}
+		for _, value := range values {
+			block = hpackAppendField(block, lower, value)
+		}
+	}
+	if (0 < aRequest.ContentLength) &&
+		("" == aRequest.Header.Get(`Content-Length`)) {
+		block = hpackAppendField(block, `content-length`,
+			strconv.FormatInt(aRequest.ContentLength, 10))
+	}
+
+	return block
+} // h2RequestBlock()
+
+// `h2Response()` builds the `http.Response` skeleton from the
+// decoded header fields; interim (1xx) responses yield `nil`.
+func h2Response(aFields []tHeaderField) *http.Response {
+	status, header := 0, http.Header{}
+	for _, field := range aFields {
+		if strings.HasPrefix(field.name, `:`) {
+			if `:status` == field.name {
+				status, _ = strconv.Atoi(field.value)
+			}
+			continue
+		}
+		header.Add(field.name, field.value)
+	}
+	if (100 <= status) && (200 > status) {
+		return nil
+	}
+
+	resp := &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         `HTTP/2.0`,
+		ProtoMajor:    2,
+		Header:        header,
+		ContentLength: -1,
+	}
+	if cl := header.Get(`Content-Length`); "" != cl {
+		if size, err := strconv.ParseInt(cl, 10, 64); nil == err {
+			resp.ContentLength = size
+		}
+	}
+
+	return resp
+} // h2Response()
+
+// `h2StripPadding()` removes padding (and, for HEADERS, the
+// optional priority fields) from a frame payload.
+func h2StripPadding(aFlags byte, aPayload []byte, aHeaders bool) []byte {
+	if (0 != (aFlags & h2FlagPadded)) && (0 < len(aPayload)) {
+		pad := int(aPayload[0])
+		aPayload = aPayload[1:]
+		if pad <= len(aPayload) {
+			aPayload = aPayload[:len(aPayload)-pad]
+		}
+	}
+	if aHeaders && (0 != (aFlags & h2FlagPriority)) && (5 <= len(aPayload)) {
+		aPayload = aPayload[5:]
+	}
+
+	return aPayload
+} // h2StripPadding()
+
+/* _EoF_ */
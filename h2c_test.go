@@ -0,0 +1,210 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHuffmanDecode(t *testing.T) {
+	// RFC 7541 Appendix C reference vectors:
+	for _, tc := range []struct {
+		encoded, want string
+	}{
+		{`f1e3c2e5f23a6ba0ab90f4ff`, `www.example.com`},
+		{`aec3771a4b`, `private`},
+		{`6402`, `302`},
+		{`a8eb10649cbf`, `no-cache`},
+	} {
+		raw, _ := hex.DecodeString(tc.encoded)
+		got, err := huffmanDecode(raw)
+		if nil != err {
+			t.Errorf(`huffmanDecode(%s) error = %v`, tc.encoded, err)
+		} else if tc.want != got {
+			t.Errorf(`huffmanDecode(%s) = %q, want %q`,
+				tc.encoded, got, tc.want)
+		}
+	}
+
+	// a single zero byte decodes '0' and leaves 000 - invalid
+	// padding, which must not be accepted silently:
+	if _, err := huffmanDecode([]byte{0}); nil == err {
+		t.Error(`huffmanDecode() accepted invalid padding`)
+	}
+} // TestHuffmanDecode()
+
+func TestHpackDecoder(t *testing.T) {
+	// the two successive request blocks of RFC 7541 Appendix C.4,
+	// exercising the static table, Huffman literals, and the
+	// dynamic table carried over between blocks:
+	var decoder tHpackDecoder
+	first, _ := hex.DecodeString(`828684418cf1e3c2e5f23a6ba0ab90f4ff`)
+	fields, err := decoder.decode(first)
+	if nil != err {
+		t.Fatalf(`decode() error = %v`, err)
+	}
+	want := []tHeaderField{
+		{`:method`, `GET`},
+		{`:scheme`, `http`},
+		{`:path`, `/`},
+		{`:authority`, `www.example.com`},
+	}
+	if len(want) != len(fields) {
+		t.Fatalf(`decode() = %d fields, want %d`, len(fields), len(want))
+	}
+	for idx, field := range want {
+		if field != fields[idx] {
+			t.Errorf(`decode() field %d = %v, want %v`, idx, fields[idx], field)
+		}
+	}
+
+	second, _ := hex.DecodeString(`828684be5886a8eb10649cbf`)
+	if fields, err = decoder.decode(second); nil != err {
+		t.Fatalf(`decode() error = %v`, err)
+	}
+	if (5 != len(fields)) ||
+		(tHeaderField{`:authority`, `www.example.com`} != fields[3]) ||
+		(tHeaderField{`cache-control`, `no-cache`} != fields[4]) {
+		t.Errorf(`decode() second block = %v`, fields)
+	}
+
+	// our own stateless encoding must decode losslessly:
+	block := hpackAppendField(nil, `x-request-id`, strings.Repeat(`abc`, 80))
+	if fields, err = decoder.decode(block); nil != err {
+		t.Fatalf(`decode() error = %v`, err)
+	}
+	if (1 != len(fields)) || (`x-request-id` != fields[0].name) ||
+		(240 != len(fields[0].value)) {
+		t.Errorf(`decode() round trip = %v`, fields)
+	}
+} // TestHpackDecoder()
+
+// `startH2CServer()` runs a minimal prior-knowledge h2c backend
+// answering every request with `h2c answer` plus any request body
+// it received, and a Huffman-coded `cache-control` header to
+// exercise the client's decoder.
+func startH2CServer(t *testing.T) net.Listener {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if nil != err {
+		t.Fatalf(`Listen() error = %v`, err)
+	}
+
+	writeFrame := func(aConn net.Conn, aType, aFlags byte, aStream uint32, aPayload []byte) {
+		head := []byte{
+			byte(len(aPayload) >> 16), byte(len(aPayload) >> 8), byte(len(aPayload)),
+			aType, aFlags,
+			byte(aStream >> 24), byte(aStream >> 16), byte(aStream >> 8), byte(aStream),
+		}
+		_, _ = aConn.Write(append(head, aPayload...))
+	}
+	serve := func(aConn net.Conn) {
+		defer func() { _ = aConn.Close() }()
+		preface := make([]byte, len(h2cPreface))
+		if _, err := io.ReadFull(aConn, preface); (nil != err) ||
+			(h2cPreface != string(preface)) {
+			return
+		}
+		writeFrame(aConn, h2FrameSettings, 0, 0, nil)
+
+		var (
+			body    []byte
+			gotAll  bool
+			decoder tHpackDecoder
+		)
+		reader := bufio.NewReader(aConn)
+		for !gotAll {
+			frame, err := h2ReadFrame(reader)
+			if nil != err {
+				return
+			}
+			switch frame.typ {
+			case h2FrameSettings:
+				if 0 == (frame.flags & h2FlagAck) {
+					writeFrame(aConn, h2FrameSettings, h2FlagAck, 0, nil)
+				}
+			case h2FrameHeaders:
+				if _, err = decoder.decode(
+					h2StripPadding(frame.flags, frame.payload, true)); nil != err {
+					return
+				}
+				gotAll = 0 != (frame.flags & h2FlagEndStream)
+			case h2FrameData:
+				body = append(body, frame.payload...)
+				gotAll = 0 != (frame.flags & h2FlagEndStream)
+			}
+		}
+
+		// :status 200 indexed, then a Huffman-coded header value:
+		block := []byte{0x88, 0}
+		block = hpackAppendString(block, `cache-control`)
+		raw, _ := hex.DecodeString(`aec3771a4b`) // "private"
+		block = append(block, 0x80|byte(len(raw)))
+		block = append(block, raw...)
+		writeFrame(aConn, h2FrameHeaders, h2FlagEndHeaders, 1, block)
+		writeFrame(aConn, h2FrameData, h2FlagEndStream, 1,
+			append([]byte(`h2c answer`), body...))
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if nil != err {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	return listener
+} // startH2CServer()
+
+func TestH2CRoundTrip(t *testing.T) {
+	listener := startH2CServer(t)
+	defer func() { _ = listener.Close() }()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: `http://` + listener.Addr().String(),
+		HTTP2:  true,
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, `http://example.org/page`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusOK != rec.Code {
+		t.Fatalf(`GET status = %d, want 200`, rec.Code)
+	}
+	if `h2c answer` != rec.Body.String() {
+		t.Errorf(`GET body = %q`, rec.Body.String())
+	}
+	if `private` != rec.Header().Get(`Cache-Control`) {
+		t.Errorf(`Cache-Control = %q, want "private"`,
+			rec.Header().Get(`Cache-Control`))
+	}
+
+	// a request body travels as DATA frames under flow control:
+	req = httptest.NewRequest(http.MethodPost, `http://example.org/post`,
+		bytes.NewReader([]byte(` plus payload`)))
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if `h2c answer plus payload` != rec.Body.String() {
+		t.Errorf(`POST body = %q`, rec.Body.String())
+	}
+} // TestH2CRoundTrip()
+
+/* _EoF_ */
@@ -0,0 +1,143 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// `gHopHeaders` are hop-by-hop headers that must never travel beyond
+// a single connection; `Connection`, `Upgrade`, and `TE` are left to
+// the standard library which handles protocol upgrades itself.
+var gHopHeaders = []string{
+	`Keep-Alive`,
+	`Proxy-Authenticate`,
+	`Proxy-Authorization`,
+	`Proxy-Connection`,
+}
+
+// `gForwardHeaders` are client-supplied forwarding headers that are
+// trivially spoofable and hence only accepted from trusted proxies.
+var gForwardHeaders = []string{
+	`Forwarded`,
+	`True-Client-IP`,
+	`X-Client-IP`,
+	`X-Forwarded-For`,
+	`X-Forwarded-Host`,
+	`X-Forwarded-Port`,
+	`X-Forwarded-Proto`,
+	`X-Real-IP`,
+}
+
+// --------------------------------------------------------------------------
+// TProxyHandler methods:
+
+// `sanitizeHeaders()` removes hop-by-hop headers from `aRequest` and
+// - unless the client is a trusted proxy - all spoofable forwarding
+// headers, then re-adds the values the backends may rely on.
+//
+// The `X-Forwarded-For` chain itself is appended to by the standard
+// library's reverse proxy.
+func (ph *TProxyHandler) sanitizeHeaders(aRequest *http.Request) {
+	for _, name := range gHopHeaders {
+		aRequest.Header.Del(name)
+	}
+	// tokens listed in `Connection` are hop-by-hop as well, apart
+	// from the upgrade mechanism handled by `httputil.ReverseProxy`:
+	for _, field := range aRequest.Header.Values(`Connection`) {
+		for _, token := range strings.Split(field, `,`) {
+			token = strings.TrimSpace(token)
+			switch strings.ToLower(token) {
+			case ``, `close`, `keep-alive`, `upgrade`, `te`:
+				// handled by `net/http` resp. kept for upgrades
+
+			default:
+				aRequest.Header.Del(token)
+			}
+		}
+	}
+
+	if !ph.trustedClient(aRequest.RemoteAddr) {
+		for _, name := range gForwardHeaders {
+			aRequest.Header.Del(name)
+		}
+	}
+
+	// (re-)add the values this proxy vouches for:
+	if "" == aRequest.Header.Get(`X-Real-IP`) {
+		if ip, _, err := net.SplitHostPort(aRequest.RemoteAddr); nil == err {
+			aRequest.Header.Set(`X-Real-IP`, ip)
+		}
+	}
+	if "" == aRequest.Header.Get(`X-Forwarded-Proto`) {
+		proto := `http`
+		if nil != aRequest.TLS {
+			proto = `https`
+		}
+		aRequest.Header.Set(`X-Forwarded-Proto`, proto)
+	}
+	if "" == aRequest.Header.Get(`X-Forwarded-Host`) {
+		aRequest.Header.Set(`X-Forwarded-Host`, aRequest.Host)
+	}
+} // sanitizeHeaders()
+
+// `trustedClient()` reports whether `aRemoteAddr` belongs to one of
+// the configured trusted proxy networks.
+func (ph *TProxyHandler) trustedClient(aRemoteAddr string) bool {
+	ph.mtx.RLock()
+	trusted := ph.trusted
+	ph.mtx.RUnlock()
+	if 0 == len(trusted) {
+		return false
+	}
+	host, _, err := net.SplitHostPort(aRemoteAddr)
+	if nil != err {
+		host = aRemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if nil == ip {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+} // trustedClient()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `parseTrustedNets()` turns the configured CIDR (or plain IP) list
+// into parsed networks, silently skipping malformed entries.
+func parseTrustedNets(aCIDRs []string) []*net.IPNet {
+	var result []*net.IPNet
+	for _, cidr := range aCIDRs {
+		if !strings.Contains(cidr, `/`) {
+			if nil != net.ParseIP(cidr) {
+				if strings.Contains(cidr, `:`) {
+					cidr += `/128`
+				} else {
+					cidr += `/32`
+				}
+			}
+		}
+		if _, network, err := net.ParseCIDR(cidr); nil == err {
+			result = append(result, network)
+		}
+	}
+
+	return result
+} // parseTrustedNets()
+
+/* _EoF_ */
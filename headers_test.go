@@ -0,0 +1,139 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedNets(t *testing.T) {
+	nets := parseTrustedNets([]string{
+		`10.0.0.0/8`,     // CIDR
+		`192.0.2.7`,      // plain IPv4 becomes a /32
+		`2001:db8::1`,    // plain IPv6 becomes a /128
+		`not-an-address`, // silently skipped
+		`10.0.0.0/33`,    // silently skipped
+		`256.1.2.3`,      // silently skipped
+	})
+	if 3 != len(nets) {
+		t.Fatalf(`parseTrustedNets() = %d networks, want 3`, len(nets))
+	}
+	if `10.0.0.0/8` != nets[0].String() {
+		t.Errorf(`parseTrustedNets() first = %q`, nets[0].String())
+	}
+	if `192.0.2.7/32` != nets[1].String() {
+		t.Errorf(`parseTrustedNets() second = %q`, nets[1].String())
+	}
+	if `2001:db8::1/128` != nets[2].String() {
+		t.Errorf(`parseTrustedNets() third = %q`, nets[2].String())
+	}
+} // TestParseTrustedNets()
+
+func TestTrustedClient(t *testing.T) {
+	ph, _ := NewProxyHandler(WithConfig(&TConfig{
+		TrustedProxies: []string{`10.0.0.0/8`, `2001:db8::1`},
+	}))
+
+	for _, tc := range []struct {
+		remoteAddr string
+		trusted    bool
+	}{
+		{`10.1.2.3:4711`, true},
+		{`[2001:db8::1]:4711`, true},
+		{`203.0.113.7:4711`, false},
+		{`[2001:db8::2]:4711`, false},
+		{`10.1.2.3`, true}, // bare IP without port
+		{`garbage`, false},
+	} {
+		if tc.trusted != ph.trustedClient(tc.remoteAddr) {
+			t.Errorf(`trustedClient(%q) = %v, want %v`,
+				tc.remoteAddr, !tc.trusted, tc.trusted)
+		}
+	}
+
+	// without any configured networks nobody is trusted:
+	ph, _ = NewProxyHandler()
+	if ph.trustedClient(`10.1.2.3:4711`) {
+		t.Error(`trustedClient() trusted a peer without configuration`)
+	}
+} // TestTrustedClient()
+
+func TestSanitizeHeaders(t *testing.T) {
+	ph, _ := NewProxyHandler(WithConfig(&TConfig{
+		TrustedProxies: []string{`10.0.0.0/8`},
+	}))
+	request := func(aRemoteAddr string, aHeader map[string]string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		req.RemoteAddr = aRemoteAddr
+		for name, value := range aHeader {
+			req.Header.Set(name, value)
+		}
+		ph.sanitizeHeaders(req)
+		return req
+	}
+
+	// an untrusted client's forwarding headers are spoofed by
+	// definition and must be replaced with what this proxy saw:
+	req := request(`203.0.113.7:4711`, map[string]string{
+		`X-Forwarded-For`:   `1.2.3.4`,
+		`X-Real-IP`:         `1.2.3.4`,
+		`X-Forwarded-Proto`: `https`,
+		`True-Client-IP`:    `1.2.3.4`,
+	})
+	if xff := req.Header.Get(`X-Forwarded-For`); "" != xff {
+		t.Errorf(`untrusted X-Forwarded-For kept: %q`, xff)
+	}
+	if "" != req.Header.Get(`True-Client-IP`) {
+		t.Error(`untrusted True-Client-IP kept`)
+	}
+	if `203.0.113.7` != req.Header.Get(`X-Real-IP`) {
+		t.Errorf(`X-Real-IP = %q, want the peer address`,
+			req.Header.Get(`X-Real-IP`))
+	}
+	if `http` != req.Header.Get(`X-Forwarded-Proto`) {
+		t.Errorf(`X-Forwarded-Proto = %q, want "http"`,
+			req.Header.Get(`X-Forwarded-Proto`))
+	}
+	if `example.org` != req.Header.Get(`X-Forwarded-Host`) {
+		t.Errorf(`X-Forwarded-Host = %q`, req.Header.Get(`X-Forwarded-Host`))
+	}
+
+	// a trusted proxy's chain survives untouched:
+	req = request(`10.1.2.3:4711`, map[string]string{
+		`X-Forwarded-For`: `1.2.3.4, 5.6.7.8`,
+		`X-Real-IP`:       `1.2.3.4`,
+	})
+	if `1.2.3.4, 5.6.7.8` != req.Header.Get(`X-Forwarded-For`) {
+		t.Errorf(`trusted X-Forwarded-For = %q`,
+			req.Header.Get(`X-Forwarded-For`))
+	}
+	if `1.2.3.4` != req.Header.Get(`X-Real-IP`) {
+		t.Errorf(`trusted X-Real-IP = %q`, req.Header.Get(`X-Real-IP`))
+	}
+
+	// hop-by-hop headers never travel on, including those named in
+	// the `Connection` header:
+	req = request(`203.0.113.7:4711`, map[string]string{
+		`Proxy-Authorization`: `Basic whatever`,
+		`Keep-Alive`:          `timeout=5`,
+		`Connection`:          `X-Custom-Hop`,
+		`X-Custom-Hop`:        `secret`,
+	})
+	for _, name := range []string{
+		`Proxy-Authorization`, `Keep-Alive`, `X-Custom-Hop`,
+	} {
+		if "" != req.Header.Get(name) {
+			t.Errorf(`hop-by-hop header %q survived`, name)
+		}
+	}
+} // TestSanitizeHeaders()
+
+/* _EoF_ */
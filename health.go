@@ -0,0 +1,247 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mwat56/reprox/events"
+)
+
+type (
+	// `THealthConfig` holds the backend health checking settings.
+	THealthConfig struct {
+		// IntervalSec is the pause between check rounds (default 30).
+		IntervalSec int `json:"intervalSec"`
+
+		// TimeoutSec is the per-check timeout (default 5).
+		TimeoutSec int `json:"timeoutSec"`
+
+		// Path is the URL path probed on each backend (default `/`).
+		Path string `json:"path,omitempty"`
+
+		// ReadyFraction is the fraction of healthy backends required
+		// for the proxy to report readiness (default 0.5).
+		ReadyFraction float64 `json:"readyFraction"`
+	}
+
+	// `THealthStatus` is the check state of a single backend.
+	THealthStatus struct {
+		Target    string        `json:"target"`
+		Healthy   bool          `json:"healthy"`
+		LastCheck time.Time     `json:"lastCheck"`
+		Latency   time.Duration `json:"latency"`
+		Failures  int           `json:"consecutiveFailures"`
+	}
+
+	// `THealthChecker` periodically probes all configured backends
+	// and keeps their last known health state.
+	THealthChecker struct {
+		mtx      sync.RWMutex
+		audit    *TAuditLog
+		stream   *TEventStream
+		client   *http.Client
+		done     chan struct{}
+		handler  *TProxyHandler
+		interval time.Duration
+		path     string
+		ready    float64
+		state    map[string]*THealthStatus // hostname -> state
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewHealthChecker()` creates a health checker probing the backends
+// of `aHandler` according to `aConfig` (which may be nil to use the
+// defaults).
+//
+// Parameters:
+//   - `aConfig`: The health checking settings (or nil).
+//   - `aHandler`: The proxy handler whose backends are probed.
+//
+// Returns:
+//   - `*THealthChecker`: The new (not yet started) checker.
+func NewHealthChecker(aConfig *THealthConfig, aHandler *TProxyHandler) *THealthChecker {
+	if nil == aConfig {
+		aConfig = &THealthConfig{}
+	}
+	interval := time.Duration(aConfig.IntervalSec) * time.Second
+	if 0 >= interval {
+		interval = 30 * time.Second
+	}
+	timeout := time.Duration(aConfig.TimeoutSec) * time.Second
+	if 0 >= timeout {
+		timeout = 5 * time.Second
+	}
+	path := aConfig.Path
+	if "" == path {
+		path = `/`
+	}
+	ready := aConfig.ReadyFraction
+	if (0 >= ready) || (1 < ready) {
+		ready = 0.5
+	}
+
+	return &THealthChecker{
+		client:   &http.Client{Timeout: timeout},
+		done:     make(chan struct{}),
+		handler:  aHandler,
+		interval: interval,
+		path:     path,
+		ready:    ready,
+		state:    make(map[string]*THealthStatus),
+	}
+} // NewHealthChecker()
+
+// --------------------------------------------------------------------------
+// THealthChecker methods:
+
+// `SetAudit()` attaches the audit log recording backend ejections
+// and readmissions.
+//
+// Parameters:
+//   - `aAudit`: The audit log to write to.
+func (hc *THealthChecker) SetAudit(aAudit *TAuditLog) {
+	hc.audit = aAudit
+} // SetAudit()
+
+// `SetEvents()` attaches the live event stream notified on backend
+// state transitions.
+//
+// Parameters:
+//   - `aStream`: The event broadcaster to publish to (may be nil).
+func (hc *THealthChecker) SetEvents(aStream *TEventStream) {
+	hc.stream = aStream
+} // SetEvents()
+
+// `Ready()` reports whether at least the configured fraction of the
+// backends is currently healthy.
+//
+// Before the first check round completed all backends count as
+// healthy so a freshly started proxy isn't reported dead.
+func (hc *THealthChecker) Ready() bool {
+	hc.mtx.RLock()
+	defer hc.mtx.RUnlock()
+
+	if 0 == len(hc.state) {
+		return true
+	}
+	healthy := 0
+	for _, status := range hc.state {
+		if status.Healthy {
+			healthy++
+		}
+	}
+
+	return float64(healthy) >= (hc.ready * float64(len(hc.state)))
+} // Ready()
+
+// `Snapshot()` returns a copy of the current per-host health states.
+func (hc *THealthChecker) Snapshot() map[string]THealthStatus {
+	hc.mtx.RLock()
+	defer hc.mtx.RUnlock()
+
+	snapshot := make(map[string]THealthStatus, len(hc.state))
+	for hostname, status := range hc.state {
+		snapshot[hostname] = *status
+	}
+
+	return snapshot
+} // Snapshot()
+
+// `Start()` runs the periodic check rounds until `Stop()` is called.
+func (hc *THealthChecker) Start() {
+	go func() {
+		hc.checkAll()
+		ticker := time.NewTicker(hc.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				hc.checkAll()
+			case <-hc.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the periodic check rounds.
+func (hc *THealthChecker) Stop() {
+	close(hc.done)
+} // Stop()
+
+// `checkAll()` probes every configured backend once.
+func (hc *THealthChecker) checkAll() {
+	routes := hc.handler.Routes()
+	for hostname, target := range routes {
+		hc.checkOne(hostname, target)
+	}
+
+	// forget hosts whose routes were removed:
+	hc.mtx.Lock()
+	for hostname := range hc.state {
+		if _, ok := routes[hostname]; !ok {
+			delete(hc.state, hostname)
+		}
+	}
+	hc.mtx.Unlock()
+} // checkAll()
+
+// `checkOne()` probes the backend `aTarget` of `aHostname`.
+func (hc *THealthChecker) checkOne(aHostname, aTarget string) {
+	started := time.Now()
+	resp, err := hc.client.Head(aTarget + hc.path)
+	latency := time.Since(started)
+	healthy := (nil == err) && (500 > resp.StatusCode)
+	if nil == err {
+		_ = resp.Body.Close()
+	}
+
+	hc.mtx.Lock()
+	status, ok := hc.state[aHostname]
+	if !ok {
+		status = &THealthStatus{}
+		hc.state[aHostname] = status
+	}
+	changed := ok && (status.Healthy != healthy)
+	if changed {
+		if healthy {
+			hc.audit.Log(`healthcheck`, `backend-readmit`,
+				aHostname+` (`+aTarget+`)`)
+			hc.stream.Publish(`backend-readmit`, aHostname+` (`+aTarget+`)`)
+		} else {
+			hc.audit.Log(`healthcheck`, `backend-eject`,
+				aHostname+` (`+aTarget+`)`)
+			hc.stream.Publish(`backend-eject`, aHostname+` (`+aTarget+`)`)
+		}
+	}
+	status.Target = aTarget
+	status.Healthy = healthy
+	status.LastCheck = started
+	status.Latency = latency
+	if healthy {
+		status.Failures = 0
+	} else {
+		status.Failures++
+	}
+	snapshot := *status
+	hc.mtx.Unlock()
+
+	if changed {
+		// bus delivery may block, so it happens outside the lock:
+		hc.handler.Bus().Publish(events.TopicHealth, snapshot)
+	}
+} // checkOne()
+
+/* _EoF_ */
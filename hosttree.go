@@ -0,0 +1,122 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strings"
+)
+
+// `THostTree` resolves hostnames against a mix of exact names and
+// wildcard patterns (`*.mwat.de`, `*.sub.mwat.de`), returning the
+// most specific match.
+//
+// Internally the names are stored as a tree of reversed DNS labels
+// (`de` -> `mwat` -> `www`), so a lookup costs O(label count)
+// regardless of how many names are registered.  An exact name
+// always beats a wildcard, and a deeper wildcard beats a shallower
+// one (`*.sub.mwat.de` wins over `*.mwat.de`).  A wildcard matches
+// one or more leading labels but never the bare parent domain.
+//
+// The tree is built once and read-only afterwards, so lookups need
+// no locking; both the HTTP router and the SNI certificate
+// selection share this type.
+type THostTree[V any] struct {
+	value    V
+	hasValue bool
+	children map[string]*THostTree[V] // DNS label (or `*`) -> subtree
+}
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewHostTree()` creates an empty hostname matcher.
+//
+// Returns:
+//   - `*THostTree[V]`: The new empty matcher.
+func NewHostTree[V any]() *THostTree[V] {
+	return &THostTree[V]{}
+} // NewHostTree()
+
+// --------------------------------------------------------------------------
+// THostTree methods:
+
+// `Match()` returns the value registered under the most specific
+// pattern matching `aHostname`.
+//
+// Parameters:
+//   - `aHostname`: The hostname to resolve (without port).
+//
+// Returns:
+//   - `V`: The best matching value (zero value if none).
+//   - `bool`: Whether any registered pattern matched.
+func (ht *THostTree[V]) Match(aHostname string) (V, bool) {
+	var value V
+	found := false
+	if nil == ht {
+		return value, found
+	}
+
+	node := ht
+	for rest := strings.ToLower(aHostname); "" != rest; {
+		label := rest
+		if idx := strings.LastIndexByte(rest, '.'); 0 <= idx {
+			label, rest = rest[idx+1:], rest[:idx]
+		} else {
+			rest = ``
+		}
+		// a wildcard here covers the label(s) still unconsumed;
+		// deeper (more specific) wildcards overwrite shallower ones:
+		if wild, ok := node.children[`*`]; ok && wild.hasValue {
+			value, found = wild.value, true
+		}
+		child, ok := node.children[label]
+		if !ok {
+			return value, found
+		}
+		node = child
+
+		if ("" == rest) && node.hasValue {
+			// all labels consumed: the exact name wins
+			return node.value, true
+		}
+	}
+
+	return value, found
+} // Match()
+
+// `Set()` registers `aValue` under the hostname or wildcard pattern
+// `aPattern`; a later `Set()` of the same pattern replaces the
+// value.
+//
+// Parameters:
+//   - `aPattern`: An exact hostname or a `*.`-prefixed pattern.
+//   - `aValue`: The value resolved for matching hostnames.
+func (ht *THostTree[V]) Set(aPattern string, aValue V) {
+	node := ht
+	for rest := strings.ToLower(aPattern); "" != rest; {
+		label := rest
+		if idx := strings.LastIndexByte(rest, '.'); 0 <= idx {
+			label, rest = rest[idx+1:], rest[:idx]
+		} else {
+			rest = ``
+		}
+		child, ok := node.children[label]
+		if !ok {
+			if nil == node.children {
+				node.children = make(map[string]*THostTree[V])
+			}
+			child = &THostTree[V]{}
+			node.children[label] = child
+		}
+		node = child
+	}
+	node.value, node.hasValue = aValue, true
+} // Set()
+
+/* _EoF_ */
@@ -0,0 +1,77 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"testing"
+)
+
+func TestHostTree(t *testing.T) {
+	tree := NewHostTree[string]()
+	tree.Set(`mwat.de`, `exact`)
+	tree.Set(`*.mwat.de`, `wild`)
+	tree.Set(`*.sub.mwat.de`, `subwild`)
+	tree.Set(`www.sub.mwat.de`, `www`)
+
+	cases := []struct {
+		hostname, want string
+		found          bool
+	}{
+		{`mwat.de`, `exact`, true},           // exact beats the wildcards
+		{`blog.mwat.de`, `wild`, true},       // one label under `*.mwat.de`
+		{`a.b.mwat.de`, `wild`, true},        // several labels, same pattern
+		{`sub.mwat.de`, `wild`, true},        // `*.sub.…` needs a label below `sub`
+		{`api.sub.mwat.de`, `subwild`, true}, // the deeper wildcard wins
+		{`www.sub.mwat.de`, `www`, true},     // ... unless an exact name exists
+		{`MWAT.DE`, `exact`, true},           // case-insensitive
+		{`mwat.com`, ``, false},
+		{`de`, ``, false},
+	}
+	for _, tc := range cases {
+		value, found := tree.Match(tc.hostname)
+		if (found != tc.found) || (value != tc.want) {
+			t.Errorf(`Match(%q) = (%q, %v), want (%q, %v)`,
+				tc.hostname, value, found, tc.want, tc.found)
+		}
+	}
+
+	// a nil tree matches nothing:
+	var nilTree *THostTree[string]
+	if _, found := nilTree.Match(`mwat.de`); found {
+		t.Error(`nil tree reported a match`)
+	}
+} // TestHostTree()
+
+func TestWildcardRouting(t *testing.T) {
+	rt := newRouteTable()
+	wild := &tRoute{}
+	exact := &tRoute{}
+	rt.replaceAll(map[string]*tRoute{
+		`*.mwat.de`:   wild,
+		`www.mwat.de`: exact,
+	}, 0)
+
+	if route := rt.lookup(`www.mwat.de`); exact != route {
+		t.Error(`lookup(www.mwat.de) didn't return the exact route`)
+	}
+	if route := rt.lookup(`anything.mwat.de`); wild != route {
+		t.Error(`lookup(anything.mwat.de) didn't match the wildcard`)
+	}
+	if route := rt.lookup(`other.example`); nil != route {
+		t.Error(`lookup(other.example) matched unexpectedly`)
+	}
+
+	// dropping the wildcard host must drop the fallback, too:
+	rt.remove(`*.mwat.de`, 0)
+	if route := rt.lookup(`anything.mwat.de`); nil != route {
+		t.Error(`removed wildcard still matched`)
+	}
+} // TestWildcardRouting()
+
+/* _EoF_ */
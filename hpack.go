@@ -0,0 +1,316 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+)
+
+type (
+	// `tHeaderField` is a single decoded HPACK name/value pair.
+	tHeaderField struct {
+		name, value string
+	}
+
+	// `tHpackDecoder` decompresses HPACK header blocks (RFC 7541)
+	// received on one HTTP/2 connection; the dynamic table is
+	// connection state, so every connection needs its own decoder.
+	tHpackDecoder struct {
+		dynamic []tHeaderField // newest entry first
+		size    int            // current dynamic table size
+		limit   int            // peer-announced table limit
+	}
+)
+
+// `hpackDefaultTableSize` is the dynamic table limit both sides
+// assume until changed via SETTINGS (RFC 7541 §4.2).
+const hpackDefaultTableSize = 4096
+
+// `hpackStaticTable` is the fixed header table of RFC 7541
+// Appendix A; indices are 1-based on the wire.
+var hpackStaticTable = [...]tHeaderField{
+	{`:authority`, ``},
+	{`:method`, `GET`},
+	{`:method`, `POST`},
+	{`:path`, `/`},
+	{`:path`, `/index.html`},
+	{`:scheme`, `http`},
+	{`:scheme`, `https`},
+	{`:status`, `200`},
+	{`:status`, `204`},
+	{`:status`, `206`},
+	{`:status`, `304`},
+	{`:status`, `400`},
+	{`:status`, `404`},
+	{`:status`, `500`},
+	{`accept-charset`, ``},
+	{`accept-encoding`, `gzip, deflate`},
+	{`accept-language`, ``},
+	{`accept-ranges`, ``},
+	{`accept`, ``},
+	{`access-control-allow-origin`, ``},
+	{`age`, ``},
+	{`allow`, ``},
+	{`authorization`, ``},
+	{`cache-control`, ``},
+	{`content-disposition`, ``},
+	{`content-encoding`, ``},
+	{`content-language`, ``},
+	{`content-length`, ``},
+	{`content-location`, ``},
+	{`content-range`, ``},
+	{`content-type`, ``},
+	{`cookie`, ``},
+	{`date`, ``},
+	{`etag`, ``},
+	{`expect`, ``},
+	{`expires`, ``},
+	{`from`, ``},
+	{`host`, ``},
+	{`if-match`, ``},
+	{`if-modified-since`, ``},
+	{`if-none-match`, ``},
+	{`if-range`, ``},
+	{`if-unmodified-since`, ``},
+	{`last-modified`, ``},
+	{`link`, ``},
+	{`location`, ``},
+	{`max-forwards`, ``},
+	{`proxy-authenticate`, ``},
+	{`proxy-authorization`, ``},
+	{`range`, ``},
+	{`referer`, ``},
+	{`refresh`, ``},
+	{`retry-after`, ``},
+	{`server`, ``},
+	{`set-cookie`, ``},
+	{`strict-transport-security`, ``},
+	{`transfer-encoding`, ``},
+	{`user-agent`, ``},
+	{`vary`, ``},
+	{`via`, ``},
+	{`www-authenticate`, ``},
+}
+
+// --------------------------------------------------------------------------
+// tHpackDecoder methods:
+
+// `add()` inserts `aField` into the dynamic table, evicting the
+// oldest entries once the table limit is exceeded (RFC 7541 §4.4).
+func (hd *tHpackDecoder) add(aField tHeaderField) {
+	hd.dynamic = append([]tHeaderField{aField}, hd.dynamic...)
+	hd.size += len(aField.name) + len(aField.value) + 32
+	limit := hd.limit
+	if 0 == limit {
+		limit = hpackDefaultTableSize
+	}
+	for (hd.size > limit) && (0 < len(hd.dynamic)) {
+		last := hd.dynamic[len(hd.dynamic)-1]
+		hd.size -= len(last.name) + len(last.value) + 32
+		hd.dynamic = hd.dynamic[:len(hd.dynamic)-1]
+	}
+} // add()
+
+// `decode()` decompresses the complete header block `aBlock` into
+// the list of fields it carries, updating the dynamic table.
+func (hd *tHpackDecoder) decode(aBlock []byte) ([]tHeaderField, error) {
+	var ( // ensure consistent types in the loop below
+		err    error
+		field  tHeaderField
+		fields []tHeaderField
+		idx    int
+	)
+	for 0 < len(aBlock) {
+		switch first := aBlock[0]; {
+		case 0 != (first & 0x80): // indexed field (§6.1)
+			if idx, aBlock, err = hpackReadInt(aBlock, 7); nil != err {
+				return nil, err
+			}
+			if field, err = hd.field(idx); nil != err {
+				return nil, err
+			}
+			fields = append(fields, field)
+
+		case 0 != (first & 0x40): // literal, incremental indexing (§6.2.1)
+			if field, aBlock, err = hd.readLiteral(aBlock, 6); nil != err {
+				return nil, err
+			}
+			hd.add(field)
+			fields = append(fields, field)
+
+		case 0x20 == (first & 0xe0): // dynamic table size update (§6.3)
+			if idx, aBlock, err = hpackReadInt(aBlock, 5); nil != err {
+				return nil, err
+			}
+			if hpackDefaultTableSize < idx {
+				return nil, fmt.Errorf(
+					"hpack: table size %d exceeds the announced limit", idx)
+			}
+			hd.setLimit(idx)
+
+		default: // literal without/never indexing (§6.2.2, §6.2.3)
+			if field, aBlock, err = hd.readLiteral(aBlock, 4); nil != err {
+				return nil, err
+			}
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+} // decode()
+
+// `field()` resolves the 1-based wire index `aIdx` against the
+// static and dynamic tables.
+func (hd *tHpackDecoder) field(aIdx int) (tHeaderField, error) {
+	if (1 <= aIdx) && (len(hpackStaticTable) >= aIdx) {
+		return hpackStaticTable[aIdx-1], nil
+	}
+	if dynIdx := aIdx - len(hpackStaticTable) - 1; (0 <= dynIdx) &&
+		(len(hd.dynamic) > dynIdx) {
+		return hd.dynamic[dynIdx], nil
+	}
+
+	return tHeaderField{}, fmt.Errorf("hpack: index %d out of range", aIdx)
+} // field()
+
+// `readLiteral()` reads one literal field representation whose
+// leading integer uses an `aPrefix` bit prefix.
+func (hd *tHpackDecoder) readLiteral(aBlock []byte, aPrefix uint8) (
+	tHeaderField, []byte, error) {
+	idx, rest, err := hpackReadInt(aBlock, aPrefix)
+	if nil != err {
+		return tHeaderField{}, nil, err
+	}
+	var field tHeaderField
+	if 0 == idx {
+		if field.name, rest, err = hpackReadString(rest); nil != err {
+			return tHeaderField{}, nil, err
+		}
+	} else {
+		named, nErr := hd.field(idx)
+		if nil != nErr {
+			return tHeaderField{}, nil, nErr
+		}
+		field.name = named.name
+	}
+	if field.value, rest, err = hpackReadString(rest); nil != err {
+		return tHeaderField{}, nil, err
+	}
+
+	return field, rest, nil
+} // readLiteral()
+
+// `setLimit()` applies a peer-announced dynamic table limit,
+// evicting entries that no longer fit.
+func (hd *tHpackDecoder) setLimit(aLimit int) {
+	hd.limit = aLimit
+	for (hd.size > aLimit) && (0 < len(hd.dynamic)) {
+		last := hd.dynamic[len(hd.dynamic)-1]
+		hd.size -= len(last.name) + len(last.value) + 32
+		hd.dynamic = hd.dynamic[:len(hd.dynamic)-1]
+	}
+} // setLimit()
+
+// --------------------------------------------------------------------------
+// encoding helper functions:
+
+// `hpackAppendField()` appends one header as a "literal without
+// indexing, new name" representation - stateless, so the peer needs
+// no synchronized dynamic table for what we send.
+func hpackAppendField(aBuf []byte, aName, aValue string) []byte {
+	aBuf = append(aBuf, 0)
+	aBuf = hpackAppendString(aBuf, aName)
+
+	return hpackAppendString(aBuf, aValue)
+} // hpackAppendField()
+
+// `hpackAppendInt()` appends `aValue` in the variable length
+// integer encoding of RFC 7541 §5.1, merging the first byte with
+// `aPattern` above the `aPrefix` bit prefix.
+func hpackAppendInt(aBuf []byte, aPrefix uint8, aPattern byte, aValue int) []byte {
+	limit := (1 << aPrefix) - 1
+	if limit > aValue {
+		return append(aBuf, aPattern|byte(aValue))
+	}
+	aBuf = append(aBuf, aPattern|byte(limit))
+	aValue -= limit
+	for 128 <= aValue {
+		aBuf = append(aBuf, byte(aValue&0x7f)|0x80)
+		aValue >>= 7
+	}
+
+	return append(aBuf, byte(aValue))
+} // hpackAppendInt()
+
+// `hpackAppendString()` appends `aStr` as a plain (non-Huffman)
+// string literal.
+func hpackAppendString(aBuf []byte, aStr string) []byte {
+	aBuf = hpackAppendInt(aBuf, 7, 0, len(aStr))
+
+	return append(aBuf, aStr...)
+} // hpackAppendString()
+
+// --------------------------------------------------------------------------
+// decoding helper functions:
+
+// `hpackReadInt()` decodes a variable length integer with an
+// `aPrefix` bit prefix, returning the value and the remaining block.
+func hpackReadInt(aBlock []byte, aPrefix uint8) (int, []byte, error) {
+	if 0 == len(aBlock) {
+		return 0, nil, fmt.Errorf("hpack: truncated integer")
+	}
+	limit := (1 << aPrefix) - 1
+	value := int(aBlock[0]) & limit
+	aBlock = aBlock[1:]
+	if limit > value {
+		return value, aBlock, nil
+	}
+	for shift := uint(0); ; shift += 7 {
+		if 0 == len(aBlock) {
+			return 0, nil, fmt.Errorf("hpack: truncated integer")
+		}
+		if 28 < shift { // cap far below any sane header size
+			return 0, nil, fmt.Errorf("hpack: integer overflow")
+		}
+		next := aBlock[0]
+		aBlock = aBlock[1:]
+		value += int(next&0x7f) << shift
+		if 0 == (next & 0x80) {
+			return value, aBlock, nil
+		}
+	}
+} // hpackReadInt()
+
+// `hpackReadString()` decodes a string literal, transparently
+// expanding Huffman-coded data.
+func hpackReadString(aBlock []byte) (string, []byte, error) {
+	if 0 == len(aBlock) {
+		return "", nil, fmt.Errorf("hpack: truncated string")
+	}
+	huffman := 0 != (aBlock[0] & 0x80)
+	strLen, rest, err := hpackReadInt(aBlock, 7)
+	if nil != err {
+		return "", nil, err
+	}
+	if len(rest) < strLen {
+		return "", nil, fmt.Errorf("hpack: truncated string")
+	}
+	raw, rest := rest[:strLen], rest[strLen:]
+	if !huffman {
+		return string(raw), rest, nil
+	}
+	str, err := huffmanDecode(raw)
+	if nil != err {
+		return "", nil, err
+	}
+
+	return str, rest, nil
+} // hpackReadString()
+
+/* _EoF_ */
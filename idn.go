@@ -0,0 +1,150 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strings"
+)
+
+// This file normalizes internationalized domain names (IDN) so a
+// configured `bücher.example` and the `xn--bcher-kva.example` form a
+// browser actually sends match the same route.  Both the config
+// loader and the request-time lookup funnel hostnames through
+// `normalizeHostname()`, which lowercases them and converts any
+// non-ASCII label to its punycode (RFC 3492) `xn--` form.
+
+// punycode parameters of RFC 3492, section 5:
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `normalizeHostname()` returns the canonical form of `aHostname`:
+// lowercased, with every non-ASCII label punycode-encoded.
+func normalizeHostname(aHostname string) string {
+	ascii := true
+	for idx := 0; idx < len(aHostname); idx++ {
+		if 0x80 <= aHostname[idx] {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return strings.ToLower(aHostname)
+	}
+
+	labels := strings.Split(strings.ToLower(aHostname), `.`)
+	for idx, label := range labels {
+		for _, ch := range label {
+			if 0x80 <= ch {
+				labels[idx] = `xn--` + punyEncode(label)
+				break
+			}
+		}
+	}
+
+	return strings.Join(labels, `.`)
+} // normalizeHostname()
+
+// `punyAdapt()` is the bias adaptation function of RFC 3492,
+// section 6.1.
+func punyAdapt(aDelta, aNumPoints int, aFirst bool) int {
+	if aFirst {
+		aDelta /= punyDamp
+	} else {
+		aDelta /= 2
+	}
+	aDelta += aDelta / aNumPoints
+
+	bias := 0
+	for aDelta > ((punyBase-punyTMin)*punyTMax)/2 {
+		aDelta /= punyBase - punyTMin
+		bias += punyBase
+	}
+
+	return bias + ((punyBase-punyTMin+1)*aDelta)/(aDelta+punySkew)
+} // punyAdapt()
+
+// `punyDigit()` returns the punycode digit character of `aValue`
+// (0-25: `a`-`z`, 26-35: `0`-`9`).
+func punyDigit(aValue int) byte {
+	if 26 > aValue {
+		return byte('a' + aValue)
+	}
+
+	return byte('0' + aValue - 26)
+} // punyDigit()
+
+// `punyEncode()` converts one (already lowercased) non-ASCII label
+// to its punycode form, without the `xn--` prefix.
+func punyEncode(aLabel string) string {
+	runes := []rune(aLabel)
+	out := make([]byte, 0, len(aLabel))
+	for _, r := range runes {
+		if 0x80 > r {
+			out = append(out, byte(r))
+		}
+	}
+	basic := len(out)
+	if 0 < basic {
+		out = append(out, '-')
+	}
+
+	handled, delta, bias := basic, 0, punyInitialBias
+	for n := rune(punyInitialN); handled < len(runes); {
+		m := rune(0x7FFFFFFF)
+		for _, r := range runes {
+			if (r >= n) && (r < m) {
+				m = r
+			}
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r == n {
+				q := delta
+				for k := punyBase; ; k += punyBase {
+					t := k - bias
+					if punyTMin > t {
+						t = punyTMin
+					} else if punyTMax < t {
+						t = punyTMax
+					}
+					if q < t {
+						break
+					}
+					out = append(out,
+						punyDigit(t+(q-t)%(punyBase-t)))
+					q = (q - t) / (punyBase - t)
+				}
+				out = append(out, punyDigit(q))
+				bias = punyAdapt(delta, handled+1, handled == basic)
+				delta = 0
+				handled++
+			}
+		}
+		delta++
+		n++
+	}
+
+	return string(out)
+} // punyEncode()
+
+/* _EoF_ */
@@ -0,0 +1,33 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"testing"
+)
+
+func TestNormalizeHostname(t *testing.T) {
+	for _, tc := range []struct {
+		hostname, want string
+	}{
+		{`Example.ORG`, `example.org`},
+		{`xn--bcher-kva.example`, `xn--bcher-kva.example`},
+		{`bücher.example`, `xn--bcher-kva.example`},
+		{`BÜCHER.example`, `xn--bcher-kva.example`},
+		{`münchen.sub.example`, `xn--mnchen-3ya.sub.example`},
+		{`点看.example`, `xn--3pxu8k.example`},
+	} {
+		if got := normalizeHostname(tc.hostname); tc.want != got {
+			t.Errorf("normalizeHostname(%q) = %q, want %q",
+				tc.hostname, got, tc.want)
+		}
+	}
+} // TestNormalizeHostname()
+
+/* _EoF_ */
@@ -0,0 +1,196 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `tK8sEndpoints` is the subset of a Kubernetes `Endpoints`
+	// object the pool cares about.
+	tK8sEndpoints struct {
+		Subsets []struct {
+			Addresses []struct {
+				IP string `json:"ip"`
+			} `json:"addresses"`
+			Ports []struct {
+				Name string `json:"name"`
+				Port int    `json:"port"`
+			} `json:"ports"`
+		} `json:"subsets"`
+	}
+)
+
+// service account credentials mounted into every pod:
+const (
+	k8sTokenFile = `/var/run/secrets/kubernetes.io/serviceaccount/token` // #nosec G101
+	k8sCAFile    = `/var/run/secrets/kubernetes.io/serviceaccount/ca.crt`
+)
+
+var (
+	// `gK8sOnce` guards the lazy in-cluster client setup.
+	gK8sOnce sync.Once
+
+	// `gK8sClient` is the authenticated API server client.
+	gK8sClient *http.Client
+
+	// `gK8sAPI` is the API server base URL.
+	gK8sAPI string
+
+	// `gK8sToken` is the service account bearer token.
+	gK8sToken string
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newK8sPool()` creates an upstream pool fed by a Kubernetes
+// `Endpoints` object, allowing the proxy to act as a simple ingress
+// for a small cluster.
+//
+// The target URL names namespace, service, and (optionally) port,
+// e.g. `k8s://default/web:8080`; the API server is reached with the
+// in-cluster service account credentials. Backends get `https` URLs
+// when the `k8s+https` scheme is given.
+func newK8sPool(aTarget *url.URL, aLogger *TLogger) *tUpstreamPool {
+	scheme := `http`
+	if `k8s+https` == aTarget.Scheme {
+		scheme = `https`
+	}
+	namespace := aTarget.Host
+	service := strings.Trim(aTarget.Path, `/`)
+	port := ``
+	if idx := strings.LastIndexByte(service, ':'); 0 < idx {
+		service, port = service[:idx], service[idx+1:]
+	}
+
+	return &tUpstreamPool{
+		service: namespace + `/` + service,
+		lookup: func() ([]tPoolTarget, error) {
+			return k8sLookup(namespace, service, port, scheme)
+		},
+		logger: aLogger,
+	}
+} // newK8sPool()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `k8sClient()` lazily builds the in-cluster API server client from
+// the mounted service account credentials.
+func k8sClient() (*http.Client, string, string, error) {
+	gK8sOnce.Do(func() {
+		host, port := os.Getenv(`KUBERNETES_SERVICE_HOST`),
+			os.Getenv(`KUBERNETES_SERVICE_PORT`)
+		if ("" == host) || ("" == port) {
+			return // not running inside a cluster
+		}
+		token, err := os.ReadFile(k8sTokenFile)
+		if nil != err {
+			return
+		}
+		caData, err := os.ReadFile(k8sCAFile)
+		if nil != err {
+			return
+		}
+		caPool := x509.NewCertPool()
+		caPool.AppendCertsFromPEM(caData)
+
+		gK8sAPI = `https://` + net.JoinHostPort(host, port)
+		gK8sToken = strings.TrimSpace(string(token))
+		gK8sClient = &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: caPool},
+			},
+		}
+	})
+	if nil == gK8sClient {
+		return nil, ``, ``,
+			fmt.Errorf("no in-cluster Kubernetes credentials found")
+	}
+
+	return gK8sClient, gK8sAPI, gK8sToken, nil
+} // k8sClient()
+
+// `k8sLookup()` reads the `Endpoints` of `aService` in `aNamespace`
+// turning its ready addresses into pool targets with `aScheme`
+// backend URLs; `aPort` selects a named or numbered port (the first
+// one is used when empty).
+func k8sLookup(aNamespace, aService, aPort, aScheme string) ([]tPoolTarget, error) {
+	client, api, token, err := k8sClient()
+	if nil != err {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf(
+		`%s/api/v1/namespaces/%s/endpoints/%s`,
+		api, aNamespace, aService), nil)
+	if nil != err {
+		return nil, err
+	}
+	req.Header.Set(`Authorization`, `Bearer `+token)
+	resp, err := client.Do(req)
+	if nil != err {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if http.StatusOK != resp.StatusCode {
+		return nil, fmt.Errorf("API server answered %s", resp.Status)
+	}
+
+	var endpoints tK8sEndpoints
+	if err = json.NewDecoder(resp.Body).Decode(&endpoints); nil != err {
+		return nil, err
+	}
+
+	var targets []tPoolTarget
+	for _, subset := range endpoints.Subsets {
+		port := 0
+		for idx, sp := range subset.Ports {
+			if ("" == aPort) && (0 == idx) {
+				port = sp.Port
+			}
+			if ("" != aPort) &&
+				((sp.Name == aPort) || (strconv.Itoa(sp.Port) == aPort)) {
+				port = sp.Port
+				break
+			}
+		}
+		if 0 == port {
+			continue
+		}
+		for _, address := range subset.Addresses {
+			targets = append(targets, tPoolTarget{
+				target: &url.URL{
+					Scheme: aScheme,
+					Host: net.JoinHostPort(address.IP,
+						strconv.Itoa(port)),
+				},
+				weight: 1,
+			})
+		}
+	}
+
+	return targets, nil
+} // k8sLookup()
+
+/* _EoF_ */
@@ -0,0 +1,288 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `TLimitsConfig` guards against oversized and malformed
+	// requests before they reach any backend.
+	TLimitsConfig struct {
+		// MaxHeaderCount is the maximum number of header values
+		// accepted per request (default 128).
+		MaxHeaderCount int `json:"maxHeaderCount,omitempty"`
+
+		// MaxHeaderKB is the accepted total size of all request
+		// headers in KiB (default 64).
+		MaxHeaderKB int `json:"maxHeaderKB,omitempty"`
+
+		// MaxURIBytes is the accepted length of the request URI
+		// (default 8192).
+		MaxURIBytes int `json:"maxURIBytes,omitempty"`
+
+		// BanAfter is the number of violations after which the
+		// offending client address is banned outright; zero
+		// disables banning.
+		BanAfter int `json:"banAfter,omitempty"`
+
+		// BanMinutes is how long a ban lasts (default 10).
+		BanMinutes int `json:"banMinutes,omitempty"`
+
+		// Redis optionally shares the violation counters and bans
+		// between all reprox replicas, see `TRedisConfig`.  During
+		// a Redis outage limiting degrades to local-only.
+		Redis *TRedisConfig `json:"redis,omitempty"`
+	}
+
+	// `tOffender` tracks one client address's violations.
+	tOffender struct {
+		count int
+		until time.Time // end of an active ban
+	}
+
+	// `tOffenderState` is the persisted form of one offender entry.
+	tOffenderState struct {
+		Count int       `json:"count"`
+		Until time.Time `json:"until,omitempty"`
+	}
+
+	// `tRequestGuard` enforces the configured request limits and
+	// keeps the short-lived ban list of repeat offenders.
+	tRequestGuard struct {
+		mtx       sync.Mutex
+		conf      *TLimitsConfig
+		banFor    time.Duration
+		offenders map[string]*tOffender
+		shared    *tRedisClient // replica-wide counters (nil: local only)
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRequestGuard()` creates a guard enforcing `aConfig`, filling
+// in the usual defaults.
+func newRequestGuard(aConfig *TLimitsConfig) *tRequestGuard {
+	if 0 >= aConfig.MaxHeaderCount {
+		aConfig.MaxHeaderCount = 128
+	}
+	if 0 >= aConfig.MaxHeaderKB {
+		aConfig.MaxHeaderKB = 64
+	}
+	if 0 >= aConfig.MaxURIBytes {
+		aConfig.MaxURIBytes = 8192
+	}
+	banFor := time.Duration(aConfig.BanMinutes) * time.Minute
+	if 0 >= banFor {
+		banFor = 10 * time.Minute
+	}
+
+	guard := &tRequestGuard{
+		conf:      aConfig,
+		banFor:    banFor,
+		offenders: make(map[string]*tOffender),
+	}
+	if (nil != aConfig.Redis) && ("" != aConfig.Redis.Addr) {
+		guard.shared = newRedisClient(aConfig.Redis)
+	}
+
+	return guard
+} // newRequestGuard()
+
+// `bansBucket` is the state-store bucket holding the offender table.
+const bansBucket = `bans`
+
+// --------------------------------------------------------------------------
+// tRequestGuard methods:
+
+// `banned()` reports whether `aAddr` is currently banned, expiring
+// stale entries as a side effect.
+func (rg *tRequestGuard) banned(aAddr string) bool {
+	ip := hostOnly(aAddr)
+
+	rg.mtx.Lock()
+	offender, ok := rg.offenders[ip]
+	if ok && (!offender.until.IsZero()) {
+		if time.Now().After(offender.until) {
+			delete(rg.offenders, ip)
+		} else {
+			rg.mtx.Unlock()
+			return true
+		}
+	}
+	rg.mtx.Unlock()
+
+	// another replica may have banned the address; a Redis problem
+	// degrades to the local verdict above:
+	if nil != rg.shared {
+		if shared, err := rg.shared.banned(ip); (nil == err) && shared {
+			return true
+		}
+	}
+
+	return false
+} // banned()
+
+// `check()` validates `aRequest` against the configured limits.
+//
+// Returns:
+//   - `int`: The HTTP status to reject with, or `0` if acceptable.
+//   - `string`: The reason for the rejection.
+func (rg *tRequestGuard) check(aRequest *http.Request) (int, string) {
+	if !validHostname(hostOnly(aRequest.Host)) {
+		return http.StatusBadRequest, `invalid Host header`
+	}
+	if len(aRequest.RequestURI) > rg.conf.MaxURIBytes {
+		return http.StatusBadRequest, `request URI too long`
+	}
+
+	count, size := 0, 0
+	for name, values := range aRequest.Header {
+		count += len(values)
+		for _, value := range values {
+			size += len(name) + len(value)
+		}
+	}
+	if count > rg.conf.MaxHeaderCount {
+		return http.StatusRequestHeaderFieldsTooLarge,
+			`too many request headers`
+	}
+	if size > (rg.conf.MaxHeaderKB << 10) {
+		return http.StatusRequestHeaderFieldsTooLarge,
+			`request headers too large`
+	}
+
+	return 0, ``
+} // check()
+
+// `loadFrom()` restores the offender table from `aStore`, dropping
+// entries whose ban already expired.
+//
+// Parameters:
+//   - `aStore`: The state store to read from.
+//
+// Returns:
+//   - `error`: A storage problem (`nil` on success).
+func (rg *tRequestGuard) loadFrom(aStore TStateStore) error {
+	entries, err := aStore.Load(bansBucket)
+	if nil != err {
+		return err
+	}
+	now := time.Now()
+
+	rg.mtx.Lock()
+	defer rg.mtx.Unlock()
+	for ip, data := range entries {
+		var state tOffenderState
+		if err := json.Unmarshal(data, &state); nil != err {
+			continue // skip corrupted entries
+		}
+		if (!state.Until.IsZero()) && now.After(state.Until) {
+			continue // ban expired while we were down
+		}
+		rg.offenders[ip] = &tOffender{count: state.Count, until: state.Until}
+	}
+
+	return nil
+} // loadFrom()
+
+// `saveTo()` persists the current offender table to `aStore`,
+// leaving out entries whose ban already expired.
+//
+// Parameters:
+//   - `aStore`: The state store to write to.
+//
+// Returns:
+//   - `error`: A storage problem (`nil` on success).
+func (rg *tRequestGuard) saveTo(aStore TStateStore) error {
+	now := time.Now()
+
+	rg.mtx.Lock()
+	entries := make(map[string][]byte, len(rg.offenders))
+	for ip, offender := range rg.offenders {
+		if (!offender.until.IsZero()) && now.After(offender.until) {
+			continue
+		}
+		data, err := json.Marshal(tOffenderState{
+			Count: offender.count, Until: offender.until})
+		if nil == err {
+			entries[ip] = data
+		}
+	}
+	rg.mtx.Unlock()
+
+	return aStore.Save(bansBucket, entries)
+} // saveTo()
+
+// `violation()` counts one violation of `aAddr`, starting a ban once
+// the configured threshold is reached.
+func (rg *tRequestGuard) violation(aAddr string) {
+	if 0 >= rg.conf.BanAfter {
+		return
+	}
+	ip := hostOnly(aAddr)
+
+	rg.mtx.Lock()
+	offender, ok := rg.offenders[ip]
+	if !ok {
+		offender = &tOffender{}
+		rg.offenders[ip] = offender
+	}
+	offender.count++
+	count := offender.count
+	if count >= rg.conf.BanAfter {
+		offender.until = time.Now().Add(rg.banFor)
+	}
+	rg.mtx.Unlock()
+
+	if nil == rg.shared {
+		return
+	}
+	// count replica-wide so an offender can't use each replica's
+	// full allowance; a Redis problem keeps the local count:
+	if shared, err := rg.shared.incr(`viol:`+ip, rg.banFor); nil == err {
+		if shared >= int64(rg.conf.BanAfter) {
+			_ = rg.shared.ban(ip, rg.banFor)
+			rg.mtx.Lock()
+			offender.until = time.Now().Add(rg.banFor)
+			rg.mtx.Unlock()
+		}
+	}
+} // violation()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `validHostname()` reports whether `aHostname` looks like a
+// legitimate DNS name, IPv4 address, or bracket-less IPv6 literal.
+func validHostname(aHostname string) bool {
+	if ("" == aHostname) || (253 < len(aHostname)) {
+		return false
+	}
+	for _, ch := range aHostname {
+		switch {
+		case ('a' <= ch) && ('z' >= ch):
+		case ('A' <= ch) && ('Z' >= ch):
+		case ('0' <= ch) && ('9' >= ch):
+		case ('.' == ch) || ('-' == ch) || ('_' == ch) || (':' == ch):
+		default:
+			return false
+		}
+	}
+
+	return !strings.HasPrefix(aHostname, `-`)
+} // validHostname()
+
+/* _EoF_ */
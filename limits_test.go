@@ -0,0 +1,60 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestGuardCheck(t *testing.T) {
+	guard := newRequestGuard(&TLimitsConfig{
+		MaxHeaderCount: 4,
+		MaxURIBytes:    64,
+		BanAfter:       2,
+		BanMinutes:     1,
+	})
+
+	req := httptest.NewRequest(`GET`, `http://example.org/`, nil)
+	if status, reason := guard.check(req); 0 != status {
+		t.Errorf("check() rejected a plain request: %d %q", status, reason)
+	}
+
+	req.Host = `exa mple.org`
+	if status, _ := guard.check(req); 400 != status {
+		t.Errorf("check() = %d for an invalid Host, want 400", status)
+	}
+	req.Host = `example.org`
+
+	req.RequestURI = `/` + strings.Repeat(`x`, 64)
+	if status, _ := guard.check(req); 400 != status {
+		t.Errorf("check() = %d for an oversized URI, want 400", status)
+	}
+	req.RequestURI = `/`
+
+	for idx := 0; 5 > idx; idx++ {
+		req.Header.Add(`X-Padding`, `yes`)
+	}
+	if status, _ := guard.check(req); 431 != status {
+		t.Errorf("check() = %d for too many headers, want 431", status)
+	}
+
+	// two violations reach the `BanAfter` threshold:
+	guard.violation(`192.0.2.7:123`)
+	if guard.banned(`192.0.2.7:456`) {
+		t.Error(`banned() = true after a single violation`)
+	}
+	guard.violation(`192.0.2.7:456`)
+	if !guard.banned(`192.0.2.7:789`) {
+		t.Error(`banned() = false after reaching the threshold`)
+	}
+} // TestRequestGuardCheck()
+
+/* _EoF_ */
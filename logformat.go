@@ -0,0 +1,217 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// `tLogEvent` carries everything one access log line may show.
+	tLogEvent struct {
+		request  *http.Request
+		status   int
+		size     int64
+		duration time.Duration
+		backend  string // selected backend target
+	}
+
+	// `tLogField` renders one directive of a log format.
+	tLogField func(aEvent *tLogEvent) string
+
+	// `tLogFormat` is a compiled access log format: a sequence of
+	// literals and field renderers.
+	tLogFormat []tLogField
+)
+
+// `defaultLogFormat` is the Apache `combined` format used when no
+// own format is configured.
+const defaultLogFormat = `%h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-Agent}i"`
+
+// --------------------------------------------------------------------------
+// tLogFormat methods:
+
+// `render()` produces the access log line of `aEvent`.
+func (lf tLogFormat) render(aEvent *tLogEvent) string {
+	var builder strings.Builder
+	for _, field := range lf {
+		builder.WriteString(field(aEvent))
+	}
+
+	return builder.String()
+} // render()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `compileLogFormat()` translates an Apache `LogFormat` style
+// string into its compiled form; unknown directives are kept
+// verbatim so typos are visible in the log instead of vanishing.
+//
+// Supported: `%h` client address, `%l` and `%u` identity/user,
+// `%t` timestamp, `%r` request line, `%s`/`%>s` status, `%b`/`%B`
+// body size, `%v` virtual host, `%D` duration in microseconds,
+// `%T` duration in seconds, `%{Name}i` request header, and the
+// proxy extensions `%{backend}x` and `%{latency_ms}x`.
+func compileLogFormat(aFormat string) tLogFormat {
+	if "" == aFormat {
+		aFormat = defaultLogFormat
+	}
+
+	var format tLogFormat
+	literal := func(aText string) {
+		if "" != aText {
+			format = append(format,
+				func(*tLogEvent) string { return aText })
+		}
+	}
+	for {
+		idx := strings.IndexByte(aFormat, '%')
+		if (0 > idx) || (len(aFormat)-1 == idx) {
+			literal(aFormat)
+			return format
+		}
+		literal(aFormat[:idx])
+		aFormat = aFormat[idx+1:]
+
+		// a `%{argument}directive` form?
+		argument := ``
+		if ('{' == aFormat[0]) &&
+			(0 < strings.IndexByte(aFormat, '}')) {
+			end := strings.IndexByte(aFormat, '}')
+			argument = aFormat[1:end]
+			aFormat = aFormat[end+1:]
+			if "" == aFormat {
+				return format
+			}
+		}
+		directive := aFormat[0]
+		if ('>' == directive) && (1 < len(aFormat)) {
+			directive = aFormat[1] // `%>s` equals `%s`
+			aFormat = aFormat[1:]
+		}
+		aFormat = aFormat[1:]
+
+		if field := logField(directive, argument); nil != field {
+			format = append(format, field)
+		} else {
+			literal(`%` + string(directive))
+		}
+	}
+} // compileLogFormat()
+
+// `logField()` returns the renderer of one format directive, or
+// `nil` for unknown ones.
+func logField(aDirective byte, aArgument string) tLogField {
+	switch aDirective {
+	case '%':
+		return func(*tLogEvent) string { return `%` }
+
+	case 'h':
+		return func(aE *tLogEvent) string {
+			remote, _, err := net.SplitHostPort(aE.request.RemoteAddr)
+			if nil != err {
+				remote = aE.request.RemoteAddr
+			}
+			return remote
+		}
+
+	case 'l':
+		return func(*tLogEvent) string { return `-` }
+
+	case 'u':
+		return func(aE *tLogEvent) string {
+			if nil != aE.request.URL.User {
+				if name := aE.request.URL.User.Username(); "" != name {
+					return name
+				}
+			}
+			return `-`
+		}
+
+	case 't':
+		return func(*tLogEvent) string {
+			return time.Now().Format(apacheTimeFormat)
+		}
+
+	case 'r':
+		return func(aE *tLogEvent) string {
+			return aE.request.Method + ` ` +
+				aE.request.RequestURI + ` ` + aE.request.Proto
+		}
+
+	case 's':
+		return func(aE *tLogEvent) string {
+			return strconv.Itoa(aE.status)
+		}
+
+	case 'b':
+		return func(aE *tLogEvent) string {
+			if 0 == aE.size {
+				return `-`
+			}
+			return strconv.FormatInt(aE.size, 10)
+		}
+
+	case 'B':
+		return func(aE *tLogEvent) string {
+			return strconv.FormatInt(aE.size, 10)
+		}
+
+	case 'v':
+		return func(aE *tLogEvent) string {
+			return hostOnly(aE.request.Host)
+		}
+
+	case 'D':
+		return func(aE *tLogEvent) string {
+			return strconv.FormatInt(
+				aE.duration.Microseconds(), 10)
+		}
+
+	case 'T':
+		return func(aE *tLogEvent) string {
+			return strconv.FormatInt(
+				int64(aE.duration/time.Second), 10)
+		}
+
+	case 'i':
+		name := aArgument
+		return func(aE *tLogEvent) string {
+			if value := aE.request.Header.Get(name); "" != value {
+				return value
+			}
+			return `-`
+		}
+
+	case 'x':
+		switch aArgument {
+		case `backend`:
+			return func(aE *tLogEvent) string {
+				if "" == aE.backend {
+					return `-`
+				}
+				return aE.backend
+			}
+		case `latency_ms`:
+			return func(aE *tLogEvent) string {
+				return strconv.FormatInt(
+					aE.duration.Milliseconds(), 10)
+			}
+		}
+	}
+
+	return nil
+} // logField()
+
+/* _EoF_ */
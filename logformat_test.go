@@ -0,0 +1,46 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCompileLogFormat(t *testing.T) {
+	req := httptest.NewRequest(`GET`, `http://example.org/page`, nil)
+	req.RemoteAddr = `192.0.2.7:4711`
+	req.Header.Set(`User-Agent`, `tester/1.0`)
+	event := &tLogEvent{
+		request:  req,
+		status:   204,
+		size:     0,
+		duration: 1500 * time.Millisecond,
+		backend:  `http://127.0.0.1:8080`,
+	}
+
+	for _, tc := range []struct {
+		format, want string
+	}{
+		{`%h`, `192.0.2.7`},
+		{`%>s %b %B`, `204 - 0`},
+		{`%v`, `example.org`},
+		{`%{User-Agent}i`, `tester/1.0`},
+		{`%{backend}x (%{latency_ms}x ms)`,
+			`http://127.0.0.1:8080 (1500 ms)`},
+		{`%T sec, 100%%`, `1 sec, 100%`},
+	} {
+		if got := compileLogFormat(tc.format).render(event); tc.want != got {
+			t.Errorf("render(%q) = %q, want %q", tc.format, got, tc.want)
+		}
+	}
+} // TestCompileLogFormat()
+
+/* _EoF_ */
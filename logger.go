@@ -0,0 +1,306 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `tLogFile` is a single shared, serialised log file writer.
+	//
+	// Several virtual hosts may point their access or error log at
+	// the same file; they then share one `tLogFile` instance so the
+	// individual log lines don't get interleaved mid-line.
+	tLogFile struct {
+		mtx      sync.Mutex
+		file     io.WriteCloser
+		name     string
+		size     int64
+		opened   time.Time
+		rotation *TLogRotation
+	}
+
+	// `TLogSampling` reduces the access log volume on high-traffic
+	// sites.
+	//
+	// Requests answered with a status of 400 or above are always
+	// logged; only successful ones are sampled.
+	TLogSampling struct {
+		// SuccessPercent is the percentage (1-100) of successful
+		// requests that gets logged; zero means 100.
+		SuccessPercent int `json:"successPercent"`
+
+		// SuppressPaths lists URL paths never logged at all (e.g.
+		// health check endpoints); a trailing `*` matches a prefix.
+		SuppressPaths []string `json:"suppressPaths,omitempty"`
+	}
+
+	// `TLogger` dispatches access and error log lines to the log
+	// files of the respective virtual host, falling back to the
+	// globally configured files for hosts without their own ones.
+	TLogger struct {
+		mtx       sync.RWMutex
+		files     map[string]tLogSink // destination -> shared sink
+		access    map[string]tLogSink // hostname -> access log
+		errors    map[string]tLogSink // hostname -> error log
+		defAccess tLogSink
+		defErrors tLogSink
+		format    tLogFormat
+		rotation  *TLogRotation
+		sampling  *TLogSampling
+	}
+)
+
+// `apacheTimeFormat` is the timestamp layout used by the Apache
+// `common`/`combined` log formats.
+const apacheTimeFormat = `[02/Jan/2006:15:04:05 -0700]`
+
+// --------------------------------------------------------------------------
+// tLogFile methods:
+
+// `close()` closes the underlying log file.
+func (lf *tLogFile) close() error {
+	lf.mtx.Lock()
+	defer lf.mtx.Unlock()
+
+	if nil == lf.file {
+		return nil
+	}
+	err := lf.file.Close()
+	lf.file = nil
+
+	return err
+} // close()
+
+// `write()` appends `aLine` to the log file.
+func (lf *tLogFile) write(aLine string) {
+	lf.mtx.Lock()
+	defer lf.mtx.Unlock()
+
+	if nil == lf.file {
+		return
+	}
+	written, _ := fmt.Fprintln(lf.file, aLine)
+	lf.size += int64(written)
+	if lf.needsRotation() {
+		lf.rotate()
+	}
+} // write()
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewLogger()` opens the log files configured in `aConfig` and sets
+// up the per-host dispatch tables.
+//
+// Hosts pointing at the same log file share a single writer so that
+// concurrently written lines don't get garbled.
+//
+// Parameters:
+//   - `aConfig`: The proxy configuration listing the log files.
+//
+// Returns:
+//   - `*TLogger`: The new logger instance.
+//   - `error`: A possible error opening one of the log files.
+func NewLogger(aConfig *TConfig) (*TLogger, error) {
+	lg := &TLogger{
+		files:    make(map[string]tLogSink),
+		access:   make(map[string]tLogSink),
+		errors:   make(map[string]tLogSink),
+		format:   compileLogFormat(aConfig.AccessLogFormat),
+		rotation: aConfig.LogRotation,
+		sampling: aConfig.LogSampling,
+	}
+
+	var err error
+	if lg.defAccess, err = lg.openShared(aConfig.AccessLog); nil != err {
+		return nil, err
+	}
+	if lg.defErrors, err = lg.openShared(aConfig.ErrorLog); nil != err {
+		return nil, err
+	}
+
+	for hostname, host := range aConfig.Hosts {
+		if "" != host.AccessLog {
+			lf, err := lg.openShared(host.AccessLog)
+			if nil != err {
+				return nil, err
+			}
+			lg.access[hostname] = lf
+		}
+		if "" != host.ErrorLog {
+			lf, err := lg.openShared(host.ErrorLog)
+			if nil != err {
+				return nil, err
+			}
+			lg.errors[hostname] = lf
+		}
+	}
+
+	return lg, nil
+} // NewLogger()
+
+// --------------------------------------------------------------------------
+// TLogger methods:
+
+// `Close()` closes all open log files.
+func (lg *TLogger) Close() error {
+	lg.mtx.Lock()
+	defer lg.mtx.Unlock()
+
+	var err error
+	for _, lf := range lg.files {
+		if e := lf.close(); (nil != e) && (nil == err) {
+			err = e
+		}
+	}
+	lg.files = make(map[string]tLogSink)
+
+	return err
+} // Close()
+
+// `Error()` writes `aMessage` to the error log of `aHostname`,
+// or to the global error log if the host doesn't have its own one.
+//
+// Parameters:
+//   - `aHostname`: The virtual host the message belongs to.
+//   - `aMessage`: The text to log.
+func (lg *TLogger) Error(aHostname, aMessage string) {
+	lg.mtx.RLock()
+	lf, ok := lg.errors[aHostname]
+	if !ok {
+		lf = lg.defErrors
+	}
+	lg.mtx.RUnlock()
+
+	if nil != lf {
+		lf.write(time.Now().Format(apacheTimeFormat) +
+			` [error] [host ` + aHostname + `] ` + aMessage)
+	}
+} // Error()
+
+// `Log()` writes one access log line (in the configured format,
+// Apache `combined` by default) to the access log of the request's
+// virtual host.
+//
+// Parameters:
+//   - `aRequest`: The handled HTTP request.
+//   - `aStatus`: The HTTP status code sent to the client.
+//   - `aSize`: The number of body bytes sent to the client.
+//   - `aDuration`: The total time spent serving the request.
+//   - `aBackend`: The selected backend target (may be empty).
+func (lg *TLogger) Log(aRequest *http.Request, aStatus int, aSize int64,
+	aDuration time.Duration, aBackend string) {
+	if !lg.shouldLog(aRequest.URL.Path, aStatus) {
+		return
+	}
+	hostname := hostOnly(aRequest.Host)
+	lg.mtx.RLock()
+	lf, ok := lg.access[hostname]
+	if !ok {
+		lf = lg.defAccess
+	}
+	format := lg.format
+	lg.mtx.RUnlock()
+	if nil == lf {
+		return
+	}
+	if nil == format {
+		format = compileLogFormat(``)
+	}
+
+	lf.write(format.render(&tLogEvent{
+		request:  aRequest,
+		status:   aStatus,
+		size:     aSize,
+		duration: aDuration,
+		backend:  aBackend,
+	}))
+} // Log()
+
+// `openShared()` returns the shared sink for the log destination
+// `aSpec`, creating it on first use; an empty spec yields nil.
+func (lg *TLogger) openShared(aSpec string) (tLogSink, error) {
+	if "" == aSpec {
+		return nil, nil
+	}
+	if !isLogScheme(aSpec) {
+		aSpec = filepath.Clean(aSpec)
+	}
+
+	lg.mtx.Lock()
+	defer lg.mtx.Unlock()
+	if sink, ok := lg.files[aSpec]; ok {
+		return sink, nil
+	}
+
+	sink, err := newLogSink(aSpec, lg.rotation)
+	if nil != err {
+		return nil, err
+	}
+	lg.files[aSpec] = sink
+
+	return sink, nil
+} // openShared()
+
+// `shouldLog()` applies the configured sampling and suppression
+// rules to one access log entry.
+func (lg *TLogger) shouldLog(aPath string, aStatus int) bool {
+	if nil == lg.sampling {
+		return true
+	}
+	for _, suppress := range lg.sampling.SuppressPaths {
+		if prefix, ok := strings.CutSuffix(suppress, `*`); ok {
+			if strings.HasPrefix(aPath, prefix) {
+				return false
+			}
+		} else if aPath == suppress {
+			return false
+		}
+	}
+	if 400 <= aStatus {
+		return true // errors are always logged
+	}
+	percent := lg.sampling.SuccessPercent
+	if (0 >= percent) || (100 <= percent) {
+		return true
+	}
+
+	return rand.Intn(100) < percent
+} // shouldLog()
+
+// `isLogScheme()` reports whether `aSpec` names a non-file log
+// target like `syslog:` or `journald:`.
+func isLogScheme(aSpec string) bool {
+	return strings.HasPrefix(aSpec, `syslog:`) ||
+		(`journald:` == aSpec)
+} // isLogScheme()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `hostOnly()` strips an optional port from `aHost`.
+func hostOnly(aHost string) string {
+	if host, _, err := net.SplitHostPort(aHost); nil == err {
+		return host
+	}
+
+	return aHost
+} // hostOnly()
+
+/* _EoF_ */
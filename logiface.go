@@ -0,0 +1,147 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+type (
+	// `TLogField` is one key/value pair attached to a log message.
+	TLogField struct {
+		Key   string
+		Value any
+	}
+
+	// `TEventLogger` is the minimal leveled logging interface used
+	// for the proxy's operational events.
+	//
+	// Embedders can plug in their own implementation via
+	// `WithEventLogger()`; the package ships adapters for the
+	// built-in Apache-style logger (`NewApacheLogAdapter()`) and
+	// for `log/slog` (`NewSlogAdapter()`), so nobody is forced to
+	// use either.
+	TEventLogger interface {
+		// Info logs a routine operational event.
+		Info(aMessage string, aFields ...TLogField)
+
+		// Warn logs a suspicious but non-fatal event.
+		Warn(aMessage string, aFields ...TLogField)
+
+		// Error logs a failed operation.
+		Error(aMessage string, aFields ...TLogField)
+	}
+
+	// `tApacheLogAdapter` renders leveled events into the
+	// Apache-style error log of a `TLogger`.
+	tApacheLogAdapter struct {
+		logger *TLogger
+	}
+
+	// `tSlogAdapter` forwards events to a structured `slog.Logger`.
+	tSlogAdapter struct {
+		logger *slog.Logger
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor functions:
+
+// `NewApacheLogAdapter()` wraps the proxy's Apache-style logger in
+// the `TEventLogger` interface; events end up in the error log.
+//
+// Parameters:
+//   - `aLogger`: The Apache-style logger to write to.
+//
+// Returns:
+//   - `TEventLogger`: The adapter instance.
+func NewApacheLogAdapter(aLogger *TLogger) TEventLogger {
+	return &tApacheLogAdapter{logger: aLogger}
+} // NewApacheLogAdapter()
+
+// `NewSlogAdapter()` wraps a structured `log/slog` logger in the
+// `TEventLogger` interface.
+//
+// Parameters:
+//   - `aLogger`: The slog logger to forward events to.
+//
+// Returns:
+//   - `TEventLogger`: The adapter instance.
+func NewSlogAdapter(aLogger *slog.Logger) TEventLogger {
+	return &tSlogAdapter{logger: aLogger}
+} // NewSlogAdapter()
+
+// --------------------------------------------------------------------------
+// tApacheLogAdapter methods:
+
+// `Info()` implements `TEventLogger`.
+func (ala *tApacheLogAdapter) Info(aMessage string, aFields ...TLogField) {
+	ala.write(`info`, aMessage, aFields)
+} // Info()
+
+// `Warn()` implements `TEventLogger`.
+func (ala *tApacheLogAdapter) Warn(aMessage string, aFields ...TLogField) {
+	ala.write(`warn`, aMessage, aFields)
+} // Warn()
+
+// `Error()` implements `TEventLogger`.
+func (ala *tApacheLogAdapter) Error(aMessage string, aFields ...TLogField) {
+	ala.write(`error`, aMessage, aFields)
+} // Error()
+
+// `write()` renders one event as a single error log line; a `host`
+// field selects the per-host error log.
+func (ala *tApacheLogAdapter) write(aLevel, aMessage string, aFields []TLogField) {
+	if (nil == ala) || (nil == ala.logger) {
+		return
+	}
+	hostname := ``
+	line := strings.Builder{}
+	line.WriteString(`[` + aLevel + `] ` + aMessage)
+	for _, field := range aFields {
+		if `host` == field.Key {
+			hostname, _ = field.Value.(string)
+			continue
+		}
+		fmt.Fprintf(&line, ` %s=%v`, field.Key, field.Value)
+	}
+	ala.logger.Error(hostname, line.String())
+} // write()
+
+// --------------------------------------------------------------------------
+// tSlogAdapter methods:
+
+// `Info()` implements `TEventLogger`.
+func (sla *tSlogAdapter) Info(aMessage string, aFields ...TLogField) {
+	sla.logger.Info(aMessage, slogArgs(aFields)...)
+} // Info()
+
+// `Warn()` implements `TEventLogger`.
+func (sla *tSlogAdapter) Warn(aMessage string, aFields ...TLogField) {
+	sla.logger.Warn(aMessage, slogArgs(aFields)...)
+} // Warn()
+
+// `Error()` implements `TEventLogger`.
+func (sla *tSlogAdapter) Error(aMessage string, aFields ...TLogField) {
+	sla.logger.Error(aMessage, slogArgs(aFields)...)
+} // Error()
+
+// `slogArgs()` converts the fields into `slog` key/value arguments.
+func slogArgs(aFields []TLogField) []any {
+	args := make([]any, 0, len(aFields)*2)
+	for _, field := range aFields {
+		args = append(args, field.Key, field.Value)
+	}
+
+	return args
+} // slogArgs()
+
+/* _EoF_ */
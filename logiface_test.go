@@ -0,0 +1,41 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogAdapter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	events := NewSlogAdapter(slog.New(slog.NewTextHandler(buffer, nil)))
+
+	events.Warn(`backend slow`,
+		TLogField{Key: `host`, Value: `example.org`},
+		TLogField{Key: `millis`, Value: 1500})
+	line := buffer.String()
+	for _, want := range []string{
+		`level=WARN`, `msg="backend slow"`,
+		`host=example.org`, `millis=1500`,
+	} {
+		if !strings.Contains(line, want) {
+			t.Errorf(`log line %q lacks %q`, line, want)
+		}
+	}
+
+	// the Apache adapter must cope with an empty logger:
+	apache := NewApacheLogAdapter(&TLogger{})
+	apache.Info(`ignored`, TLogField{Key: `host`, Value: `example.org`})
+	apache.Error(`ignored`)
+} // TestSlogAdapter()
+
+/* _EoF_ */
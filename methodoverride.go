@@ -0,0 +1,83 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// `maxOverrideBody` caps how much of a form body is buffered while
+// looking for a `_method` field.
+const maxOverrideBody = 64 << 10 // 64 KiB
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `applyMethodOverride()` rewrites the method of a `POST` request
+// carrying an `X-HTTP-Method-Override` header (or `_method` form
+// field) to the requested method, provided it's on the host's
+// allow-list; clients behind restrictive proxies or firewalls can
+// thus still issue e.g. `PUT`/`DELETE` requests.
+//
+// Parameters:
+//   - `aRequest`: The incoming HTTP request to possibly rewrite.
+//   - `aAllowed`: The methods that may be assumed via override.
+func applyMethodOverride(aRequest *http.Request, aAllowed []string) {
+	if (0 == len(aAllowed)) || (http.MethodPost != aRequest.Method) {
+		return
+	}
+	override := aRequest.Header.Get(`X-HTTP-Method-Override`)
+	if "" == override {
+		override = overrideFromForm(aRequest)
+	}
+	if "" == override {
+		return
+	}
+
+	for _, method := range aAllowed {
+		if strings.EqualFold(method, override) {
+			aRequest.Method = strings.ToUpper(override)
+			aRequest.Header.Del(`X-HTTP-Method-Override`)
+			return
+		}
+	}
+} // applyMethodOverride()
+
+// `overrideFromForm()` extracts the `_method` field from an
+// urlencoded form body, restoring the body so it can still be
+// forwarded to the backend unchanged.
+func overrideFromForm(aRequest *http.Request) string {
+	ctype, _, _ := strings.Cut(aRequest.Header.Get(`Content-Type`), `;`)
+	if `application/x-www-form-urlencoded` != strings.TrimSpace(ctype) {
+		return ``
+	}
+	if (nil == aRequest.Body) || (0 > aRequest.ContentLength) ||
+		(maxOverrideBody < aRequest.ContentLength) {
+		return ``
+	}
+
+	data, err := io.ReadAll(io.LimitReader(aRequest.Body, maxOverrideBody))
+	// the body must stay replayable for the backend:
+	aRequest.Body = io.NopCloser(bytes.NewReader(data))
+	if nil != err {
+		return ``
+	}
+	values, err := url.ParseQuery(string(data))
+	if nil != err {
+		return ``
+	}
+
+	return values.Get(`_method`)
+} // overrideFromForm()
+
+/* _EoF_ */
@@ -0,0 +1,63 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestApplyMethodOverride(t *testing.T) {
+	allowed := []string{`PUT`, `DELETE`}
+
+	req := httptest.NewRequest(http.MethodPost, `http://example.org/`, nil)
+	req.Header.Set(`X-HTTP-Method-Override`, `delete`)
+	applyMethodOverride(req, allowed)
+	if http.MethodDelete != req.Method {
+		t.Errorf(`header override method = %q, want DELETE`, req.Method)
+	}
+	if "" != req.Header.Get(`X-HTTP-Method-Override`) {
+		t.Error(`override header wasn't removed`)
+	}
+
+	// methods outside the allow-list are ignored:
+	req = httptest.NewRequest(http.MethodPost, `http://example.org/`, nil)
+	req.Header.Set(`X-HTTP-Method-Override`, `PATCH`)
+	applyMethodOverride(req, allowed)
+	if http.MethodPost != req.Method {
+		t.Errorf(`disallowed override method = %q, want POST`, req.Method)
+	}
+
+	// no allow-list means the translation stays off:
+	req = httptest.NewRequest(http.MethodPost, `http://example.org/`, nil)
+	req.Header.Set(`X-HTTP-Method-Override`, `PUT`)
+	applyMethodOverride(req, nil)
+	if http.MethodPost != req.Method {
+		t.Errorf(`opt-out override method = %q, want POST`, req.Method)
+	}
+
+	// the `_method` form field works too, keeping the body intact:
+	body := `name=value&_method=PUT`
+	req = httptest.NewRequest(http.MethodPost, `http://example.org/`,
+		strings.NewReader(body))
+	req.Header.Set(`Content-Type`, `application/x-www-form-urlencoded`)
+	applyMethodOverride(req, allowed)
+	if http.MethodPut != req.Method {
+		t.Errorf(`form override method = %q, want PUT`, req.Method)
+	}
+	replay, _ := io.ReadAll(req.Body)
+	if body != string(replay) {
+		t.Errorf(`replayed body = %q, want %q`, replay, body)
+	}
+} // TestApplyMethodOverride()
+
+/* _EoF_ */
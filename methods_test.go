@@ -0,0 +1,78 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAllowsMethod(t *testing.T) {
+	host := &THostConfig{}
+	if !host.allowsMethod(http.MethodDelete) {
+		t.Error(`allowsMethod() with an empty list refused a method`)
+	}
+
+	host.Methods = []string{`get`, `HEAD`}
+	if !host.allowsMethod(http.MethodGet) || !host.allowsMethod(http.MethodHead) {
+		t.Error(`allowsMethod() refused a listed method`)
+	}
+	if host.allowsMethod(http.MethodPost) {
+		t.Error(`allowsMethod() accepted an unlisted method`)
+	}
+} // TestAllowsMethod()
+
+func TestMethodRestrictions(t *testing.T) {
+	var hits atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			hits.Add(1)
+			_, _ = aWriter.Write([]byte(`backend answer`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target:  backend.URL,
+		Methods: []string{`GET`, `HEAD`},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	call := func(aMethod string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(aMethod, `http://example.org/`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := call(http.MethodGet); http.StatusOK != rec.Code {
+		t.Errorf(`GET status = %d, want 200`, rec.Code)
+	}
+	if rec := call(http.MethodHead); http.StatusOK != rec.Code {
+		t.Errorf(`HEAD status = %d, want 200`, rec.Code)
+	}
+
+	// a disallowed method is answered locally with 405 and the
+	// allowed set, and must never reach the backend:
+	before := hits.Load()
+	rec := call(http.MethodDelete)
+	if http.StatusMethodNotAllowed != rec.Code {
+		t.Errorf(`DELETE status = %d, want 405`, rec.Code)
+	}
+	if `GET, HEAD` != rec.Header().Get(`Allow`) {
+		t.Errorf(`Allow = %q, want "GET, HEAD"`, rec.Header().Get(`Allow`))
+	}
+	if before != hits.Load() {
+		t.Error(`disallowed request reached the backend`)
+	}
+} // TestMethodRestrictions()
+
+/* _EoF_ */
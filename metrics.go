@@ -0,0 +1,170 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// `tHostMetrics` aggregates the live counters of one virtual host.
+	tHostMetrics struct {
+		Requests   int64 // total requests handled
+		Errors     int64 // responses with status >= 500
+		Active     int64 // currently in-flight requests
+		BytesIn    int64 // request body bytes received
+		BytesOut   int64 // response bytes sent
+		LastMinute int64 // requests within the current minute
+		prevMinute int64
+		minuteMark int64 // unix minute of `LastMinute`
+	}
+
+	// `tRecentError` is one entry of the recent-errors ring buffer.
+	tRecentError struct {
+		When    time.Time
+		Host    string
+		Message string
+	}
+
+	// `TMetrics` collects the runtime counters shown on the status
+	// page: per-host request rates, in-flight requests, and the
+	// most recent errors.
+	TMetrics struct {
+		mtx     sync.RWMutex
+		started time.Time
+		hosts   map[string]*tHostMetrics
+		recent  []tRecentError // ring buffer, newest last
+	}
+)
+
+// `maxRecentErrors` is the size of the recent-errors ring buffer.
+const maxRecentErrors = 24
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newMetrics()` creates an empty metrics collector.
+func newMetrics() *TMetrics {
+	return &TMetrics{
+		started: time.Now(),
+		hosts:   make(map[string]*tHostMetrics),
+	}
+} // newMetrics()
+
+// --------------------------------------------------------------------------
+// TMetrics methods:
+
+// `ActiveTotal()` returns the number of currently in-flight requests
+// over all virtual hosts, including hijacked (WebSocket) connections
+// still being copied.
+func (m *TMetrics) ActiveTotal() int64 {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	var active int64
+	for _, host := range m.hosts {
+		active += host.Active
+	}
+
+	return active
+} // ActiveTotal()
+
+// `Uptime()` returns the time elapsed since the collector (i.e. the
+// proxy) was started.
+func (m *TMetrics) Uptime() time.Duration {
+	return time.Since(m.started).Round(time.Second)
+} // Uptime()
+
+// `countError()` stores `aMessage` in the recent-errors buffer.
+func (m *TMetrics) countError(aHostname, aMessage string) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.recent = append(m.recent, tRecentError{
+		When: time.Now(), Host: aHostname, Message: aMessage})
+	if maxRecentErrors < len(m.recent) {
+		m.recent = m.recent[1:]
+	}
+} // countError()
+
+// `countRequest()` accounts one finished request of `aHostname`.
+func (m *TMetrics) countRequest(aHostname string, aStatus int) {
+	minute := time.Now().Unix() / 60
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	host := m.host(aHostname)
+	host.Requests++
+	if minute != host.minuteMark {
+		host.prevMinute, host.LastMinute = host.LastMinute, 0
+		if minute != host.minuteMark+1 {
+			host.prevMinute = 0
+		}
+		host.minuteMark = minute
+	}
+	host.LastMinute++
+	if 500 <= aStatus {
+		host.Errors++
+	}
+} // countRequest()
+
+// `countTransfer()` adds the transferred byte counts of one request
+// to `aHostname`'s traffic accounting.
+func (m *TMetrics) countTransfer(aHostname string, aIn, aOut int64) {
+	m.mtx.Lock()
+	host := m.host(aHostname)
+	host.BytesIn += aIn
+	host.BytesOut += aOut
+	m.mtx.Unlock()
+} // countTransfer()
+
+// `host()` returns the counters of `aHostname`, creating them on
+// first use; the caller must hold `m.mtx`.
+func (m *TMetrics) host(aHostname string) *tHostMetrics {
+	host, ok := m.hosts[aHostname]
+	if !ok {
+		host = &tHostMetrics{}
+		m.hosts[aHostname] = host
+	}
+
+	return host
+} // host()
+
+// `requestDone()` decrements the in-flight counter of `aHostname`.
+func (m *TMetrics) requestDone(aHostname string) {
+	m.mtx.Lock()
+	m.host(aHostname).Active--
+	m.mtx.Unlock()
+} // requestDone()
+
+// `requestStart()` increments the in-flight counter of `aHostname`.
+func (m *TMetrics) requestStart(aHostname string) {
+	m.mtx.Lock()
+	m.host(aHostname).Active++
+	m.mtx.Unlock()
+} // requestStart()
+
+// `snapshot()` returns a copy of all per-host counters and the
+// recent errors for the status page.
+func (m *TMetrics) snapshot() (map[string]tHostMetrics, []tRecentError) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+
+	hosts := make(map[string]tHostMetrics, len(m.hosts))
+	for hostname, host := range m.hosts {
+		hosts[hostname] = *host
+	}
+	recent := make([]tRecentError, len(m.recent))
+	copy(recent, m.recent)
+
+	return hosts, recent
+} // snapshot()
+
+/* _EoF_ */
@@ -0,0 +1,74 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `normalizeRequestPath()` canonicalizes the URL path of `aRequest`
+// before route matching and forwarding: duplicate slashes and `.`/`..`
+// segments are collapsed in place, so every backend sees the same
+// canonical form regardless of how the client spelled the path.
+//
+// It reports whether the request may proceed; obviously malicious
+// paths - embedded NUL or other control characters, or `..` sequences
+// trying to climb above the root - are rejected and should be
+// answered with `400`.
+//
+// Parameters:
+//   - `aRequest`: The incoming HTTP request to canonicalize.
+//
+// Returns:
+//   - `bool`: Whether the request's path is acceptable.
+func normalizeRequestPath(aRequest *http.Request) bool {
+	decoded := aRequest.URL.Path
+	for _, char := range decoded {
+		if (0x20 > char) || (0x7f == char) {
+			// NUL bytes and CR/LF enable truncation and header
+			// splitting attacks at sloppy backends:
+			return false
+		}
+	}
+
+	// a `..` sequence escaping the root is an attack, not a typo:
+	depth := 0
+	for _, segment := range strings.Split(decoded, `/`) {
+		switch segment {
+		case ``, `.`:
+			// ignored by `path.Clean()` anyway
+
+		case `..`:
+			if depth--; 0 > depth {
+				return false
+			}
+
+		default:
+			depth++
+		}
+	}
+
+	clean := path.Clean(`/` + decoded)
+	if (`/` != clean) && strings.HasSuffix(decoded, `/`) {
+		clean += `/` // preserve directory-style requests
+	}
+	if clean != decoded {
+		aRequest.URL.Path = clean
+		aRequest.URL.RawPath = `` // recompute the escaped form
+	}
+
+	return true
+} // normalizeRequestPath()
+
+/* _EoF_ */
@@ -0,0 +1,44 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeRequestPath(t *testing.T) {
+	for _, tc := range []struct {
+		path, want string
+		ok         bool
+	}{
+		{`/page`, `/page`, true},
+		{`//a///b`, `/a/b`, true},
+		{`/a/./b`, `/a/b`, true},
+		{`/a/b/../c`, `/a/c`, true},
+		{`/a/b/`, `/a/b/`, true},
+		{`/../etc/passwd`, ``, false},
+		{`/a/../../etc`, ``, false},
+		{"/a\x00b", ``, false},
+		{"/a\r\nHeader: x", ``, false},
+	} {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		req.URL.Path = tc.path
+		if ok := normalizeRequestPath(req); tc.ok != ok {
+			t.Errorf(`normalizeRequestPath(%q) = %v, want %v`,
+				tc.path, ok, tc.ok)
+		} else if ok && (tc.want != req.URL.Path) {
+			t.Errorf(`normalizeRequestPath(%q) path = %q, want %q`,
+				tc.path, req.URL.Path, tc.want)
+		}
+	}
+} // TestNormalizeRequestPath()
+
+/* _EoF_ */
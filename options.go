@@ -0,0 +1,126 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+)
+
+type (
+	// `TOption` configures a proxy handler created by
+	// `NewProxyHandler()`.
+	TOption func(*TProxyHandler)
+)
+
+// --------------------------------------------------------------------------
+// option functions:
+
+// `WithConfig()` applies a complete proxy configuration, replacing
+// any routes added so far.
+//
+// Parameters:
+//   - `aConfig`: The proxy configuration to use.
+func WithConfig(aConfig *TConfig) TOption {
+	return func(ph *TProxyHandler) {
+		ph.conf = aConfig
+		// hostname keys and aliases are matched in their normalized
+		// (lowercase, punycode) form - just as `LoadConfig()` does:
+		hosts := make(map[string]*THostConfig, len(aConfig.Hosts))
+		for hostname, host := range aConfig.Hosts {
+			if nil == host {
+				continue
+			}
+			for idx, alias := range host.Aliases {
+				host.Aliases[idx] = normalizeHostname(alias)
+			}
+			hosts[normalizeHostname(hostname)] = host
+		}
+		ph.conf.Hosts = hosts
+		if nil != aConfig.Alerts {
+			ph.alerts = newAlertMonitor(aConfig.Alerts, ph.logger)
+		}
+		if (nil != aConfig.Transport) && (nil == ph.transport) {
+			ph.transport = newUpstreamTransport(aConfig.Transport)
+		}
+		ph.trusted = parseTrustedNets(aConfig.TrustedProxies)
+		if nil != aConfig.Shedding {
+			ph.shedder = newLoadShedder(aConfig.Shedding, ph.metrics)
+		}
+		if nil != aConfig.SLO {
+			ph.slo = newSLOTracker(aConfig.SLO)
+		}
+		if nil != aConfig.Limits {
+			ph.guard = newRequestGuard(aConfig.Limits)
+		}
+	}
+} // WithConfig()
+
+// `WithLogger()` sets the access/error logger used by the handler.
+//
+// Parameters:
+//   - `aLogger`: The logger instance to use.
+func WithLogger(aLogger *TLogger) TOption {
+	return func(ph *TProxyHandler) {
+		ph.logger = aLogger
+		if nil != ph.alerts {
+			ph.alerts.logger = aLogger
+		}
+	}
+} // WithLogger()
+
+// `WithRoutes()` adds a simple hostname to backend URL mapping on
+// top of the current configuration.
+//
+// Parameters:
+//   - `aRoutes`: Hostname to backend URL pairs to add.
+func WithRoutes(aRoutes map[string]string) TOption {
+	return func(ph *TProxyHandler) {
+		for hostname, target := range aRoutes {
+			// request-time lookups use the normalized (lowercase,
+			// punycode) hostname, so the key must match that form:
+			ph.conf.Hosts[normalizeHostname(hostname)] =
+				&THostConfig{Target: target}
+		}
+	}
+} // WithRoutes()
+
+// `WithEventLogger()` sets the leveled logger receiving the proxy's
+// operational events (backend failures, bans, maintenance switches).
+//
+// Parameters:
+//   - `aEvents`: The event logger implementation to use.
+func WithEventLogger(aEvents TEventLogger) TOption {
+	return func(ph *TProxyHandler) {
+		ph.events = aEvents
+	}
+} // WithEventLogger()
+
+// `WithStateStore()` sets the persistence backend keeping dynamic
+// state (e.g. the auto-ban list) across restarts.
+//
+// Parameters:
+//   - `aStore`: The state store implementation to use.
+func WithStateStore(aStore TStateStore) TOption {
+	return func(ph *TProxyHandler) {
+		ph.store = aStore
+	}
+} // WithStateStore()
+
+// `WithTransport()` sets the `http.RoundTripper` used for all
+// upstream connections (e.g. for custom TLS or dialing behaviour).
+//
+// Parameters:
+//   - `aTransport`: The transport handed to the reverse proxies.
+func WithTransport(aTransport http.RoundTripper) TOption {
+	return func(ph *TProxyHandler) {
+		ph.transport = aTransport
+	}
+} // WithTransport()
+
+/* _EoF_ */
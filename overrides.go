@@ -0,0 +1,103 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type (
+	// `TOverrideConfig` is one special-path response (e.g.
+	// `/robots.txt` or `/.well-known/security.txt`) served by the
+	// proxy itself, so such well-known files exist even when the
+	// backend doesn't provide them.
+	//
+	// Either `Content` holds the response body inline, or `File`
+	// names a local file to serve; `File` wins if both are set.
+	TOverrideConfig struct {
+		// Content is the inline response body.
+		Content string `json:"content,omitempty"`
+
+		// File is a local file served as the response body.
+		File string `json:"file,omitempty"`
+
+		// ContentType overrides the `Content-Type` of inline
+		// content (default: `text/plain; charset=utf-8`).
+		ContentType string `json:"contentType,omitempty"`
+	}
+)
+
+// --------------------------------------------------------------------------
+// TOverrideConfig methods:
+
+// `serve()` answers `aRequest` with the override's configured
+// response.
+//
+// Parameters:
+//   - `aWriter`: Used to send the response to the client.
+//   - `aRequest`: The incoming HTTP request.
+func (oc *TOverrideConfig) serve(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if (http.MethodGet != aRequest.Method) &&
+		(http.MethodHead != aRequest.Method) {
+		aWriter.Header().Set(`Allow`, `GET, HEAD`)
+		http.Error(aWriter, `method not allowed`,
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	if "" != oc.File {
+		// `ServeFile` handles conditional and Range requests:
+		http.ServeFile(aWriter, aRequest, oc.File)
+		return
+	}
+
+	ctype := oc.ContentType
+	if "" == ctype {
+		ctype = `text/plain; charset=utf-8`
+	}
+	aWriter.Header().Set(`Content-Type`, ctype)
+	aWriter.Header().Set(`Content-Length`, strconv.Itoa(len(oc.Content)))
+	if http.MethodHead == aRequest.Method {
+		return
+	}
+	_, _ = io.WriteString(aWriter, oc.Content)
+} // serve()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `overrideTrie()` builds the longest-prefix matcher of all
+// override paths ending in a slash (prefix entries); exact paths
+// stay in the plain map checked first.
+//
+// Parameters:
+//   - `aOverrides`: The host's configured overrides.
+//
+// Returns:
+//   - `*tPathTrie[*TOverrideConfig]`: The matcher (nil if the host
+//     has no prefix overrides).
+func overrideTrie(aOverrides map[string]*TOverrideConfig) *tPathTrie[*TOverrideConfig] {
+	var trie *tPathTrie[*TOverrideConfig]
+	for path, override := range aOverrides {
+		if (nil == override) || !strings.HasSuffix(path, `/`) {
+			continue
+		}
+		if nil == trie {
+			trie = newPathTrie[*TOverrideConfig]()
+		}
+		trie.set(path, override)
+	}
+
+	return trie
+} // overrideTrie()
+
+/* _EoF_ */
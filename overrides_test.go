@@ -0,0 +1,56 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostOverrides(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`backend answer`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: backend.URL,
+		Overrides: map[string]*TOverrideConfig{
+			`/robots.txt`: {Content: "User-agent: *\nDisallow: /\n"},
+		},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet,
+		`http://example.org/robots.txt`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if "User-agent: *\nDisallow: /\n" != rec.Body.String() {
+		t.Errorf(`override body = %q`, rec.Body.String())
+	}
+	if `text/plain; charset=utf-8` != rec.Header().Get(`Content-Type`) {
+		t.Errorf(`override Content-Type = %q`,
+			rec.Header().Get(`Content-Type`))
+	}
+
+	// everything else still reaches the backend:
+	req = httptest.NewRequest(http.MethodGet,
+		`http://example.org/page`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if `backend answer` != rec.Body.String() {
+		t.Errorf(`proxied body = %q`, rec.Body.String())
+	}
+} // TestHostOverrides()
+
+/* _EoF_ */
@@ -0,0 +1,99 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strings"
+)
+
+// `tPathTrie` is a radix tree over URL path segments, resolving a
+// request path to the value stored under its longest registered
+// prefix in O(path length) - independent of how many prefixes are
+// registered, unlike a linear scan over all of them.
+//
+// The tree is built once at route-construction time and read-only
+// afterwards, so lookups need no locking.
+type tPathTrie[V any] struct {
+	value    V
+	hasValue bool
+	children map[string]*tPathTrie[V] // path segment -> subtree
+}
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newPathTrie()` creates an empty path prefix tree.
+func newPathTrie[V any]() *tPathTrie[V] {
+	return &tPathTrie[V]{}
+} // newPathTrie()
+
+// --------------------------------------------------------------------------
+// tPathTrie methods:
+
+// `match()` returns the value stored under the longest registered
+// prefix of `aPath`.
+//
+// Parameters:
+//   - `aPath`: The request path to resolve.
+//
+// Returns:
+//   - `V`: The best matching value (zero value if none).
+//   - `bool`: Whether any registered prefix matched.
+func (pt *tPathTrie[V]) match(aPath string) (V, bool) {
+	node := pt
+	value, found := node.value, node.hasValue
+	for path := strings.Trim(aPath, `/`); "" != path; {
+		segment := path
+		if idx := strings.IndexByte(path, '/'); 0 <= idx {
+			segment, path = path[:idx], path[idx+1:]
+		} else {
+			path = ``
+		}
+		child, ok := node.children[segment]
+		if !ok {
+			break
+		}
+		node = child
+		if node.hasValue {
+			value, found = node.value, true
+		}
+	}
+
+	return value, found
+} // match()
+
+// `set()` stores `aValue` under the path prefix `aPrefix`; a later
+// `set()` of the same prefix replaces the value.
+//
+// Parameters:
+//   - `aPrefix`: The path prefix to register.
+//   - `aValue`: The value resolved for paths below that prefix.
+func (pt *tPathTrie[V]) set(aPrefix string, aValue V) {
+	node := pt
+	for path := strings.Trim(aPrefix, `/`); "" != path; {
+		segment := path
+		if idx := strings.IndexByte(path, '/'); 0 <= idx {
+			segment, path = path[:idx], path[idx+1:]
+		} else {
+			path = ``
+		}
+		child, ok := node.children[segment]
+		if !ok {
+			if nil == node.children {
+				node.children = make(map[string]*tPathTrie[V])
+			}
+			child = &tPathTrie[V]{}
+			node.children[segment] = child
+		}
+		node = child
+	}
+	node.value, node.hasValue = aValue, true
+} // set()
+
+/* _EoF_ */
@@ -0,0 +1,123 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPathTrie(t *testing.T) {
+	trie := newPathTrie[string]()
+	trie.set(`/api/`, `api`)
+	trie.set(`/api/v2/`, `v2`)
+	trie.set(`/static/`, `static`)
+
+	cases := []struct {
+		path, want string
+		found      bool
+	}{
+		{`/api/users`, `api`, true},
+		{`/api/v2/users`, `v2`, true},
+		{`/api/v2`, `v2`, true},
+		{`/api`, `api`, true},
+		{`/static/css/site.css`, `static`, true},
+		{`/apiv2/users`, ``, false},
+		{`/`, ``, false},
+		{`/other`, ``, false},
+	}
+	for _, tc := range cases {
+		value, found := trie.match(tc.path)
+		if (found != tc.found) || (value != tc.want) {
+			t.Errorf(`match(%q) = (%q, %v), want (%q, %v)`,
+				tc.path, value, found, tc.want, tc.found)
+		}
+	}
+
+	// a root prefix catches everything:
+	trie.set(`/`, `root`)
+	if value, found := trie.match(`/other`); !found || (`root` != value) {
+		t.Errorf(`match(/other) = (%q, %v), want (root, true)`, value, found)
+	}
+	// ... but longer prefixes still win:
+	if value, _ := trie.match(`/api/v2/users`); `v2` != value {
+		t.Errorf(`match(/api/v2/users) = %q, want v2`, value)
+	}
+} // TestPathTrie()
+
+func TestOverrideTrie(t *testing.T) {
+	overrides := map[string]*TOverrideConfig{
+		`/robots.txt`: {Content: `exact`}, // exact: not in the trie
+		`/docs/`:      {Content: `docs`},
+		`/broken/`:    nil, // nil entries are skipped
+	}
+	trie := overrideTrie(overrides)
+	if nil == trie {
+		t.Fatal(`overrideTrie() = nil, want matcher`)
+	}
+	if override, ok := trie.match(`/docs/intro.html`); !ok ||
+		(`docs` != override.Content) {
+		t.Errorf(`match(/docs/intro.html) = (%+v, %v)`, override, ok)
+	}
+	if _, ok := trie.match(`/robots.txt`); ok {
+		t.Error(`exact override path matched as prefix`)
+	}
+
+	if trie := overrideTrie(nil); nil != trie {
+		t.Error(`overrideTrie(nil) built a matcher`)
+	}
+} // TestOverrideTrie()
+
+// the structural payoff: resolving against 10k+ registered prefixes
+// costs the same as against a handful, while a linear scan doesn't.
+const benchPrefixes = 10_240
+
+func benchPrefixList() []string {
+	prefixes := make([]string, benchPrefixes)
+	for idx := 0; benchPrefixes > idx; idx++ {
+		prefixes[idx] = `/tenant` + strconv.Itoa(idx) + `/api/`
+	}
+
+	return prefixes
+} // benchPrefixList()
+
+func BenchmarkPathTrieMatch(b *testing.B) {
+	trie := newPathTrie[int]()
+	for idx, prefix := range benchPrefixList() {
+		trie.set(prefix, idx)
+	}
+	path := `/tenant` + strconv.Itoa(benchPrefixes-1) + `/api/users/42`
+	b.ResetTimer()
+	for idx := 0; b.N > idx; idx++ {
+		if _, ok := trie.match(path); !ok {
+			b.Fatal(`no match`)
+		}
+	}
+} // BenchmarkPathTrieMatch()
+
+func BenchmarkLinearPrefixScan(b *testing.B) {
+	prefixes := benchPrefixList()
+	path := `/tenant` + strconv.Itoa(benchPrefixes-1) + `/api/users/42`
+	b.ResetTimer()
+	for idx := 0; b.N > idx; idx++ {
+		best := -1
+		for pos, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) &&
+				((0 > best) || (len(prefixes[best]) < len(prefix))) {
+				best = pos
+			}
+		}
+		if 0 > best {
+			b.Fatal(`no match`)
+		}
+	}
+} // BenchmarkLinearPrefixScan()
+
+/* _EoF_ */
@@ -0,0 +1,180 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+type (
+	// `TStateStore` is the pluggable persistence backend for dynamic
+	// proxy state - auto-bans, rate-limit penalties, sticky-session
+	// mappings, ACME account data - so it survives restarts.
+	//
+	// State is organised in named buckets of key/value entries; a
+	// bucket is always loaded and saved as a whole, which keeps the
+	// interface implementable by about anything from a flat file to
+	// an external database.
+	TStateStore interface {
+		// `Load()` returns all entries of `aBucket` (an empty map
+		// for an unknown bucket).
+		Load(aBucket string) (map[string][]byte, error)
+
+		// `Save()` replaces the entries of `aBucket`.
+		Save(aBucket string, aEntries map[string][]byte) error
+
+		// `Close()` flushes and releases the backend.
+		Close() error
+	}
+
+	// `TMemStore` is the in-memory default store: fully functional
+	// but - by design - losing everything when the process ends.
+	TMemStore struct {
+		mtx     sync.RWMutex
+		buckets map[string]map[string][]byte
+	}
+
+	// `TFileStore` persists each bucket as a JSON file below a
+	// state directory, the dependency-free embedded alternative to
+	// an external database.
+	TFileStore struct {
+		mtx sync.Mutex
+		dir string
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor functions:
+
+// `NewFileStore()` creates a store writing each bucket to
+// `aDir/<bucket>.json`, creating the directory if needed.
+//
+// Parameters:
+//   - `aDir`: The state directory to use.
+//
+// Returns:
+//   - `*TFileStore`: The new store.
+//   - `error`: A directory creation problem (`nil` on success).
+func NewFileStore(aDir string) (*TFileStore, error) {
+	if err := os.MkdirAll(aDir, 0o700); nil != err {
+		return nil, err
+	}
+
+	return &TFileStore{dir: aDir}, nil
+} // NewFileStore()
+
+// `NewMemStore()` creates an empty in-memory store.
+func NewMemStore() *TMemStore {
+	return &TMemStore{buckets: make(map[string]map[string][]byte)}
+} // NewMemStore()
+
+// --------------------------------------------------------------------------
+// TMemStore methods:
+
+// `Close()` implements `TStateStore`; nothing to release.
+func (ms *TMemStore) Close() error {
+	return nil
+} // Close()
+
+// `Load()` returns a copy of all entries of `aBucket`.
+func (ms *TMemStore) Load(aBucket string) (map[string][]byte, error) {
+	ms.mtx.RLock()
+	defer ms.mtx.RUnlock()
+
+	entries := make(map[string][]byte, len(ms.buckets[aBucket]))
+	for key, value := range ms.buckets[aBucket] {
+		entries[key] = value
+	}
+
+	return entries, nil
+} // Load()
+
+// `Save()` replaces the entries of `aBucket` with a copy of
+// `aEntries`.
+func (ms *TMemStore) Save(aBucket string, aEntries map[string][]byte) error {
+	entries := make(map[string][]byte, len(aEntries))
+	for key, value := range aEntries {
+		entries[key] = value
+	}
+
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+	ms.buckets[aBucket] = entries
+
+	return nil
+} // Save()
+
+// --------------------------------------------------------------------------
+// TFileStore methods:
+
+// `Close()` implements `TStateStore`; every `Save()` already synced
+// to disk, so nothing is left to flush.
+func (fs *TFileStore) Close() error {
+	return nil
+} // Close()
+
+// `Load()` reads the entries of `aBucket` from its JSON file.
+func (fs *TFileStore) Load(aBucket string) (map[string][]byte, error) {
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+
+	data, err := os.ReadFile(fs.bucketFile(aBucket))
+	if nil != err {
+		if os.IsNotExist(err) {
+			return make(map[string][]byte), nil
+		}
+		return nil, err
+	}
+	entries := make(map[string][]byte)
+	if err = json.Unmarshal(data, &entries); nil != err {
+		return nil, err
+	}
+
+	return entries, nil
+} // Load()
+
+// `Save()` writes the entries of `aBucket` to its JSON file,
+// replacing the previous contents atomically (write + rename).
+func (fs *TFileStore) Save(aBucket string, aEntries map[string][]byte) error {
+	data, err := json.MarshalIndent(aEntries, ``, "\t")
+	if nil != err {
+		return err
+	}
+
+	fs.mtx.Lock()
+	defer fs.mtx.Unlock()
+
+	filename := fs.bucketFile(aBucket)
+	tmpName := filename + `~`
+	if err = os.WriteFile(tmpName, data, 0o600); nil != err {
+		return err
+	}
+
+	return os.Rename(tmpName, filename)
+} // Save()
+
+// `bucketFile()` returns the filename storing `aBucket`, with path
+// separators in the bucket name neutralised.
+func (fs *TFileStore) bucketFile(aBucket string) string {
+	name := strings.Map(func(aChar rune) rune {
+		switch aChar {
+		case '/', '\\', ':':
+			return '_'
+		}
+		return aChar
+	}, aBucket)
+
+	return filepath.Join(fs.dir, name+`.json`)
+} // bucketFile()
+
+/* _EoF_ */
@@ -0,0 +1,82 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStateStores(t *testing.T) {
+	fileStore, err := NewFileStore(t.TempDir())
+	if nil != err {
+		t.Fatalf(`NewFileStore() failed: %v`, err)
+	}
+	for name, store := range map[string]TStateStore{
+		`mem`:  NewMemStore(),
+		`file`: fileStore,
+	} {
+		entries := map[string][]byte{
+			`192.0.2.1`: []byte(`{"count":3}`),
+			`192.0.2.2`: []byte(`{"count":7}`),
+		}
+		if err := store.Save(`bans`, entries); nil != err {
+			t.Fatalf(`%s: Save() failed: %v`, name, err)
+		}
+		loaded, err := store.Load(`bans`)
+		if nil != err {
+			t.Fatalf(`%s: Load() failed: %v`, name, err)
+		}
+		if len(entries) != len(loaded) {
+			t.Errorf(`%s: Load() returned %d entries, want %d`,
+				name, len(loaded), len(entries))
+		}
+		if `{"count":3}` != string(loaded[`192.0.2.1`]) {
+			t.Errorf(`%s: entry = %s`, name, loaded[`192.0.2.1`])
+		}
+
+		// an unknown bucket loads empty, not as an error:
+		if loaded, err = store.Load(`unknown`); (nil != err) || (0 != len(loaded)) {
+			t.Errorf(`%s: Load(unknown) = (%v, %v)`, name, loaded, err)
+		}
+		if err = store.Close(); nil != err {
+			t.Errorf(`%s: Close() failed: %v`, name, err)
+		}
+	}
+} // TestStateStores()
+
+func TestGuardPersistence(t *testing.T) {
+	store := NewMemStore()
+	guard := newRequestGuard(&TLimitsConfig{BanAfter: 1, BanMinutes: 10})
+	guard.violation(`192.0.2.7:1234`)
+	if !guard.banned(`192.0.2.7:5678`) {
+		t.Fatal(`violation didn't start a ban`)
+	}
+	// an expired ban must not be persisted:
+	guard.offenders[`192.0.2.8`] = &tOffender{
+		count: 5, until: time.Now().Add(-time.Minute)}
+
+	if err := guard.saveTo(store); nil != err {
+		t.Fatalf(`saveTo() failed: %v`, err)
+	}
+
+	// a fresh guard (fresh process) restores the active ban:
+	restored := newRequestGuard(&TLimitsConfig{BanAfter: 1, BanMinutes: 10})
+	if err := restored.loadFrom(store); nil != err {
+		t.Fatalf(`loadFrom() failed: %v`, err)
+	}
+	if !restored.banned(`192.0.2.7:9999`) {
+		t.Error(`restored guard lost the active ban`)
+	}
+	if restored.banned(`192.0.2.8:1111`) {
+		t.Error(`restored guard revived an expired ban`)
+	}
+} // TestGuardPersistence()
+
+/* _EoF_ */
@@ -0,0 +1,131 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// `tProxyProtoListener` unwraps the HAProxy PROXY protocol (v1)
+	// header sent by an upstream load balancer, so the real client
+	// address shows up in logs and `X-Forwarded-For` handling.
+	tProxyProtoListener struct {
+		net.Listener
+	}
+
+	// `tProxyProtoConn` is a connection whose remote address was
+	// taken from the PROXY protocol header.
+	tProxyProtoConn struct {
+		net.Conn
+		remote net.Addr
+	}
+)
+
+// `proxyHeaderTimeout` limits how long a client may take to send
+// the PROXY protocol header.
+const proxyHeaderTimeout = 3 * time.Second
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewProxyProtoListener()` wraps `aListener` so every accepted
+// connection must start with a PROXY protocol v1 header whose
+// source address replaces the connection's remote address.
+//
+// Parameters:
+//   - `aListener`: The raw listener to wrap.
+//
+// Returns:
+//   - `net.Listener`: The unwrapping listener.
+func NewProxyProtoListener(aListener net.Listener) net.Listener {
+	return &tProxyProtoListener{Listener: aListener}
+} // NewProxyProtoListener()
+
+// --------------------------------------------------------------------------
+// tProxyProtoListener methods:
+
+// `Accept()` waits for the next connection and consumes its PROXY
+// protocol header; connections with a broken header are dropped.
+func (pl *tProxyProtoListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := pl.Listener.Accept()
+		if nil != err {
+			return nil, err
+		}
+
+		remote, err := readProxyHeader(conn)
+		if nil != err {
+			_ = conn.Close()
+			continue
+		}
+		if nil == remote {
+			remote = conn.RemoteAddr()
+		}
+
+		return &tProxyProtoConn{Conn: conn, remote: remote}, nil
+	}
+} // Accept()
+
+// `RemoteAddr()` returns the client address from the PROXY header.
+func (pc *tProxyProtoConn) RemoteAddr() net.Addr {
+	return pc.remote
+} // RemoteAddr()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `readProxyHeader()` consumes the PROXY v1 line from `aConn`
+// returning the advertised source address (nil for `UNKNOWN`).
+func readProxyHeader(aConn net.Conn) (net.Addr, error) {
+	_ = aConn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout))
+	defer func() { _ = aConn.SetReadDeadline(time.Time{}) }()
+
+	// read byte-wise so we never consume payload data; the v1
+	// header is at most 107 bytes:
+	var line strings.Builder
+	buf := make([]byte, 1)
+	for 108 > line.Len() {
+		if _, err := aConn.Read(buf); nil != err {
+			return nil, err
+		}
+		line.WriteByte(buf[0])
+		if '\n' == buf[0] {
+			break
+		}
+	}
+	header := strings.TrimSuffix(line.String(), "\r\n")
+	if header == line.String() {
+		return nil, fmt.Errorf("unterminated PROXY header")
+	}
+
+	fields := strings.Split(header, ` `)
+	if (2 > len(fields)) || (`PROXY` != fields[0]) {
+		return nil, fmt.Errorf("invalid PROXY header %q", header)
+	}
+	if `UNKNOWN` == fields[1] {
+		return nil, nil
+	}
+	if 6 != len(fields) {
+		return nil, fmt.Errorf("invalid PROXY header %q", header)
+	}
+	srcIP := net.ParseIP(fields[2])
+	srcPort, err := strconv.Atoi(fields[4])
+	if (nil == srcIP) || (nil != err) {
+		return nil, fmt.Errorf("invalid PROXY source %q", header)
+	}
+
+	return &net.TCPAddr{IP: srcIP, Port: srcPort}, nil
+} // readProxyHeader()
+
+/* _EoF_ */
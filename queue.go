@@ -0,0 +1,87 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// `tBackendQueue` caps the concurrent requests of one backend
+	// and briefly queues the overflow, smoothing short bursts
+	// instead of failing them immediately.
+	//
+	// Both the queue depth and the waiting time are bounded, so a
+	// stalled backend can't accumulate goroutines without limit.
+	tBackendQueue struct {
+		slots   chan struct{} // semaphore of size MaxConcurrent
+		waiters atomic.Int32
+		depth   int32
+		wait    time.Duration
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newBackendQueue()` creates a queue admitting `aConcurrent`
+// parallel requests, queueing up to `aDepth` more for at most
+// `aWait`.
+func newBackendQueue(aConcurrent, aDepth int, aWait time.Duration) *tBackendQueue {
+	if 0 >= aWait {
+		aWait = 5 * time.Second
+	}
+
+	return &tBackendQueue{
+		slots: make(chan struct{}, aConcurrent),
+		depth: int32(aDepth),
+		wait:  aWait,
+	}
+} // newBackendQueue()
+
+// --------------------------------------------------------------------------
+// tBackendQueue methods:
+
+// `acquire()` claims a concurrency slot, queueing briefly when all
+// are taken; it reports whether the request may proceed (and then
+// must `release()` later).
+func (bq *tBackendQueue) acquire(aCtx context.Context) bool {
+	select {
+	case bq.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	// all slots taken - queue if there's still room:
+	if bq.depth < bq.waiters.Add(1) {
+		bq.waiters.Add(-1)
+		return false
+	}
+	defer bq.waiters.Add(-1)
+
+	timer := time.NewTimer(bq.wait)
+	defer timer.Stop()
+	select {
+	case bq.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-aCtx.Done():
+		return false
+	}
+} // acquire()
+
+// `release()` returns a claimed concurrency slot.
+func (bq *tBackendQueue) release() {
+	<-bq.slots
+} // release()
+
+/* _EoF_ */
@@ -0,0 +1,297 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// This file implements the minimal Redis (RESP) client used for
+// distributed rate limiting: with several reprox replicas behind
+// DNS round-robin the violation counters and bans live in Redis so
+// an offender can't use each replica's full allowance.  Like the
+// Consul and etcd watchers this speaks the wire protocol directly
+// instead of pulling in a client dependency - the four commands
+// needed (`INCR`, `EXPIRE`, `SET`, `EXISTS`) hardly warrant one.
+
+type (
+	// `TRedisConfig` describes the optional Redis backend shared by
+	// all replicas for rate limiting and bans.
+	TRedisConfig struct {
+		// Addr is the `host:port` of the Redis server.
+		Addr string `json:"addr"`
+
+		// Password is sent via `AUTH` when non-empty.
+		Password string `json:"password,omitempty"`
+
+		// DB is the logical database selected after connecting.
+		DB int `json:"db,omitempty"`
+
+		// KeyPrefix namespaces all keys (default `reprox:`).
+		KeyPrefix string `json:"keyPrefix,omitempty"`
+
+		// TimeoutSec is the per-command timeout (default 2).
+		TimeoutSec int `json:"timeoutSec,omitempty"`
+	}
+
+	// `tRedisClient` is a tiny single-connection RESP client; a
+	// failed command closes the connection and the next command
+	// redials.
+	tRedisClient struct {
+		mtx     sync.Mutex
+		conf    *TRedisConfig
+		conn    net.Conn
+		reader  *bufio.Reader
+		prefix  string
+		timeout time.Duration
+
+		// after a connection problem Redis is bypassed until this
+		// time, so an outage degrades to local-only limiting
+		// instead of stalling every request:
+		failUntil time.Time
+	}
+)
+
+// `redisRetryPeriod` is how long Redis is bypassed after a failure.
+const redisRetryPeriod = 15 * time.Second
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRedisClient()` creates a client for `aConfig`, filling in the
+// usual defaults; the connection is established lazily.
+func newRedisClient(aConfig *TRedisConfig) *tRedisClient {
+	timeout := time.Duration(aConfig.TimeoutSec) * time.Second
+	if 0 >= timeout {
+		timeout = 2 * time.Second
+	}
+	prefix := aConfig.KeyPrefix
+	if "" == prefix {
+		prefix = `reprox:`
+	}
+
+	return &tRedisClient{
+		conf:    aConfig,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+} // newRedisClient()
+
+// --------------------------------------------------------------------------
+// tRedisClient methods:
+
+// `ban()` marks `aIP` as banned for `aFor` in the shared store.
+func (rc *tRedisClient) ban(aIP string, aFor time.Duration) error {
+	return rc.set(`ban:`+aIP, `1`, aFor)
+} // ban()
+
+// `banned()` reports whether `aIP` is banned in the shared store.
+func (rc *tRedisClient) banned(aIP string) (bool, error) {
+	reply, err := rc.do(`EXISTS`, rc.prefix+`ban:`+aIP)
+	if nil != err {
+		return false, err
+	}
+	count, _ := reply.(int64)
+
+	return 0 < count, nil
+} // banned()
+
+// `close()` drops the connection; the caller must hold `rc.mtx`.
+func (rc *tRedisClient) close() {
+	if nil != rc.conn {
+		_ = rc.conn.Close()
+		rc.conn, rc.reader = nil, nil
+	}
+} // close()
+
+// `connect()` dials and authenticates; the caller must hold
+// `rc.mtx`.
+func (rc *tRedisClient) connect() error {
+	conn, err := net.DialTimeout(`tcp`, rc.conf.Addr, rc.timeout)
+	if nil != err {
+		return err
+	}
+	rc.conn, rc.reader = conn, bufio.NewReader(conn)
+
+	if "" != rc.conf.Password {
+		if _, err = rc.command(`AUTH`, rc.conf.Password); nil != err {
+			rc.close()
+			return err
+		}
+	}
+	if 0 != rc.conf.DB {
+		if _, err = rc.command(`SELECT`,
+			strconv.Itoa(rc.conf.DB)); nil != err {
+			rc.close()
+			return err
+		}
+	}
+
+	return nil
+} // connect()
+
+// `command()` sends one command and reads its reply; the caller
+// must hold `rc.mtx` and have a live connection.
+func (rc *tRedisClient) command(aArgs ...string) (any, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "*%d\r\n", len(aArgs))
+	for _, arg := range aArgs {
+		fmt.Fprintf(&buf, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	deadline := time.Now().Add(rc.timeout)
+	_ = rc.conn.SetDeadline(deadline)
+	if _, err := rc.conn.Write(buf.Bytes()); nil != err {
+		return nil, err
+	}
+
+	return rc.readReply()
+} // command()
+
+// `do()` runs one command, (re-)connecting as needed; after a
+// failure Redis is bypassed for `redisRetryPeriod`.
+func (rc *tRedisClient) do(aArgs ...string) (any, error) {
+	rc.mtx.Lock()
+	defer rc.mtx.Unlock()
+
+	if time.Now().Before(rc.failUntil) {
+		return nil, errRedisDown
+	}
+	if nil == rc.conn {
+		if err := rc.connect(); nil != err {
+			rc.failUntil = time.Now().Add(redisRetryPeriod)
+			return nil, err
+		}
+	}
+	reply, err := rc.command(aArgs...)
+	if nil != err {
+		rc.close()
+		rc.failUntil = time.Now().Add(redisRetryPeriod)
+	}
+
+	return reply, err
+} // do()
+
+// `get()` returns the string value of `aKey`, reporting whether the
+// key exists.
+func (rc *tRedisClient) get(aKey string) (string, bool, error) {
+	reply, err := rc.do(`GET`, rc.prefix+aKey)
+	if nil != err {
+		return ``, false, err
+	}
+	value, ok := reply.(string)
+
+	return value, ok, nil
+} // get()
+
+// `incr()` atomically increments `aKey`, starting its expiry at
+// `aTTL` on first use, and returns the new count.
+func (rc *tRedisClient) incr(aKey string, aTTL time.Duration) (int64, error) {
+	reply, err := rc.do(`INCR`, rc.prefix+aKey)
+	if nil != err {
+		return 0, err
+	}
+	count, _ := reply.(int64)
+	if 1 == count {
+		_, _ = rc.do(`EXPIRE`, rc.prefix+aKey,
+			strconv.Itoa(int(aTTL.Seconds())))
+	}
+
+	return count, nil
+} // incr()
+
+// `readReply()` parses one RESP reply; the caller must hold
+// `rc.mtx`.
+func (rc *tRedisClient) readReply() (any, error) {
+	line, err := rc.reader.ReadString('\n')
+	if nil != err {
+		return nil, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if "" == line {
+		return nil, errRedisProtocol
+	}
+
+	switch line[0] {
+	case '+': // simple string
+		return line[1:], nil
+
+	case '-': // server-side error
+		return nil, errors.New(`redis: ` + line[1:])
+
+	case ':': // integer
+		return strconv.ParseInt(line[1:], 10, 64)
+
+	case '$': // bulk string
+		size, err := strconv.Atoi(line[1:])
+		if nil != err {
+			return nil, err
+		}
+		if 0 > size { // null bulk
+			return nil, nil
+		}
+		data := make([]byte, size+2) // payload + CRLF
+		if _, err = io.ReadFull(rc.reader, data); nil != err {
+			return nil, err
+		}
+		return string(data[:size]), nil
+
+	case '*': // array
+		count, err := strconv.Atoi(line[1:])
+		if nil != err {
+			return nil, err
+		}
+		if 0 > count { // null array
+			return nil, nil
+		}
+		items := make([]any, count)
+		for idx := range items {
+			if items[idx], err = rc.readReply(); nil != err {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+
+	return nil, errRedisProtocol
+} // readReply()
+
+// `set()` stores `aValue` under `aKey`, expiring after `aTTL` (no
+// expiry if zero).
+func (rc *tRedisClient) set(aKey, aValue string, aTTL time.Duration) error {
+	args := []string{`SET`, rc.prefix + aKey, aValue}
+	if 0 < aTTL {
+		args = append(args, `EX`, strconv.Itoa(int(aTTL.Seconds())))
+	}
+	_, err := rc.do(args...)
+
+	return err
+} // set()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+var (
+	// `errRedisDown` marks commands skipped during the post-failure
+	// bypass period.
+	errRedisDown = errors.New(`redis: backend bypassed after failure`)
+
+	// `errRedisProtocol` marks malformed server replies.
+	errRedisProtocol = errors.New(`redis: malformed reply`)
+)
+
+/* _EoF_ */
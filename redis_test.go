@@ -0,0 +1,176 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// `startFakeRedis()` serves just enough RESP for the client tests:
+// `INCR`, `EXPIRE`, `SET`, `GET`, and `EXISTS` over an in-memory map.
+func startFakeRedis(t *testing.T) string {
+	listener, err := net.Listen(`tcp`, `127.0.0.1:0`)
+	if nil != err {
+		t.Fatalf(`listen failed: %v`, err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	var mtx sync.Mutex
+	counters := make(map[string]int64)
+	values := make(map[string]string)
+
+	serveConn := func(aConn net.Conn) {
+		defer aConn.Close()
+		reader := bufio.NewReader(aConn)
+		for {
+			args, err := readFakeCommand(reader)
+			if nil != err {
+				return
+			}
+			mtx.Lock()
+			switch args[0] {
+			case `INCR`:
+				counters[args[1]]++
+				fmt.Fprintf(aConn, ":%d\r\n", counters[args[1]])
+			case `EXPIRE`:
+				fmt.Fprint(aConn, ":1\r\n")
+			case `SET`:
+				values[args[1]] = args[2]
+				fmt.Fprint(aConn, "+OK\r\n")
+			case `GET`:
+				if value, ok := values[args[1]]; ok {
+					fmt.Fprintf(aConn, "$%d\r\n%s\r\n", len(value), value)
+				} else {
+					fmt.Fprint(aConn, "$-1\r\n")
+				}
+			case `EXISTS`:
+				if _, ok := values[args[1]]; ok {
+					fmt.Fprint(aConn, ":1\r\n")
+				} else {
+					fmt.Fprint(aConn, ":0\r\n")
+				}
+			default:
+				fmt.Fprint(aConn, "-ERR unknown command\r\n")
+			}
+			mtx.Unlock()
+		}
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if nil != err {
+				return
+			}
+			go serveConn(conn)
+		}
+	}()
+
+	return listener.Addr().String()
+} // startFakeRedis()
+
+// `readFakeCommand()` parses one RESP command array.
+func readFakeCommand(aReader *bufio.Reader) ([]string, error) {
+	header, err := aReader.ReadString('\n')
+	if nil != err {
+		return nil, err
+	}
+	count, err := strconv.Atoi(header[1 : len(header)-2])
+	if nil != err {
+		return nil, err
+	}
+	args := make([]string, count)
+	for idx := range args {
+		if _, err = aReader.ReadString('\n'); nil != err {
+			return nil, err // `$<len>` line
+		}
+		arg, err := aReader.ReadString('\n')
+		if nil != err {
+			return nil, err
+		}
+		args[idx] = arg[:len(arg)-2]
+	}
+
+	return args, nil
+} // readFakeCommand()
+
+func TestRedisClient(t *testing.T) {
+	addr := startFakeRedis(t)
+	client := newRedisClient(&TRedisConfig{Addr: addr})
+
+	if count, err := client.incr(`viol:192.0.2.1`, time.Minute); (nil != err) || (1 != count) {
+		t.Errorf(`incr() = (%d, %v), want (1, nil)`, count, err)
+	}
+	if count, err := client.incr(`viol:192.0.2.1`, time.Minute); (nil != err) || (2 != count) {
+		t.Errorf(`incr() = (%d, %v), want (2, nil)`, count, err)
+	}
+
+	if banned, err := client.banned(`192.0.2.1`); (nil != err) || banned {
+		t.Errorf(`banned() = (%v, %v), want (false, nil)`, banned, err)
+	}
+	if err := client.ban(`192.0.2.1`, time.Minute); nil != err {
+		t.Errorf(`ban() failed: %v`, err)
+	}
+	if banned, err := client.banned(`192.0.2.1`); (nil != err) || !banned {
+		t.Errorf(`banned() = (%v, %v), want (true, nil)`, banned, err)
+	}
+} // TestRedisClient()
+
+func TestRedisOutageFallback(t *testing.T) {
+	// nothing listens on this address: every command must fail fast
+	// and the guard must keep enforcing its local bans.
+	client := newRedisClient(&TRedisConfig{Addr: `127.0.0.1:1`, TimeoutSec: 1})
+	if _, err := client.incr(`viol:x`, time.Minute); nil == err {
+		t.Error(`incr() succeeded without a server`)
+	}
+	// during the bypass period commands fail without dialing again:
+	if _, err := client.do(`PING`); errRedisDown != err {
+		t.Errorf(`do() during bypass = %v, want errRedisDown`, err)
+	}
+
+	guard := newRequestGuard(&TLimitsConfig{
+		BanAfter: 1, BanMinutes: 10,
+		Redis: &TRedisConfig{Addr: `127.0.0.1:1`, TimeoutSec: 1},
+	})
+	guard.violation(`192.0.2.9:1234`)
+	if !guard.banned(`192.0.2.9:1234`) {
+		t.Error(`local ban lost during Redis outage`)
+	}
+} // TestRedisOutageFallback()
+
+func TestGuardSharedBans(t *testing.T) {
+	addr := startFakeRedis(t)
+	conf := &TLimitsConfig{BanAfter: 2, BanMinutes: 10,
+		Redis: &TRedisConfig{Addr: addr}}
+
+	// two violations seen by two different replicas:
+	replica1 := newRequestGuard(conf)
+	replica2 := newRequestGuard(conf)
+	replica1.violation(`192.0.2.5:1111`)
+	if replica1.banned(`192.0.2.5:1111`) {
+		t.Fatal(`banned after a single violation`)
+	}
+	replica2.violation(`192.0.2.5:2222`)
+
+	// the shared count reached the threshold, so both replicas -
+	// and any new one - must see the ban:
+	for idx, guard := range []*tRequestGuard{
+		replica1, replica2, newRequestGuard(conf)} {
+		if !guard.banned(`192.0.2.5:3333`) {
+			t.Errorf(`replica %d missed the shared ban`, idx)
+		}
+	}
+} // TestGuardSharedBans()
+
+/* _EoF_ */
@@ -0,0 +1,1167 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+
+// Package reprox implements a simple virtual-host aware reverse proxy.
+//
+// Incoming requests are dispatched by their `Host:` header to the
+// backend server configured for the respective virtual host, and all
+// traffic is written to Apache-style access and error logs.
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mwat56/reprox/events"
+)
+
+// `acmeChallengePath` is the URL prefix reserved for ACME HTTP-01
+// challenges (RFC 8555).
+const acmeChallengePath = `/.well-known/acme-challenge/`
+
+type (
+	// `TProxyHandler` dispatches incoming requests to the backend
+	// servers of the configured virtual hosts.
+	TProxyHandler struct {
+		mtx         sync.RWMutex
+		acmeCache   *tAcmeCache
+		alerts      *TAlertMonitor
+		bus         *events.TPubSub
+		routes      *tRouteTable
+		conf        *TConfig
+		logger      *TLogger
+		events      TEventLogger
+		maintenance bool
+		metrics     *TMetrics
+		middlewares []TMiddleware
+		chain       http.Handler
+		guard       *tRequestGuard
+		shedder     *tLoadShedder
+		slo         *TSLOTracker
+		store       TStateStore
+		transport   http.RoundTripper
+		trusted     []*net.IPNet
+	}
+
+	// `TMiddleware` wraps an `http.Handler` with additional
+	// behaviour, e.g. authentication, logging, or metrics.
+	TMiddleware func(http.Handler) http.Handler
+
+	// `tLogWriter` wraps a `ResponseWriter` to capture the response
+	// status and size for the access log.
+	tLogWriter struct {
+		http.ResponseWriter
+		status  int
+		size    int64
+		backend string // selected backend, for the access log
+	}
+
+	// `tCountReader` wraps a request body to count the bytes
+	// received from the client for the traffic accounting.
+	tCountReader struct {
+		io.ReadCloser
+		size int64
+	}
+)
+
+// --------------------------------------------------------------------------
+// tCountReader methods:
+
+// `Read()` passes the read through, counting the bytes received.
+func (cr *tCountReader) Read(aData []byte) (int, error) {
+	read, err := cr.ReadCloser.Read(aData)
+	cr.size += int64(read)
+
+	return read, err
+} // Read()
+
+// --------------------------------------------------------------------------
+// tLogWriter methods:
+
+// `Write()` sends `aData` to the client, counting the bytes written.
+func (lw *tLogWriter) Write(aData []byte) (int, error) {
+	if 0 == lw.status {
+		lw.status = http.StatusOK
+	}
+	written, err := lw.ResponseWriter.Write(aData)
+	lw.size += int64(written)
+
+	return written, err
+} // Write()
+
+// `WriteHeader()` sends the HTTP status code to the client.
+func (lw *tLogWriter) WriteHeader(aStatus int) {
+	lw.status = aStatus
+	lw.ResponseWriter.WriteHeader(aStatus)
+} // WriteHeader()
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewProxyHandler()` returns a proxy handler configured by the
+// given options.
+//
+// Without options an empty handler is created whose routes can be
+// added later via `AddRoute()`; embedders typically pass
+// `WithConfig()` or `WithRoutes()` plus `WithLogger()`.
+//
+// All routes of the configuration are constructed and validated up
+// front, so misconfigured backend URLs surface here instead of on
+// the first request.  The function never logs fatally or terminates
+// the process, so it's safe to use from other programs embedding
+// the proxy.
+//
+// Parameters:
+//   - `aOptions`: The configuration options to apply in order.
+//
+// Returns:
+//   - `*TProxyHandler`: The new proxy handler.
+//   - `error`: `ErrInvalidTarget` if a configured backend URL is
+//     unusable.
+func NewProxyHandler(aOptions ...TOption) (*TProxyHandler, error) {
+	ph := &TProxyHandler{
+		bus:     events.New(),
+		routes:  newRouteTable(),
+		conf:    &TConfig{Hosts: make(map[string]*THostConfig)},
+		logger:  &TLogger{},
+		metrics: newMetrics(),
+	}
+	for _, option := range aOptions {
+		option(ph)
+	}
+	if nil != ph.conf.AcmeCache {
+		cache, err := newAcmeCache(ph.conf.AcmeCache)
+		if nil != err {
+			return nil, err
+		}
+		ph.acmeCache = cache
+	}
+	if err := ph.BuildRoutes(); nil != err {
+		return nil, err
+	}
+	if (nil != ph.store) && (nil != ph.guard) {
+		if err := ph.guard.loadFrom(ph.store); nil != err {
+			if nil != ph.events {
+				ph.events.Warn(`restoring ban list failed`,
+					TLogField{`error`, err.Error()})
+			}
+		}
+	}
+
+	return ph, nil
+} // NewProxyHandler()
+
+// --------------------------------------------------------------------------
+// TProxyHandler methods:
+
+// `ServeHTTP()` dispatches `aRequest` to the backend server of the
+// request's virtual host.
+//
+// Parameters:
+//   - `aWriter`: Used to send the response to the client.
+//   - `aRequest`: The incoming HTTP request.
+func (ph *TProxyHandler) ServeHTTP(aWriter http.ResponseWriter, aRequest *http.Request) {
+	hostname := normalizeHostname(hostOnly(aRequest.Host))
+	rlog := newRequestLogger(ph.logger, newRequestID(), hostname)
+	aRequest = aRequest.WithContext(
+		withRequestLogger(aRequest.Context(), rlog))
+	if "" == aRequest.Header.Get(`X-Request-Id`) {
+		// let the backends correlate their own logs with ours:
+		aRequest.Header.Set(`X-Request-Id`, rlog.ID())
+	}
+	lw := &tLogWriter{ResponseWriter: aWriter}
+	cr := &tCountReader{ReadCloser: aRequest.Body}
+	if nil != aRequest.Body {
+		aRequest.Body = cr
+	}
+	started := time.Now()
+	ph.metrics.requestStart(hostname)
+	defer func() {
+		ph.metrics.requestDone(hostname)
+		ph.metrics.countRequest(hostname, lw.status)
+		ph.metrics.countTransfer(hostname, cr.size, lw.size)
+		if nil != ph.alerts {
+			ph.alerts.record(hostname, time.Since(started), lw.status)
+		}
+		if nil != ph.slo {
+			ph.slo.record(hostname, time.Since(started), lw.status)
+		}
+		ph.logger.Log(aRequest, lw.status, lw.size,
+			time.Since(started), lw.backend)
+	}()
+
+	if nil != ph.guard {
+		if ph.guard.banned(aRequest.RemoteAddr) {
+			http.Error(lw, `forbidden`, http.StatusForbidden)
+			return
+		}
+		if status, reason := ph.guard.check(aRequest); 0 != status {
+			ph.guard.violation(aRequest.RemoteAddr)
+			if nil != ph.events {
+				ph.events.Warn(`request rejected`,
+					TLogField{`host`, hostname},
+					TLogField{`client`, aRequest.RemoteAddr},
+					TLogField{`reason`, reason})
+			}
+			ph.metrics.countError(hostname,
+				reason+` from `+aRequest.RemoteAddr)
+			http.Error(lw, reason, status)
+			return
+		}
+	}
+
+	if !normalizeRequestPath(aRequest) {
+		if nil != ph.guard {
+			ph.guard.violation(aRequest.RemoteAddr)
+		}
+		ph.metrics.countError(hostname,
+			`malicious request path from `+aRequest.RemoteAddr)
+		http.Error(lw, `invalid request path`, http.StatusBadRequest)
+		return
+	}
+
+	// `PURGE` requests from trusted clients invalidate cached
+	// entries below the request path instead of being proxied:
+	if `PURGE` == aRequest.Method {
+		if !ph.trustedClient(aRequest.RemoteAddr) {
+			http.Error(lw, `forbidden`, http.StatusForbidden)
+			return
+		}
+		purged := ph.PurgeCache(hostname, aRequest.URL.Path,
+			aRequest.Header.Get(`X-Purge-Key`))
+		lw.Header().Set(`Content-Type`, `text/plain; charset=utf-8`)
+		fmt.Fprintf(lw, "purged %d entries\n", purged)
+		return
+	}
+
+	ph.sanitizeHeaders(aRequest)
+
+	// ACME HTTP-01 challenges are answered from the configured
+	// webroot even while everything else is proxied (or in
+	// maintenance), so certificate renewals never break:
+	if strings.HasPrefix(aRequest.URL.Path, acmeChallengePath) {
+		if ph.serveAcmeChallenge(lw, aRequest) {
+			return
+		}
+	}
+
+	if (nil != ph.shedder) && ph.shedder.shed(ph.hostPriority(hostname)) {
+		lw.Header().Set(`Retry-After`, ph.shedder.retryAfter())
+		http.Error(lw, `server overloaded`, http.StatusServiceUnavailable)
+		return
+	}
+
+	if ph.InMaintenance() {
+		lw.Header().Set(`Retry-After`, `300`)
+		http.Error(lw, `temporarily down for maintenance`,
+			http.StatusServiceUnavailable)
+		return
+	}
+
+	ph.mtx.RLock()
+	chain := ph.chain
+	ph.mtx.RUnlock()
+	if nil == chain {
+		chain = http.HandlerFunc(ph.route)
+	}
+	chain.ServeHTTP(lw, aRequest)
+} // ServeHTTP()
+
+// `Use()` appends `aMiddleware` to the handler's middleware chain.
+//
+// Middlewares run in the order they were added, after the built-in
+// logging/maintenance handling but before the routing/proxy step,
+// so embedders can insert their own authentication, logging, or
+// metrics layers.
+//
+// Parameters:
+//   - `aMiddleware`: The middleware to append to the chain.
+func (ph *TProxyHandler) Use(aMiddleware TMiddleware) {
+	ph.mtx.Lock()
+	defer ph.mtx.Unlock()
+
+	ph.middlewares = append(ph.middlewares, aMiddleware)
+	chain := http.Handler(http.HandlerFunc(ph.route))
+	for idx := len(ph.middlewares) - 1; 0 <= idx; idx-- {
+		chain = ph.middlewares[idx](chain)
+	}
+	ph.chain = chain
+} // Use()
+
+// `route()` performs the actual per-host routing and proxying; it's
+// the innermost element of the middleware chain.
+func (ph *TProxyHandler) route(aWriter http.ResponseWriter, aRequest *http.Request) {
+	hostname := normalizeHostname(hostOnly(aRequest.Host))
+	rlog := RequestLogger(aRequest.Context())
+	if nil == rlog {
+		// e.g. called outside `ServeHTTP()` by embedders:
+		rlog = newRequestLogger(ph.logger, ``, hostname)
+	}
+
+	route := ph.routes.lookup(hostname)
+	if (nil != route) && ("" != route.canonical) {
+		scheme := `http`
+		if nil != aRequest.TLS {
+			scheme = `https`
+		}
+		http.Redirect(aWriter, aRequest,
+			scheme+`://`+route.canonical+aRequest.URL.RequestURI(),
+			http.StatusMovedPermanently)
+		return
+	}
+	if nil == route {
+		ph.mtx.RLock()
+		host, exists := ph.conf.Hosts[hostname]
+		ph.mtx.RUnlock()
+		if !exists {
+			rlog.Error(`no backend configured`)
+			http.Error(aWriter, `unknown host`, http.StatusNotFound)
+			return
+		}
+		target, err := url.Parse(host.effectiveTarget())
+		if nil != err {
+			rlog.Error(`invalid backend URL: ` + err.Error())
+			http.Error(aWriter, `bad gateway`, http.StatusBadGateway)
+			return
+		}
+		route = ph.newRoute(target, host)
+		ph.routes.set(hostname, route)
+	}
+
+	if (nil != route.schedule) && !route.schedule.active(time.Now()) {
+		aWriter.Header().Set(`Retry-After`, `300`)
+		http.Error(aWriter, `temporarily down for maintenance`,
+			http.StatusServiceUnavailable)
+		return
+	}
+
+	applyMethodOverride(aRequest, route.host.MethodOverrides)
+
+	if !route.host.allowsMethod(aRequest.Method) {
+		aWriter.Header().Set(`Allow`,
+			strings.ToUpper(strings.Join(route.host.Methods, `, `)))
+		http.Error(aWriter, `method not allowed`,
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	if (nil != route.script) && route.script.runRequest(aWriter, aRequest) {
+		if lw, ok := aWriter.(*tLogWriter); ok {
+			lw.backend = `script`
+		}
+		rlog.setBackend(`script`)
+		return
+	}
+
+	override, haveOverride := route.host.Overrides[aRequest.URL.Path]
+	if ((!haveOverride) || (nil == override)) && (nil != route.overrides) {
+		// longest-prefix match of the configured `/…/` overrides:
+		override, haveOverride = route.overrides.match(aRequest.URL.Path)
+	}
+	if haveOverride && (nil != override) {
+		if lw, ok := aWriter.(*tLogWriter); ok {
+			lw.backend = `override`
+		}
+		rlog.setBackend(`override`)
+		override.serve(aWriter, aRequest)
+		return
+	}
+
+	if nil != route.static {
+		if lw, ok := aWriter.(*tLogWriter); ok {
+			lw.backend = `static:` + route.host.StaticDir
+		}
+		rlog.setBackend(`static:` + route.host.StaticDir)
+		route.static.ServeHTTP(aWriter, aRequest)
+		return
+	}
+
+	var stale *tCacheEntry
+	if (nil != route.cache) && cacheableRequest(aRequest) {
+		if entry := route.cache.get(cacheKey(aRequest), aRequest); nil != entry {
+			now := time.Now()
+			switch {
+			case entry.fresh(now):
+				if lw, ok := aWriter.(*tLogWriter); ok {
+					lw.backend = `cache`
+				}
+				entry.serve(aWriter, aRequest, false)
+				return
+
+			case entry.staleOK(now):
+				// RFC 5861: answer stale right away, refresh in
+				// the background:
+				if lw, ok := aWriter.(*tLogWriter); ok {
+					lw.backend = `cache`
+				}
+				entry.serve(aWriter, aRequest, true)
+				ph.revalidate(route, entry, aRequest)
+				return
+
+			case entry.staleOnError(now):
+				stale = entry // fallback if the backend fails
+			}
+		}
+	}
+
+	proxy, backend := route.proxy, route.host.effectiveTarget()
+	if route.useBackup() {
+		proxy, backend = route.backup, route.host.BackupTarget
+	}
+	if lw, ok := aWriter.(*tLogWriter); ok {
+		lw.backend = backend
+	}
+	rlog.setBackend(backend)
+	if (0 < len(route.host.EarlyHints)) &&
+		(http.MethodGet == aRequest.Method) && aRequest.ProtoAtLeast(1, 1) {
+		// RFC 8297: let the browser start preloading while the
+		// backend is still working on the real answer:
+		for _, link := range route.host.EarlyHints {
+			aWriter.Header().Add(`Link`, link)
+		}
+		aWriter.WriteHeader(http.StatusEarlyHints)
+	}
+	if nil != route.queue {
+		if !route.queue.acquire(aRequest.Context()) {
+			rlog.Error(`backend concurrency limit reached`)
+			aWriter.Header().Set(`Retry-After`, `1`)
+			http.Error(aWriter, `backend busy`, http.StatusServiceUnavailable)
+			return
+		}
+		defer route.queue.release()
+	}
+	if nil != route.bucket {
+		aWriter = &tThrottleWriter{ResponseWriter: aWriter, bucket: route.bucket}
+	}
+	if timeout := ph.requestTimeout(route.host); 0 < timeout {
+		// a context deadline instead of `http.TimeoutHandler` so
+		// streamed and hijacked responses keep working:
+		ctx, cancel := context.WithTimeout(aRequest.Context(), timeout)
+		defer cancel()
+		aRequest = aRequest.WithContext(ctx)
+	}
+	if nil != stale {
+		aWriter = &tStaleWriter{ResponseWriter: aWriter,
+			entry: stale, request: aRequest}
+	}
+	proxy.ServeHTTP(aWriter, aRequest)
+} // route()
+
+// `revalidate()` refreshes a stale cache entry in the background
+// (at most one refresh per entry at a time) by replaying the
+// request against the backend; the fresh response is stored by the
+// proxy's `ModifyResponse` hook, the response itself is discarded.
+func (ph *TProxyHandler) revalidate(aRoute *tRoute, aEntry *tCacheEntry,
+	aRequest *http.Request) {
+	if !aEntry.revalidating.CompareAndSwap(false, true) {
+		return
+	}
+	request := aRequest.Clone(context.Background())
+	request.Body = http.NoBody
+	go func() {
+		defer aEntry.revalidating.Store(false)
+		aRoute.proxy.ServeHTTP(
+			&tDiscardWriter{header: make(http.Header)}, request)
+	}()
+} // revalidate()
+
+// `requestTimeout()` returns the effective timeout of one request
+// to `aHost`: its own setting, falling back to the global default;
+// a negative per-host value disables the timeout entirely.
+func (ph *TProxyHandler) requestTimeout(aHost *THostConfig) time.Duration {
+	if 0 > aHost.RequestTimeoutSec {
+		return 0
+	}
+	if 0 < aHost.RequestTimeoutSec {
+		return time.Duration(aHost.RequestTimeoutSec) * time.Second
+	}
+
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	return time.Duration(ph.conf.RequestTimeoutSec) * time.Second
+} // requestTimeout()
+
+// `newRoute()` builds the complete routing entry of one virtual
+// host: its reverse proxy plus the optional egress throttle and
+// discovered upstream pool.
+func (ph *TProxyHandler) newRoute(aTarget *url.URL, aHost *THostConfig) *tRoute {
+	route := &tRoute{host: aHost,
+		proxy:     ph.newProxy(aTarget, aHost),
+		overrides: overrideTrie(aHost.Overrides)}
+	if "" != aHost.Script {
+		script, err := loadScript(aHost.Script)
+		if nil == err {
+			route.script = script
+			if script.modifiesResponses() {
+				inner := route.proxy.ModifyResponse
+				route.proxy.ModifyResponse = func(aResponse *http.Response) error {
+					if nil != inner {
+						if err := inner(aResponse); nil != err {
+							return err
+						}
+					}
+					script.runResponse(aResponse)
+					return nil
+				}
+			}
+		} else {
+			ph.logger.Error(``, `invalid script: `+err.Error())
+		}
+	}
+	if 0 < aHost.MaxKBPerSec {
+		route.bucket = newTokenBucket(int64(aHost.MaxKBPerSec) << 10)
+	}
+	if 0 < aHost.MaxConcurrent {
+		route.queue = newBackendQueue(aHost.MaxConcurrent,
+			aHost.QueueDepth,
+			time.Duration(aHost.QueueWaitSec)*time.Second)
+	}
+	if nil != aHost.Cache {
+		cache := newResponseCache(aHost.Cache)
+		route.cache = cache
+		inner := route.proxy.ModifyResponse
+		route.proxy.ModifyResponse = func(aResponse *http.Response) error {
+			if nil != inner {
+				if err := inner(aResponse); nil != err {
+					return err
+				}
+			}
+			cache.maybeStore(aResponse)
+			return nil
+		}
+	}
+	if nil != aHost.Schedule {
+		schedule, err := newSchedule(aHost.Schedule)
+		if nil == err {
+			route.schedule = schedule
+		} else {
+			ph.logger.Error(``, `invalid schedule: `+err.Error())
+		}
+	}
+	if "" != aHost.BackupTarget {
+		backup, err := url.Parse(aHost.BackupTarget)
+		if (nil == err) && ("" != backup.Scheme) && ("" != backup.Host) {
+			route.backup = ph.newProxy(backup, aHost)
+			inner := route.proxy.ErrorHandler
+			route.proxy.ErrorHandler = func(aWriter http.ResponseWriter,
+				aR *http.Request, aErr error) {
+				route.markPrimaryDown()
+				inner(aWriter, aR, aErr)
+			}
+		} else {
+			ph.logger.Error(``, `invalid backup target URL: `+
+				aHost.BackupTarget)
+		}
+	}
+	if pool := newPool(aTarget, ph.logger); nil != pool {
+		route.pool = pool
+		inner := route.proxy.Director
+		route.proxy.Director = func(aR *http.Request) {
+			inner(aR)
+			if target := pool.pick(); nil != target {
+				aR.URL.Scheme, aR.URL.Host = target.Scheme, target.Host
+			}
+		}
+	}
+	if (nil != route.script) && route.script.routes() {
+		// chained last so a matching `route` rule wins over both
+		// the configured target and a discovered pool backend:
+		inner := route.proxy.Director
+		route.proxy.Director = func(aR *http.Request) {
+			inner(aR)
+			if target := route.script.routeTarget(aR); nil != target {
+				aR.URL.Scheme, aR.URL.Host = target.Scheme, target.Host
+			}
+		}
+	}
+
+	return route
+} // newRoute()
+
+// `SetHooks()` installs per-route request/response callbacks for
+// `aHostname`, so embedders can e.g. sign outgoing requests or
+// rewrite response headers without forking the proxy internals.
+//
+// Parameters:
+//   - `aHostname`: The virtual host the hooks apply to.
+//   - `aDirector`: Mutates outgoing backend requests (may be nil).
+//   - `aModify`: Mutates backend responses (may be nil).
+//
+// Returns:
+//   - `error`: `ErrNoRoute` if `aHostname` has no route configured.
+func (ph *TProxyHandler) SetHooks(aHostname string,
+	aDirector func(*http.Request), aModify func(*http.Response) error) error {
+	ph.mtx.Lock()
+	defer ph.mtx.Unlock()
+
+	host, ok := ph.conf.Hosts[aHostname]
+	if !ok {
+		return fmt.Errorf("%w for %q", ErrNoRoute, aHostname)
+	}
+	host.Director = aDirector
+	host.ModifyResponse = aModify
+	// force a proxy rebuild with the new hooks:
+	ph.routes.remove(aHostname, 0)
+
+	return nil
+} // SetHooks()
+
+// `AddRoute()` adds or replaces the route of `aHostname`, sending
+// its requests to the backend `aTarget`.
+//
+// Parameters:
+//   - `aHostname`: The virtual host to (re-)configure.
+//   - `aTarget`: The backend URL to proxy the host's requests to.
+//
+// Returns:
+//   - `error`: `ErrInvalidTarget` if `aTarget` is not a valid URL.
+func (ph *TProxyHandler) AddRoute(aHostname, aTarget string) error {
+	return ph.AddHost(aHostname, &THostConfig{Target: aTarget})
+} // AddRoute()
+
+// `AddHost()` adds or replaces the complete virtual host
+// configuration of `aHostname`, including its route.
+//
+// Parameters:
+//   - `aHostname`: The virtual host to (re-)configure.
+//   - `aHost`: The host's full proxy settings.
+//
+// Returns:
+//   - `error`: `ErrInvalidTarget` if the host's target is not a
+//     valid URL.
+func (ph *TProxyHandler) AddHost(aHostname string, aHost *THostConfig) error {
+	var route *tRoute
+	if "" != aHost.StaticDir {
+		route = &tRoute{host: aHost,
+			static:    newStaticServer(aHost.StaticDir),
+			overrides: overrideTrie(aHost.Overrides)}
+	} else {
+		target, err := url.Parse(aHost.effectiveTarget())
+		if (nil != err) || ("" == target.Scheme) || ("" == target.Host) {
+			return fmt.Errorf("%w %q", ErrInvalidTarget,
+				aHost.effectiveTarget())
+		}
+		route = ph.newRoute(target, aHost)
+	}
+
+	aHostname = normalizeHostname(aHostname)
+	ph.mtx.Lock()
+	defer ph.mtx.Unlock()
+	ph.conf.Hosts[aHostname] = aHost
+	ph.routes.set(aHostname, route)
+	for idx, alias := range aHost.Aliases {
+		aHost.Aliases[idx] = normalizeHostname(alias)
+		ph.routes.set(aHost.Aliases[idx], aliasRoute(aHostname, aHost, route))
+	}
+
+	return nil
+} // AddHost()
+
+// `aliasRoute()` returns the routing entry serving one alias of the
+// canonical host `aHostname`: either the canonical route itself
+// (transparent) or a stub redirecting to the canonical name.
+func aliasRoute(aHostname string, aHost *THostConfig, aCanonical *tRoute) *tRoute {
+	if aHost.RedirectAliases {
+		return &tRoute{host: aHost, canonical: aHostname}
+	}
+
+	return aCanonical
+} // aliasRoute()
+
+// `PurgeCache()` invalidates cached responses by host, URL path
+// prefix, and/or surrogate key.
+//
+// Parameters:
+//   - `aHostname`: The virtual host to purge (empty: all hosts).
+//   - `aPrefix`: A URL path prefix to match (empty: any path).
+//   - `aKey`: A backend-emitted surrogate key to match.
+//
+// Returns:
+//   - `int`: The number of cache entries dropped.
+func (ph *TProxyHandler) PurgeCache(aHostname, aPrefix, aKey string) int {
+	aHostname = normalizeHostname(aHostname)
+	purged := 0
+	for hostname, route := range ph.routes.all() {
+		if nil == route.cache {
+			continue
+		}
+		if ("" != aHostname) && (hostname != aHostname) {
+			continue
+		}
+		purged += route.cache.purge(aPrefix, aKey)
+	}
+
+	return purged
+} // PurgeCache()
+
+// `SwitchSlot()` atomically flips `aHostname`'s traffic to the
+// deployment slot `aSlot`, enabling instant blue/green cutover and
+// rollback; an empty slot name reverts to the plain `Target`.
+//
+// Parameters:
+//   - `aHostname`: The virtual host to switch.
+//   - `aSlot`: The deployment slot to activate.
+//
+// Returns:
+//   - `error`: `ErrNoRoute` resp. `ErrUnknownSlot` if the host or
+//     slot doesn't exist.
+func (ph *TProxyHandler) SwitchSlot(aHostname, aSlot string) error {
+	aHostname = normalizeHostname(aHostname)
+	ph.mtx.Lock()
+	defer ph.mtx.Unlock()
+
+	host, ok := ph.conf.Hosts[aHostname]
+	if !ok {
+		return fmt.Errorf("%w for %q", ErrNoRoute, aHostname)
+	}
+	if "" != aSlot {
+		if _, ok = host.Slots[aSlot]; !ok {
+			return fmt.Errorf("host %q: %w %q", aHostname,
+				ErrUnknownSlot, aSlot)
+		}
+	}
+	previous := host.ActiveSlot
+	host.ActiveSlot = aSlot
+	target, err := url.Parse(host.effectiveTarget())
+	if (nil != err) || ("" == target.Scheme) || ("" == target.Host) {
+		host.ActiveSlot = previous
+		return fmt.Errorf("host %q: slot %q: %w %q",
+			aHostname, aSlot, ErrInvalidTarget, host.effectiveTarget())
+	}
+	ph.routes.set(aHostname, ph.newRoute(target, host))
+
+	return nil
+} // SwitchSlot()
+
+// `Bus()` returns the handler's internal event bus broadcasting
+// configuration reloads, backend health transitions, and shutdown
+// to interested subsystems.
+func (ph *TProxyHandler) Bus() *events.TPubSub {
+	return ph.bus
+} // Bus()
+
+// `Metrics()` returns the handler's runtime metrics collector.
+func (ph *TProxyHandler) Metrics() *TMetrics {
+	return ph.metrics
+} // Metrics()
+
+// `SLO()` returns the handler's SLO tracker, or `nil` if no
+// objectives are configured.
+func (ph *TProxyHandler) SLO() *TSLOTracker {
+	return ph.slo
+} // SLO()
+
+// `InMaintenance()` reports whether maintenance mode is active.
+func (ph *TProxyHandler) InMaintenance() bool {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	return ph.maintenance
+} // InMaintenance()
+
+// `BuildRoutes()` constructs and validates the reverse proxies of
+// all configured virtual hosts up front, so misconfigured backend
+// URLs are caught at startup instead of on the first request, and
+// the request hot path never has to build a proxy.
+//
+// Returns:
+//   - `error`: The first invalid backend URL encountered.
+func (ph *TProxyHandler) BuildRoutes() error {
+	ph.mtx.RLock()
+	conf := ph.conf
+	ph.mtx.RUnlock()
+
+	routes, err := ph.buildRoutesFrom(conf)
+	if nil != err {
+		return err
+	}
+	ph.routes.replaceAll(routes, 0)
+
+	return nil
+} // BuildRoutes()
+
+// `Reload()` validates and applies `aConfig`, atomically replacing
+// the whole routing table; on error the old configuration stays
+// active.
+//
+// Parameters:
+//   - `aConfig`: The new proxy configuration to apply.
+//
+// Returns:
+//   - `error`: The first invalid backend URL in `aConfig`.
+func (ph *TProxyHandler) Reload(aConfig *TConfig) error {
+	routes, err := ph.buildRoutesFrom(aConfig)
+	if nil != err {
+		return err
+	}
+
+	ph.mtx.Lock()
+	ph.conf = aConfig
+	ph.trusted = parseTrustedNets(aConfig.TrustedProxies)
+	ph.mtx.Unlock()
+	ph.routes.replaceAll(routes, ph.drainPeriod())
+	ph.bus.Publish(events.TopicReload, len(aConfig.Hosts))
+
+	return nil
+} // Reload()
+
+// `ExportHosts()` returns a copy of the complete virtual host
+// configurations currently being served, suitable for JSON export
+// and later re-import via `ImportHosts()`.
+//
+// Returns:
+//   - `map[string]*THostConfig`: Hostname to host settings pairs.
+func (ph *TProxyHandler) ExportHosts() map[string]*THostConfig {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	hosts := make(map[string]*THostConfig, len(ph.conf.Hosts))
+	for hostname, host := range ph.conf.Hosts {
+		copied := *host
+		hosts[hostname] = &copied
+	}
+
+	return hosts
+} // ExportHosts()
+
+// `ImportHosts()` validates and applies a complete new set of
+// virtual host configurations, atomically replacing the routing
+// table; the global settings (logs, limits, etc.) stay untouched.
+// On error the old routes stay active.
+//
+// Parameters:
+//   - `aHosts`: The hostname to host settings pairs to apply.
+//
+// Returns:
+//   - `error`: `ErrConfigInvalid` for an empty set, or
+//     `ErrInvalidTarget` for an unusable backend URL.
+func (ph *TProxyHandler) ImportHosts(aHosts map[string]*THostConfig) error {
+	if 0 == len(aHosts) {
+		return fmt.Errorf("%w: no hosts given", ErrConfigInvalid)
+	}
+	hosts := make(map[string]*THostConfig, len(aHosts))
+	for hostname, host := range aHosts {
+		if nil == host {
+			return fmt.Errorf("%w: host %q lacks settings",
+				ErrConfigInvalid, hostname)
+		}
+		for idx, alias := range host.Aliases {
+			host.Aliases[idx] = normalizeHostname(alias)
+		}
+		hosts[normalizeHostname(hostname)] = host
+	}
+
+	ph.mtx.RLock()
+	conf := *ph.conf
+	ph.mtx.RUnlock()
+	conf.Hosts = hosts
+
+	routes, err := ph.buildRoutesFrom(&conf)
+	if nil != err {
+		return err
+	}
+	ph.mtx.Lock()
+	ph.conf = &conf
+	ph.mtx.Unlock()
+	ph.routes.replaceAll(routes, ph.drainPeriod())
+
+	return nil
+} // ImportHosts()
+
+// `buildRoutesFrom()` constructs the full route set of `aConfig`.
+func (ph *TProxyHandler) buildRoutesFrom(aConfig *TConfig) (map[string]*tRoute, error) {
+	routes := make(map[string]*tRoute, len(aConfig.Hosts))
+	for hostname, host := range aConfig.Hosts {
+		var route *tRoute
+		if "" != host.StaticDir {
+			route = &tRoute{host: host,
+				static:    newStaticServer(host.StaticDir),
+				overrides: overrideTrie(host.Overrides)}
+		} else {
+			target, err := url.Parse(host.effectiveTarget())
+			if (nil != err) || ("" == target.Scheme) || ("" == target.Host) {
+				return nil, fmt.Errorf("host %q: %w %q",
+					hostname, ErrInvalidTarget, host.effectiveTarget())
+			}
+			route = ph.newRoute(target, host)
+		}
+		routes[hostname] = route
+		for _, alias := range host.Aliases {
+			routes[alias] = aliasRoute(hostname, host, route)
+		}
+	}
+
+	return routes, nil
+} // buildRoutesFrom()
+
+// `RemoveRoute()` deletes the route of `aHostname`; requests for the
+// host are answered with `404` afterwards.
+//
+// Parameters:
+//   - `aHostname`: The virtual host to remove.
+func (ph *TProxyHandler) RemoveRoute(aHostname string) {
+	aHostname = normalizeHostname(aHostname)
+	ph.mtx.Lock()
+	defer ph.mtx.Unlock()
+
+	drain := time.Duration(ph.conf.DrainSec) * time.Second
+	if host, ok := ph.conf.Hosts[aHostname]; ok {
+		for _, alias := range host.Aliases {
+			ph.routes.remove(alias, drain)
+		}
+	}
+	delete(ph.conf.Hosts, aHostname)
+	ph.routes.remove(aHostname, drain)
+} // RemoveRoute()
+
+// `hostSlots()` returns the deployment slots and the currently
+// active slot of `aHostname`.
+func (ph *TProxyHandler) hostSlots(aHostname string) (map[string]string, string, bool) {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	host, ok := ph.conf.Hosts[normalizeHostname(aHostname)]
+	if !ok {
+		return nil, ``, false
+	}
+	slots := make(map[string]string, len(host.Slots))
+	for name, target := range host.Slots {
+		slots[name] = target
+	}
+
+	return slots, host.ActiveSlot, true
+} // hostSlots()
+
+// `hostPriority()` returns the priority class of `aHostname`
+// (normal for unknown hosts).
+func (ph *TProxyHandler) hostPriority(aHostname string) TPriority {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	if host, ok := ph.conf.Hosts[aHostname]; ok {
+		return host.priority()
+	}
+
+	return PrioNormal
+} // hostPriority()
+
+// `hostCerts()` returns the certificate files of all virtual hosts
+// that have one configured.
+func (ph *TProxyHandler) hostCerts() map[string]string {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	certs := make(map[string]string)
+	for hostname, host := range ph.conf.Hosts {
+		if "" != host.CertFile {
+			certs[hostname] = host.CertFile
+		}
+	}
+
+	return certs
+} // hostCerts()
+
+// `drainPeriod()` returns the configured drain period for removed
+// virtual hosts.
+func (ph *TProxyHandler) drainPeriod() time.Duration {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	return time.Duration(ph.conf.DrainSec) * time.Second
+} // drainPeriod()
+
+// `Routes()` returns the currently configured hostname to backend
+// URL mapping.
+func (ph *TProxyHandler) Routes() map[string]string {
+	ph.mtx.RLock()
+	defer ph.mtx.RUnlock()
+
+	routes := make(map[string]string, len(ph.conf.Hosts))
+	for hostname, host := range ph.conf.Hosts {
+		routes[hostname] = host.Target
+	}
+
+	return routes
+} // Routes()
+
+// `SetMaintenance()` switches maintenance mode on or off; while
+// active all proxied requests are answered with `503`.
+//
+// Parameters:
+//   - `aOn`: Whether to activate maintenance mode.
+func (ph *TProxyHandler) SetMaintenance(aOn bool) {
+	ph.mtx.Lock()
+	ph.maintenance = aOn
+	ph.mtx.Unlock()
+	if nil != ph.events {
+		ph.events.Info(`maintenance mode switched`,
+			TLogField{`active`, aOn})
+	}
+} // SetMaintenance()
+
+// `SaveState()` persists the proxy's dynamic state (currently the
+// guard's ban list) to the configured state store; a no-op without
+// a store.
+//
+// Returns:
+//   - `error`: A storage problem (`nil` on success).
+func (ph *TProxyHandler) SaveState() error {
+	if (nil == ph.store) || (nil == ph.guard) {
+		return nil
+	}
+
+	return ph.guard.saveTo(ph.store)
+} // SaveState()
+
+// `serveAcmeChallenge()` answers an ACME HTTP-01 challenge request
+// from the configured webroot or the shared ACME cache, reporting
+// whether it handled the request.
+func (ph *TProxyHandler) serveAcmeChallenge(aWriter http.ResponseWriter, aRequest *http.Request) bool {
+	ph.mtx.RLock()
+	webroot := ph.conf.AcmeWebroot
+	ph.mtx.RUnlock()
+	if ("" == webroot) && (nil == ph.acmeCache) {
+		return false
+	}
+
+	token := filepath.Base(aRequest.URL.Path)
+	if (`.` == token) || (`/` == token) || (`..` == token) {
+		http.Error(aWriter, `bad challenge token`, http.StatusBadRequest)
+		return true
+	}
+	if "" != webroot {
+		filename := filepath.Join(webroot, acmeChallengePath, token)
+		if _, err := os.Stat(filename); nil == err {
+			http.ServeFile(aWriter, aRequest, filename)
+			return true
+		}
+	}
+	// the token may have been published by another replica of a
+	// round-robin cluster:
+	if nil != ph.acmeCache {
+		if auth, ok := ph.acmeCache.getToken(token); ok {
+			aWriter.Header().Set(`Content-Type`, `text/plain`)
+			_, _ = aWriter.Write([]byte(auth))
+			return true
+		}
+	}
+	http.NotFound(aWriter, aRequest)
+
+	return true
+} // serveAcmeChallenge()
+
+// `newProxy()` builds the reverse proxy forwarding to `aTarget`,
+// honouring the per-route hooks configured in `aHost`.
+func (ph *TProxyHandler) newProxy(aTarget *url.URL, aHost *THostConfig) *httputil.ReverseProxy {
+	proxy := httputil.NewSingleHostReverseProxy(aTarget)
+	proxy.BufferPool = gBufferPool
+	proxy.Transport = ph.transport
+	if "" != aHost.ProxyURL {
+		if transport, err := outboundProxyTransport(
+			ph.transport, aHost.ProxyURL); nil == err {
+			proxy.Transport = transport
+		} else {
+			ph.logger.Error(aTarget.Host, err.Error())
+		}
+	}
+	if aHost.HTTP2 {
+		if `https` == aTarget.Scheme {
+			proxy.Transport = http2Transport(proxy.Transport)
+		} else {
+			// cleartext backends use the built-in prior-knowledge
+			// h2c client:
+			proxy.Transport = newH2CTransport()
+		}
+	}
+	var authHeader string
+	if nil != aHost.Auth {
+		authHeader = aHost.Auth.header()
+	}
+	if (nil != aHost.Director) || aHost.AnnounceTrailers ||
+		(0 < len(aHost.BodyRewrites)) || ("" != authHeader) {
+		defDirector, custom := proxy.Director, aHost.Director
+		rewrites := 0 < len(aHost.BodyRewrites)
+		trailers := aHost.AnnounceTrailers
+		proxy.Director = func(aR *http.Request) {
+			defDirector(aR)
+			if rewrites {
+				// force an uncompressed backend answer so the
+				// body filter can work on plain text:
+				aR.Header.Del(`Accept-Encoding`)
+			}
+			if trailers {
+				aR.Header.Set(`TE`, `trailers`)
+			}
+			if "" != authHeader {
+				aR.Header.Set(`Authorization`, authHeader)
+			}
+			if nil != custom {
+				custom(aR)
+			}
+		}
+	}
+	rewrite, custom := aHost.RewriteRedirects, aHost.ModifyResponse
+	if rewrite || (nil != custom) || (nil != aHost.Cookies) ||
+		(0 < len(aHost.BodyRewrites)) {
+		bodyRewrites, cookieConf := aHost.BodyRewrites, aHost.Cookies
+		proxy.ModifyResponse = func(aResp *http.Response) error {
+			if rewrite {
+				rewriteRedirectHeaders(aResp, aTarget)
+			}
+			if nil != cookieConf {
+				enforceCookieAttrs(aResp, cookieConf)
+			}
+			if 0 < len(bodyRewrites) {
+				rewriteBody(aResp, bodyRewrites)
+			}
+			if nil != custom {
+				return custom(aResp)
+			}
+			return nil
+		}
+	}
+	proxy.ErrorHandler = func(aW http.ResponseWriter, aR *http.Request, aErr error) {
+		if !errors.Is(aErr, ErrBackendDown) {
+			aErr = fmt.Errorf("%w: %w", ErrBackendDown, aErr)
+		}
+		hostname := hostOnly(aR.Host)
+		if rlog := RequestLogger(aR.Context()); nil != rlog {
+			rlog.Error(aErr.Error())
+		} else {
+			ph.logger.Error(hostname, aErr.Error())
+		}
+		ph.metrics.countError(hostname, aErr.Error())
+		if nil != ph.events {
+			ph.events.Error(`backend error`,
+				TLogField{`host`, hostname},
+				TLogField{`error`, aErr.Error()})
+		}
+		if context.DeadlineExceeded == aR.Context().Err() {
+			aW.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		aW.WriteHeader(http.StatusBadGateway)
+	}
+
+	return proxy
+} // newProxy()
+
+/* _EoF_ */
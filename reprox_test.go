@@ -0,0 +1,182 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewProxyHandlerOptions(t *testing.T) {
+	ph, _ := NewProxyHandler(WithRoutes(map[string]string{
+		`example.org`: `http://127.0.0.1:8181`,
+	}))
+	routes := ph.Routes()
+	if 1 != len(routes) {
+		t.Fatalf(`Routes() = %d entries, want 1`, len(routes))
+	}
+	if `http://127.0.0.1:8181` != routes[`example.org`] {
+		t.Errorf(`Routes() = %q`, routes[`example.org`])
+	}
+
+	if err := ph.AddRoute(`other.org`, `:not a url:`); nil == err {
+		t.Error(`AddRoute() expected error for invalid URL`)
+	}
+	if err := ph.AddRoute(`other.org`, `http://127.0.0.1:8282`); nil != err {
+		t.Errorf(`AddRoute() error = %v`, err)
+	}
+	ph.RemoveRoute(`other.org`)
+	if 1 != len(ph.Routes()) {
+		t.Error(`RemoveRoute() route still present`)
+	}
+} // TestNewProxyHandlerOptions()
+
+func TestOptionsHostNormalization(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`backend answer`))
+		}))
+	defer backend.Close()
+
+	// host keys handed to the options must match the normalized
+	// (lowercase, punycode) form used for request-time lookups:
+	ph, _ := NewProxyHandler(WithRoutes(map[string]string{
+		`API.Example.Org`: backend.URL,
+	}))
+	req := httptest.NewRequest(http.MethodGet, `http://api.example.org/`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusOK != rec.Code {
+		t.Errorf(`WithRoutes() mixed-case key status = %d, want 200`, rec.Code)
+	}
+
+	ph, err := NewProxyHandler(WithConfig(&TConfig{
+		Hosts: map[string]*THostConfig{
+			`WWW.Example.Org`: {
+				Target:  backend.URL,
+				Aliases: []string{`Alias.Example.Org`},
+			},
+		}}))
+	if nil != err {
+		t.Fatalf(`NewProxyHandler() failed: %v`, err)
+	}
+	for _, hostname := range []string{`www.example.org`, `alias.example.org`} {
+		req = httptest.NewRequest(http.MethodGet, `http://`+hostname+`/`, nil)
+		rec = httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		if http.StatusOK != rec.Code {
+			t.Errorf(`WithConfig() %q status = %d, want 200`,
+				hostname, rec.Code)
+		}
+	}
+} // TestOptionsHostNormalization()
+
+func TestServeHTTPRouting(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			aWriter.Header().Set(`X-Backend`, `hit`)
+			_, _ = aWriter.Write([]byte(`backend answer`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler(WithRoutes(map[string]string{
+		`example.org`: backend.URL,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusOK != rec.Code {
+		t.Errorf(`ServeHTTP() status = %d, want 200`, rec.Code)
+	}
+	if `hit` != rec.Header().Get(`X-Backend`) {
+		t.Error(`ServeHTTP() request didn't reach the backend`)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, `http://unknown.org/`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusNotFound != rec.Code {
+		t.Errorf(`ServeHTTP() status = %d, want 404`, rec.Code)
+	}
+} // TestServeHTTPRouting()
+
+func TestServeHTTPAliases(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`backend answer`))
+		}))
+	defer backend.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target:  backend.URL,
+		Aliases: []string{`www.example.org`},
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, `http://www.example.org/`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusOK != rec.Code {
+		t.Errorf(`ServeHTTP() alias status = %d, want 200`, rec.Code)
+	}
+
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target:          backend.URL,
+		Aliases:         []string{`www.example.org`},
+		RedirectAliases: true,
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	req = httptest.NewRequest(http.MethodGet,
+		`http://www.example.org/page?q=1`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusMovedPermanently != rec.Code {
+		t.Fatalf(`ServeHTTP() alias status = %d, want 301`, rec.Code)
+	}
+	if want, got := `http://example.org/page?q=1`,
+		rec.Header().Get(`Location`); want != got {
+		t.Errorf(`ServeHTTP() Location = %q, want %q`, got, want)
+	}
+} // TestServeHTTPAliases()
+
+func TestExportImportHosts(t *testing.T) {
+	ph, _ := NewProxyHandler(WithRoutes(map[string]string{
+		`example.org`: `http://127.0.0.1:8081`,
+		`example.net`: `http://127.0.0.1:8082`,
+	}))
+
+	exported := ph.ExportHosts()
+	if 2 != len(exported) {
+		t.Fatalf(`ExportHosts() = %d hosts, want 2`, len(exported))
+	}
+
+	// an unusable target must leave the old table active:
+	if err := ph.ImportHosts(map[string]*THostConfig{
+		`broken.example`: {Target: `:not a url`},
+	}); nil == err {
+		t.Error(`ImportHosts() accepted an invalid target`)
+	}
+	if 2 != len(ph.Routes()) {
+		t.Errorf(`routes after failed import = %d, want 2`, len(ph.Routes()))
+	}
+
+	delete(exported, `example.net`)
+	if err := ph.ImportHosts(exported); nil != err {
+		t.Fatalf(`ImportHosts() error = %v`, err)
+	}
+	routes := ph.Routes()
+	if (1 != len(routes)) || (`http://127.0.0.1:8081` != routes[`example.org`]) {
+		t.Errorf(`routes after import = %v`, routes)
+	}
+} // TestExportImportHosts()
+
+/* _EoF_ */
@@ -0,0 +1,49 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// `rewriteRedirectHeaders()` fixes absolute redirects leaked by the
+// backend: `Location`/`Content-Location` headers pointing at the
+// internal backend address (e.g. `http://192.168.1.2:8181/...`)
+// are rewritten to the public hostname and scheme the client used.
+//
+// Parameters:
+//   - `aResponse`: The backend response to fix up in place.
+//   - `aTarget`: The backend base URL of the route.
+func rewriteRedirectHeaders(aResponse *http.Response, aTarget *url.URL) {
+	request := aResponse.Request
+	if nil == request {
+		return
+	}
+	scheme := `http`
+	if nil != request.TLS {
+		scheme = `https`
+	}
+
+	for _, name := range [...]string{`Location`, `Content-Location`} {
+		value := aResponse.Header.Get(name)
+		if "" == value {
+			continue
+		}
+		location, err := url.Parse(value)
+		if (nil != err) || !location.IsAbs() ||
+			(location.Host != aTarget.Host) {
+			continue
+		}
+		location.Scheme, location.Host = scheme, request.Host
+		aResponse.Header.Set(name, location.String())
+	}
+} // rewriteRedirectHeaders()
+
+/* _EoF_ */
@@ -0,0 +1,88 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRewriteRedirectHeaders(t *testing.T) {
+	target, _ := url.Parse(`http://192.168.1.2:8181`)
+
+	rewrite := func(aTLS bool, aLocation string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		req.Host = `example.org`
+		if aTLS {
+			req.TLS = &tls.ConnectionState{}
+		}
+		resp := &http.Response{
+			StatusCode: http.StatusFound,
+			Header:     http.Header{`Location`: []string{aLocation}},
+			Request:    req,
+		}
+		rewriteRedirectHeaders(resp, target)
+		return resp
+	}
+
+	for _, tc := range []struct {
+		location, want string
+		tls            bool
+	}{
+		// a leaked backend address gets the public host and scheme:
+		{location: `http://192.168.1.2:8181/login?next=%2F`,
+			want: `http://example.org/login?next=%2F`},
+		{location: `http://192.168.1.2:8181/login`,
+			want: `https://example.org/login`, tls: true},
+		// foreign absolute URLs are the backend's business:
+		{location: `https://other.example.com/oauth`,
+			want: `https://other.example.com/oauth`},
+		// same IP but another port is not this route's backend:
+		{location: `http://192.168.1.2:9999/admin`,
+			want: `http://192.168.1.2:9999/admin`},
+		// relative redirects need no rewriting:
+		{location: `/login`, want: `/login`},
+		{location: `../index.html`, want: `../index.html`},
+	} {
+		resp := rewrite(tc.tls, tc.location)
+		if got := resp.Header.Get(`Location`); tc.want != got {
+			t.Errorf("rewrite(%q) = %q, want %q", tc.location, got, tc.want)
+		}
+	}
+
+	// `Content-Location` is rewritten the same way:
+	req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+	req.Host = `example.org`
+	resp := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header: http.Header{
+			`Content-Location`: []string{`http://192.168.1.2:8181/items/42`}},
+		Request: req,
+	}
+	rewriteRedirectHeaders(resp, target)
+	if want, got := `http://example.org/items/42`,
+		resp.Header.Get(`Content-Location`); want != got {
+		t.Errorf(`Content-Location = %q, want %q`, got, want)
+	}
+
+	// a response without request context is left untouched:
+	resp = &http.Response{
+		Header: http.Header{
+			`Location`: []string{`http://192.168.1.2:8181/login`}},
+	}
+	rewriteRedirectHeaders(resp, target)
+	if `http://192.168.1.2:8181/login` != resp.Header.Get(`Location`) {
+		t.Error(`rewrite without request context altered the header`)
+	}
+} // TestRewriteRedirectHeaders()
+
+/* _EoF_ */
@@ -0,0 +1,251 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+)
+
+type (
+	// `tRfc2136Provider` publishes DNS-01 challenge records via
+	// RFC 2136 dynamic updates, authenticated with a HMAC-SHA256
+	// TSIG key (RFC 8945) — the classic `nsupdate` mechanism.
+	tRfc2136Provider struct {
+		server   string // nameserver address (host:port)
+		zone     string // updated zone
+		tsigName string // TSIG key name
+		secret   []byte // decoded TSIG secret
+	}
+)
+
+// DNS wire-format constants used by the updater:
+const (
+	dnsClassIN   = 1
+	dnsClassNone = 254
+	dnsClassAny  = 255
+	dnsTypeSOA   = 6
+	dnsTypeTXT   = 16
+	dnsTypeTSIG  = 250
+
+	// `tsigAlgorithm` is the only TSIG algorithm supported here.
+	tsigAlgorithm = `hmac-sha256.`
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRfc2136Provider()` creates the RFC 2136 provider from
+// `aConfig`, validating its TSIG settings.
+func newRfc2136Provider(aConfig *TAcmeDNSConfig) (*tRfc2136Provider, error) {
+	if ("" == aConfig.Server) || ("" == aConfig.Zone) {
+		return nil, fmt.Errorf("rfc2136 provider needs server and zone")
+	}
+	if ("" == aConfig.TSIGName) || ("" == aConfig.TSIGSecret) {
+		return nil, fmt.Errorf("rfc2136 provider needs a TSIG key")
+	}
+	secret, err := base64.StdEncoding.DecodeString(aConfig.TSIGSecret)
+	if nil != err {
+		return nil, fmt.Errorf("invalid TSIG secret: %w", err)
+	}
+	server := aConfig.Server
+	if !strings.Contains(server, `:`) {
+		server += `:53`
+	}
+
+	return &tRfc2136Provider{
+		server:   server,
+		zone:     aConfig.Zone,
+		tsigName: aConfig.TSIGName,
+		secret:   secret,
+	}, nil
+} // newRfc2136Provider()
+
+// --------------------------------------------------------------------------
+// tRfc2136Provider methods:
+
+// `Cleanup()` removes the challenge TXT record again.
+func (rp *tRfc2136Provider) Cleanup(aDomain, aValue string) error {
+	return rp.update(acmeChallengeFQDN(aDomain), aValue, false)
+} // Cleanup()
+
+// `Present()` publishes the challenge TXT record.
+func (rp *tRfc2136Provider) Present(aDomain, aValue string) error {
+	return rp.update(acmeChallengeFQDN(aDomain), aValue, true)
+} // Present()
+
+// `update()` sends one signed dynamic update adding (or deleting)
+// the TXT record `aFQDN` = `aValue`.
+func (rp *tRfc2136Provider) update(aFQDN, aValue string, aAdd bool) error {
+	var msg bytes2136
+	id := uint16(rand.Intn(1 << 16))
+
+	// header: opcode UPDATE, one zone, one update record
+	msg.u16(id)
+	msg.u16(5 << 11) // opcode 5 = UPDATE
+	msg.u16(1)       // ZOCOUNT
+	msg.u16(0)       // PRCOUNT
+	msg.u16(1)       // UPCOUNT
+	msg.u16(0)       // ARCOUNT (TSIG added after signing)
+
+	// zone section:
+	msg.name(rp.zone)
+	msg.u16(dnsTypeSOA)
+	msg.u16(dnsClassIN)
+
+	// update section:
+	msg.name(aFQDN)
+	msg.u16(dnsTypeTXT)
+	rdata := append([]byte{byte(len(aValue))}, aValue...)
+	if aAdd {
+		msg.u16(dnsClassIN)
+		msg.u32(60) // TTL
+	} else {
+		// delete the specific record: class NONE, TTL 0
+		msg.u16(dnsClassNone)
+		msg.u32(0)
+	}
+	msg.u16(uint16(len(rdata)))
+	msg.raw(rdata)
+
+	signed := rp.sign(msg.data, id)
+
+	return exchange2136(rp.server, signed)
+} // update()
+
+// `sign()` appends the TSIG record (RFC 8945) to `aMessage`.
+func (rp *tRfc2136Provider) sign(aMessage []byte, aID uint16) []byte {
+	now := time.Now().Unix()
+	fudge := uint16(300)
+
+	// the MAC covers the unsigned message plus the TSIG variables:
+	var vars bytes2136
+	vars.name(rp.tsigName)
+	vars.u16(dnsClassAny)
+	vars.u32(0) // TTL
+	vars.name(tsigAlgorithm)
+	vars.u16(uint16(now >> 32)) // time signed, 48 bits
+	vars.u32(uint32(now))
+	vars.u16(fudge)
+	vars.u16(0) // error
+	vars.u16(0) // other length
+
+	mac := hmac.New(sha256.New, rp.secret)
+	mac.Write(aMessage)
+	mac.Write(vars.data)
+	digest := mac.Sum(nil)
+
+	// the TSIG resource record itself:
+	var rr bytes2136
+	rr.name(rp.tsigName)
+	rr.u16(dnsTypeTSIG)
+	rr.u16(dnsClassAny)
+	rr.u32(0) // TTL
+	var rdata bytes2136
+	rdata.name(tsigAlgorithm)
+	rdata.u16(uint16(now >> 32))
+	rdata.u32(uint32(now))
+	rdata.u16(fudge)
+	rdata.u16(uint16(len(digest)))
+	rdata.raw(digest)
+	rdata.u16(aID)
+	rdata.u16(0) // error
+	rdata.u16(0) // other length
+	rr.u16(uint16(len(rdata.data)))
+	rr.raw(rdata.data)
+
+	signed := append(aMessage, rr.data...)
+	// bump ARCOUNT for the appended TSIG record:
+	binary.BigEndian.PutUint16(signed[10:], 1)
+
+	return signed
+} // sign()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `exchange2136()` sends `aMessage` to `aServer` over TCP and
+// checks the answer's RCODE.
+func exchange2136(aServer string, aMessage []byte) error {
+	conn, err := net.DialTimeout(`tcp`, aServer, 10*time.Second)
+	if nil != err {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	// TCP DNS messages carry a two byte length prefix:
+	prefix := []byte{byte(len(aMessage) >> 8), byte(len(aMessage))}
+	if _, err = conn.Write(append(prefix, aMessage...)); nil != err {
+		return err
+	}
+	if _, err = io.ReadFull(conn, prefix); nil != err {
+		return err
+	}
+	answer := make([]byte, binary.BigEndian.Uint16(prefix))
+	if _, err = io.ReadFull(conn, answer); nil != err {
+		return err
+	}
+	if 4 > len(answer) {
+		return fmt.Errorf("truncated DNS answer")
+	}
+	if rcode := answer[3] & 0x0F; 0 != rcode {
+		return fmt.Errorf("dynamic update refused (RCODE %d)", rcode)
+	}
+
+	return nil
+} // exchange2136()
+
+// --------------------------------------------------------------------------
+// wire-format writer:
+
+type (
+	// `bytes2136` is a small DNS wire-format writer.
+	bytes2136 struct {
+		data []byte
+	}
+)
+
+// `name()` appends `aName` as uncompressed DNS labels.
+func (b *bytes2136) name(aName string) {
+	for _, label := range strings.Split(strings.TrimSuffix(aName, `.`), `.`) {
+		if "" == label {
+			continue
+		}
+		b.data = append(b.data, byte(len(label)))
+		b.data = append(b.data, label...)
+	}
+	b.data = append(b.data, 0)
+} // name()
+
+// `raw()` appends `aData` verbatim.
+func (b *bytes2136) raw(aData []byte) {
+	b.data = append(b.data, aData...)
+} // raw()
+
+// `u16()` appends `aValue` in network byte order.
+func (b *bytes2136) u16(aValue uint16) {
+	b.data = append(b.data, byte(aValue>>8), byte(aValue))
+} // u16()
+
+// `u32()` appends `aValue` in network byte order.
+func (b *bytes2136) u32(aValue uint32) {
+	b.data = append(b.data, byte(aValue>>24), byte(aValue>>16),
+		byte(aValue>>8), byte(aValue))
+} // u32()
+
+/* _EoF_ */
@@ -0,0 +1,205 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type (
+	// `TLogRotation` holds the built-in log rotation settings.
+	//
+	// A zero value disables the respective trigger; with both
+	// `MaxSizeMB` and `MaxAgeDays` zero no rotation happens at all
+	// and log files only change on an explicit `Reopen()` (e.g.
+	// triggered by `SIGUSR1` for an external logrotate setup).
+	TLogRotation struct {
+		// MaxSizeMB rotates a log file once it exceeds this size.
+		MaxSizeMB int64 `json:"maxSizeMB"`
+
+		// MaxAgeDays rotates a log file once it has been in use
+		// for this many days.
+		MaxAgeDays int `json:"maxAgeDays"`
+
+		// Keep is the number of rotated files to keep per log;
+		// zero keeps everything.
+		Keep int `json:"keep"`
+
+		// Compress gzips rotated files in the background.
+		Compress bool `json:"compress"`
+	}
+)
+
+// `rotateTimeFormat` is the timestamp appended to rotated log files.
+const rotateTimeFormat = `20060102-150405`
+
+// --------------------------------------------------------------------------
+// tLogFile methods:
+
+// `needsRotation()` reports whether the file exceeds one of the
+// configured rotation thresholds; the caller must hold `lf.mtx`.
+func (lf *tLogFile) needsRotation() bool {
+	if nil == lf.rotation {
+		return false
+	}
+	if (0 < lf.rotation.MaxSizeMB) &&
+		(lf.size >= (lf.rotation.MaxSizeMB << 20)) {
+		return true
+	}
+	if 0 < lf.rotation.MaxAgeDays {
+		age := time.Duration(lf.rotation.MaxAgeDays) * 24 * time.Hour
+		if time.Since(lf.opened) >= age {
+			return true
+		}
+	}
+
+	return false
+} // needsRotation()
+
+// `reopen()` closes and re-opens the log file under its own name,
+// picking up a file moved away by an external log rotation tool.
+func (lf *tLogFile) reopen() error {
+	lf.mtx.Lock()
+	defer lf.mtx.Unlock()
+
+	return lf.reopenLocked()
+} // reopen()
+
+// `reopenLocked()` implements `reopen()`; the caller holds `lf.mtx`.
+func (lf *tLogFile) reopenLocked() error {
+	if nil != lf.file {
+		_ = lf.file.Close()
+		lf.file = nil
+	}
+	file, err := os.OpenFile(lf.name,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0640)
+	if nil != err {
+		return fmt.Errorf("reopening log %q: %w", lf.name, err)
+	}
+	lf.file, lf.opened, lf.size = file, time.Now(), 0
+	if fi, err := file.Stat(); nil == err {
+		lf.size = fi.Size()
+	}
+
+	return nil
+} // reopenLocked()
+
+// `rotate()` moves the current log file aside, re-opens a fresh one,
+// and compresses/prunes the archives in the background; the caller
+// must hold `lf.mtx`.
+func (lf *tLogFile) rotate() {
+	archive := lf.name + `.` + time.Now().Format(rotateTimeFormat)
+	if err := os.Rename(lf.name, archive); nil != err {
+		return // keep writing to the current file
+	}
+	_ = lf.reopenLocked()
+
+	rot := lf.rotation
+	go func() {
+		if rot.Compress {
+			if err := gzipFile(archive); nil == err {
+				archive += `.gz`
+			}
+		}
+		if 0 < rot.Keep {
+			pruneArchives(lf.name, rot.Keep)
+		}
+	}()
+} // rotate()
+
+// --------------------------------------------------------------------------
+// TLogger methods:
+
+// `Reopen()` closes and re-opens all log files under their
+// configured names.
+//
+// This is meant to be called from a `SIGUSR1` handler so external
+// log rotation tools can move the files away without restarting
+// the proxy.
+//
+// Returns:
+//   - `error`: The first error encountered while re-opening.
+func (lg *TLogger) Reopen() error {
+	lg.mtx.RLock()
+	defer lg.mtx.RUnlock()
+
+	var err error
+	for _, lf := range lg.files {
+		if e := lf.reopen(); (nil != e) && (nil == err) {
+			err = e
+		}
+	}
+
+	return err
+} // Reopen()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `gzipFile()` compresses `aFilename` to `aFilename.gz` and removes
+// the original on success.
+func gzipFile(aFilename string) error {
+	src, err := os.Open(aFilename)
+	if nil != err {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(aFilename+`.gz`,
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+	if nil != err {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); nil == err {
+		err = zw.Close()
+	} else {
+		_ = zw.Close()
+	}
+	if e := dst.Close(); (nil != e) && (nil == err) {
+		err = e
+	}
+	if nil != err {
+		_ = os.Remove(aFilename + `.gz`)
+		return err
+	}
+
+	return os.Remove(aFilename)
+} // gzipFile()
+
+// `pruneArchives()` deletes the oldest rotated files of `aLogName`
+// keeping at most `aKeep` of them.
+func pruneArchives(aLogName string, aKeep int) {
+	matches, err := filepath.Glob(aLogName + `.*`)
+	if nil != err {
+		return
+	}
+	archives := matches[:0]
+	for _, name := range matches {
+		ext := filepath.Ext(name)
+		if (`.gz` == ext) || (len(name) == len(aLogName)+1+len(rotateTimeFormat)) {
+			archives = append(archives, name)
+		}
+	}
+	if len(archives) <= aKeep {
+		return
+	}
+	sort.Strings(archives) // timestamps sort chronologically
+	for _, name := range archives[:len(archives)-aKeep] {
+		_ = os.Remove(name)
+	}
+} // pruneArchives()
+
+/* _EoF_ */
@@ -0,0 +1,150 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type (
+	// `TRoutesDirWatcher` watches a `routes.d/` style directory of
+	// small per-site JSON files and merges them into the routing
+	// table at runtime.
+	//
+	// Each file maps hostnames to their host configuration (the
+	// same shape as the main config's `hosts` object); dropping a
+	// file publishes its sites, removing it unpublishes them — no
+	// main config edit or reload needed.
+	TRoutesDirWatcher struct {
+		mtx      sync.Mutex
+		dir      string
+		interval time.Duration
+		handler  *TProxyHandler
+		logger   *TLogger
+		done     chan struct{}
+		applied  map[string]string // hostname -> serialised config
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewRoutesDirWatcher()` creates a watcher merging the per-site
+// files below `aDir` into `aHandler`'s routes.
+//
+// Parameters:
+//   - `aDir`: The directory of per-site JSON files.
+//   - `aHandler`: The proxy handler whose routes are updated.
+//   - `aLogger`: Used to report problems with the site files.
+//
+// Returns:
+//   - `*TRoutesDirWatcher`: The new (not yet started) watcher.
+func NewRoutesDirWatcher(aDir string, aHandler *TProxyHandler, aLogger *TLogger) *TRoutesDirWatcher {
+	return &TRoutesDirWatcher{
+		dir:      aDir,
+		interval: 10 * time.Second,
+		handler:  aHandler,
+		logger:   aLogger,
+		done:     make(chan struct{}),
+		applied:  make(map[string]string),
+	}
+} // NewRoutesDirWatcher()
+
+// --------------------------------------------------------------------------
+// TRoutesDirWatcher methods:
+
+// `Start()` runs the periodic sync rounds until `Stop()` is called.
+func (rw *TRoutesDirWatcher) Start() {
+	go func() {
+		rw.sync()
+		ticker := time.NewTicker(rw.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				rw.sync()
+			case <-rw.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the periodic sync rounds.
+func (rw *TRoutesDirWatcher) Stop() {
+	close(rw.done)
+} // Stop()
+
+// `read()` collects the host configurations of all `*.json` files
+// in the watched directory.
+func (rw *TRoutesDirWatcher) read() (map[string]*THostConfig, error) {
+	names, err := filepath.Glob(filepath.Join(rw.dir, `*.json`))
+	if nil != err {
+		return nil, err
+	}
+
+	hosts := make(map[string]*THostConfig)
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if nil != err {
+			rw.logger.Error(`routes.d`, name+`: `+err.Error())
+			continue
+		}
+		var fileHosts map[string]*THostConfig
+		if err = json.Unmarshal(data, &fileHosts); nil != err {
+			rw.logger.Error(`routes.d`, name+`: `+err.Error())
+			continue
+		}
+		for hostname, host := range fileHosts {
+			hosts[hostname] = host
+		}
+	}
+
+	return hosts, nil
+} // read()
+
+// `sync()` applies the current directory contents, adding,
+// updating, and removing only what changed since last time.
+func (rw *TRoutesDirWatcher) sync() {
+	hosts, err := rw.read()
+	if nil != err {
+		rw.logger.Error(`routes.d`, err.Error())
+		return
+	}
+
+	rw.mtx.Lock()
+	defer rw.mtx.Unlock()
+	current := make(map[string]string, len(hosts))
+	for hostname, host := range hosts {
+		serialised, _ := json.Marshal(host)
+		current[hostname] = string(serialised)
+		if rw.applied[hostname] == current[hostname] {
+			continue
+		}
+		if err = rw.handler.AddHost(hostname, host); nil != err {
+			rw.logger.Error(`routes.d`, fmt.Sprintf(
+				"host %q: %v", hostname, err))
+			delete(current, hostname)
+			continue
+		}
+	}
+	for hostname := range rw.applied {
+		if _, ok := current[hostname]; !ok {
+			rw.handler.RemoveRoute(hostname)
+		}
+	}
+	rw.applied = current
+} // sync()
+
+/* _EoF_ */
@@ -0,0 +1,45 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRoutesDirSync(t *testing.T) {
+	dir := t.TempDir()
+	siteFile := filepath.Join(dir, `example.json`)
+	site := `{"example.org": {"target": "http://127.0.0.1:8080"}}`
+	if err := os.WriteFile(siteFile, []byte(site), 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	handler, _ := NewProxyHandler()
+	watcher := NewRoutesDirWatcher(dir, handler, &TLogger{})
+
+	watcher.sync()
+	routes := handler.Routes()
+	if target := routes[`example.org`]; `http://127.0.0.1:8080` != target {
+		t.Errorf("sync() route = %q, want %q",
+			target, `http://127.0.0.1:8080`)
+	}
+
+	// removing the file must unpublish the site:
+	if err := os.Remove(siteFile); nil != err {
+		t.Fatal(err)
+	}
+	watcher.sync()
+	if routes = handler.Routes(); 0 != len(routes) {
+		t.Errorf("sync() after removal kept %d routes", len(routes))
+	}
+} // TestRoutesDirSync()
+
+/* _EoF_ */
@@ -0,0 +1,214 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http/httputil"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// `tRoute` is one resolved routing entry: the host's settings
+	// plus its ready-built reverse proxy.
+	tRoute struct {
+		host      *THostConfig
+		proxy     *httputil.ReverseProxy
+		backup    *httputil.ReverseProxy       // failover upstream (nil: none)
+		bucket    *tTokenBucket                // egress throttle (nil: unlimited)
+		pool      *tUpstreamPool               // discovered backends (nil: fixed target)
+		queue     *tBackendQueue               // concurrency cap (nil: unlimited)
+		cache     *tResponseCache              // response cache (nil: disabled)
+		static    *tStaticServer               // static-file host (nil: proxied)
+		overrides *tPathTrie[*TOverrideConfig] // prefix overrides (nil: none)
+		script    *tScript                     // rule script (nil: none)
+		canonical string                       // redirect target of a `RedirectAliases` alias
+		schedule  *tSchedule                   // activation windows (nil: always active)
+		downUntil atomic.Int64                 // primary considered down until (unix nanos)
+	}
+
+	// `tRouteTable` is the concurrency-safe routing table.
+	//
+	// Lookups on the hot path are lock-free reads of an immutable
+	// map held in an `atomic.Value`; every mutation builds a fresh
+	// copy under the writers' mutex and swaps it in atomically.
+	// This replaces the former plain-map mutation from request
+	// goroutines which was a data race.
+	tRouteTable struct {
+		current  atomic.Value // map[string]*tRoute
+		wild     atomic.Value // *THostTree[*tRoute] (wildcard hosts)
+		mtx      sync.Mutex   // serialises writers and `draining`
+		draining map[string]*tDrainingRoute
+	}
+
+	// `tDrainingRoute` is a removed route still served until its
+	// drain deadline, so in-flight requests and sticky sessions
+	// aren't cut off by a config change.
+	tDrainingRoute struct {
+		route    *tRoute
+		deadline time.Time
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newRouteTable()` creates an empty routing table.
+func newRouteTable() *tRouteTable {
+	rt := &tRouteTable{}
+	rt.current.Store(make(map[string]*tRoute))
+	rt.wild.Store((*THostTree[*tRoute])(nil))
+
+	return rt
+} // newRouteTable()
+
+// `backupRetryPeriod` is how long a failed primary upstream is
+// bypassed in favour of the backup before it's tried again.
+const backupRetryPeriod = 10 * time.Second
+
+// --------------------------------------------------------------------------
+// tRoute methods:
+
+// `markPrimaryDown()` records a failed primary contact, diverting
+// the following requests to the backup upstream (if one is
+// configured) for the next `backupRetryPeriod`.
+func (r *tRoute) markPrimaryDown() {
+	r.downUntil.Store(time.Now().Add(backupRetryPeriod).UnixNano())
+} // markPrimaryDown()
+
+// `useBackup()` reports whether requests should currently go to the
+// backup upstream instead of the primary one.
+func (r *tRoute) useBackup() bool {
+	return (nil != r.backup) &&
+		(time.Now().UnixNano() < r.downUntil.Load())
+} // useBackup()
+
+// --------------------------------------------------------------------------
+// tRouteTable methods:
+
+// `drain()` moves `aRoute` into the draining set until `aDeadline`;
+// the caller must hold `rt.mtx`.
+func (rt *tRouteTable) drain(aHostname string, aRoute *tRoute, aDeadline time.Time) {
+	if nil == rt.draining {
+		rt.draining = make(map[string]*tDrainingRoute)
+	}
+	rt.draining[aHostname] = &tDrainingRoute{route: aRoute, deadline: aDeadline}
+} // drain()
+
+// `all()` returns the current routing map; the returned map is
+// immutable and must not be modified.
+func (rt *tRouteTable) all() map[string]*tRoute {
+	routes, _ := rt.current.Load().(map[string]*tRoute)
+
+	return routes
+} // all()
+
+// `lookup()` returns the route of `aHostname` (nil if unknown);
+// safe for concurrent use, lock-free for active routes.
+func (rt *tRouteTable) lookup(aHostname string) *tRoute {
+	routes, _ := rt.current.Load().(map[string]*tRoute)
+	if route, ok := routes[aHostname]; ok {
+		return route
+	}
+	// an unknown name may fall under a wildcard host:
+	wild, _ := rt.wild.Load().(*THostTree[*tRoute])
+	if route, ok := wild.Match(aHostname); ok {
+		return route
+	}
+
+	// slow path: a removed host may still be draining
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+	if draining, ok := rt.draining[aHostname]; ok {
+		if time.Now().Before(draining.deadline) {
+			return draining.route
+		}
+		delete(rt.draining, aHostname)
+	}
+
+	return nil
+} // lookup()
+
+// `remove()` deletes the route of `aHostname`, optionally keeping it
+// around for `aDrain` so current users aren't cut off immediately.
+func (rt *tRouteTable) remove(aHostname string, aDrain time.Duration) {
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	old, _ := rt.current.Load().(map[string]*tRoute)
+	route, ok := old[aHostname]
+	if !ok {
+		return
+	}
+	fresh := make(map[string]*tRoute, len(old))
+	for hostname, entry := range old {
+		if hostname != aHostname {
+			fresh[hostname] = entry
+		}
+	}
+	rt.current.Store(fresh)
+	rt.rebuildWildcards(fresh)
+	if 0 < aDrain {
+		rt.drain(aHostname, route, time.Now().Add(aDrain))
+	}
+} // remove()
+
+// `replaceAll()` swaps in a completely new route set atomically;
+// hosts dropped by the new set keep draining for `aDrain`.
+func (rt *tRouteTable) replaceAll(aRoutes map[string]*tRoute, aDrain time.Duration) {
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	if 0 < aDrain {
+		old, _ := rt.current.Load().(map[string]*tRoute)
+		deadline := time.Now().Add(aDrain)
+		for hostname, route := range old {
+			if _, keep := aRoutes[hostname]; !keep {
+				rt.drain(hostname, route, deadline)
+			}
+		}
+	}
+	rt.current.Store(aRoutes)
+	rt.rebuildWildcards(aRoutes)
+} // replaceAll()
+
+// `rebuildWildcards()` rebuilds the reversed-label matcher of all
+// wildcard (`*.`) hosts in `aRoutes`; the caller must hold `rt.mtx`.
+func (rt *tRouteTable) rebuildWildcards(aRoutes map[string]*tRoute) {
+	var tree *THostTree[*tRoute]
+	for hostname, route := range aRoutes {
+		if strings.HasPrefix(hostname, `*.`) {
+			if nil == tree {
+				tree = NewHostTree[*tRoute]()
+			}
+			tree.Set(hostname, route)
+		}
+	}
+	rt.wild.Store(tree)
+} // rebuildWildcards()
+
+// `set()` adds or replaces the route of `aHostname`.
+func (rt *tRouteTable) set(aHostname string, aRoute *tRoute) {
+	rt.mtx.Lock()
+	defer rt.mtx.Unlock()
+
+	old, _ := rt.current.Load().(map[string]*tRoute)
+	fresh := make(map[string]*tRoute, len(old)+1)
+	for hostname, route := range old {
+		fresh[hostname] = route
+	}
+	fresh[aHostname] = aRoute
+	rt.current.Store(fresh)
+	rt.rebuildWildcards(fresh)
+	delete(rt.draining, aHostname)
+} // set()
+
+/* _EoF_ */
@@ -0,0 +1,131 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRouteTableBasics(t *testing.T) {
+	rt := newRouteTable()
+	if nil != rt.lookup(`example.org`) {
+		t.Error(`lookup() on an empty table found a route`)
+	}
+
+	route := &tRoute{}
+	rt.set(`example.org`, route)
+	if route != rt.lookup(`example.org`) {
+		t.Error(`lookup() missed an added route`)
+	}
+
+	wild := &tRoute{}
+	rt.set(`*.example.org`, wild)
+	if wild != rt.lookup(`www.example.org`) {
+		t.Error(`lookup() missed the wildcard route`)
+	}
+	if route != rt.lookup(`example.org`) {
+		t.Error(`wildcard shadowed the exact route`)
+	}
+
+	rt.remove(`example.org`, 0)
+	if nil != rt.lookup(`example.org`) {
+		t.Error(`lookup() found a removed route`)
+	}
+} // TestRouteTableBasics()
+
+func TestRouteTableDraining(t *testing.T) {
+	rt := newRouteTable()
+	route := &tRoute{}
+	rt.set(`example.org`, route)
+
+	// with a drain period the removed route stays reachable:
+	rt.remove(`example.org`, 50*time.Millisecond)
+	if route != rt.lookup(`example.org`) {
+		t.Error(`lookup() dropped a draining route early`)
+	}
+
+	// re-adding the host clears the draining entry:
+	fresh := &tRoute{}
+	rt.set(`example.org`, fresh)
+	if fresh != rt.lookup(`example.org`) {
+		t.Error(`lookup() served the stale draining route`)
+	}
+
+	// past the deadline the drained route is gone:
+	rt.remove(`example.org`, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if nil != rt.lookup(`example.org`) {
+		t.Error(`lookup() served a route past its drain deadline`)
+	}
+
+	// `replaceAll()` drains the hosts missing from the new set:
+	rt.set(`old.example.org`, route)
+	rt.replaceAll(map[string]*tRoute{`new.example.org`: fresh},
+		50*time.Millisecond)
+	if route != rt.lookup(`old.example.org`) {
+		t.Error(`replaceAll() cut off the dropped host immediately`)
+	}
+	if fresh != rt.lookup(`new.example.org`) {
+		t.Error(`replaceAll() lost the new host`)
+	}
+} // TestRouteTableDraining()
+
+// `TestRouteTableConcurrency` hammers the table from parallel
+// readers and writers; run with `-race` this guards against the
+// plain-map mutation regression the table was introduced to fix.
+func TestRouteTableConcurrency(t *testing.T) {
+	rt := newRouteTable()
+	rt.set(`keep.example.org`, &tRoute{})
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for worker := 0; 4 > worker; worker++ {
+		wg.Add(1)
+		go func() { // readers on the hot path
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					if nil == rt.lookup(`keep.example.org`) {
+						t.Error(`lookup() lost a stable route`)
+						return
+					}
+					_ = rt.lookup(`flap.example.org`)
+					_ = rt.all()
+				}
+			}
+		}()
+	}
+	for worker := 0; 2 > worker; worker++ {
+		wg.Add(1)
+		go func(aID int) { // writers mutating the table
+			defer wg.Done()
+			hostname := `w` + strconv.Itoa(aID) + `.example.org`
+			for round := 0; 100 > round; round++ {
+				rt.set(`flap.example.org`, &tRoute{})
+				rt.set(hostname, &tRoute{})
+				rt.remove(`flap.example.org`, time.Millisecond)
+				rt.replaceAll(map[string]*tRoute{
+					`keep.example.org`: rt.lookup(`keep.example.org`),
+					hostname:           &tRoute{},
+				}, time.Millisecond)
+			}
+		}(worker)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+} // TestRouteTableConcurrency()
+
+/* _EoF_ */
@@ -0,0 +1,196 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// `TScheduleConfig` activates or deactivates a virtual host on a
+	// recurring schedule, so planned maintenance windows and timed
+	// launches don't require manual intervention at odd hours.
+	TScheduleConfig struct {
+		// Windows lists cron-like expressions of five fields
+		// (`minute hour day month weekday`), each supporting `*`,
+		// comma lists, `a-b` ranges, and `/step` suffixes.
+		Windows []string `json:"windows"`
+
+		// Mode decides what a matching window means: `offline`
+		// (the default) answers with `503` while a window matches
+		// (planned maintenance), `online` serves the host only
+		// while a window matches (timed availability).
+		Mode string `json:"mode,omitempty"`
+	}
+
+	// `tCronExpr` is one parsed cron expression; each field is a
+	// bitset of the accepted values.
+	tCronExpr struct {
+		minute, hour, dom, month, dow uint64
+		domStar, dowStar              bool
+	}
+
+	// `tSchedule` is a host's parsed activation schedule.
+	tSchedule struct {
+		windows []*tCronExpr
+		online  bool // windows list uptime instead of downtime
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newSchedule()` parses `aConfig`'s cron expressions.
+//
+// Parameters:
+//   - `aConfig`: The schedule settings to parse.
+//
+// Returns:
+//   - `*tSchedule`: The parsed schedule.
+//   - `error`: The first invalid cron expression encountered.
+func newSchedule(aConfig *TScheduleConfig) (*tSchedule, error) {
+	schedule := &tSchedule{online: `online` == aConfig.Mode}
+	for _, spec := range aConfig.Windows {
+		expr, err := parseCron(spec)
+		if nil != err {
+			return nil, err
+		}
+		schedule.windows = append(schedule.windows, expr)
+	}
+
+	return schedule, nil
+} // newSchedule()
+
+// --------------------------------------------------------------------------
+// tSchedule methods:
+
+// `active()` reports whether the host should serve at `aTime`.
+func (sc *tSchedule) active(aTime time.Time) bool {
+	matched := false
+	for _, expr := range sc.windows {
+		if expr.matches(aTime) {
+			matched = true
+			break
+		}
+	}
+
+	return matched == sc.online
+} // active()
+
+// --------------------------------------------------------------------------
+// tCronExpr methods:
+
+// `matches()` reports whether `aTime` falls into this expression,
+// using the conventional cron semantics: if both the day-of-month
+// and the weekday field are restricted, either one matching is
+// enough.
+func (ce *tCronExpr) matches(aTime time.Time) bool {
+	if 0 == (ce.minute & (1 << uint(aTime.Minute()))) {
+		return false
+	}
+	if 0 == (ce.hour & (1 << uint(aTime.Hour()))) {
+		return false
+	}
+	if 0 == (ce.month & (1 << uint(aTime.Month()))) {
+		return false
+	}
+
+	domHit := 0 != (ce.dom & (1 << uint(aTime.Day())))
+	dowHit := 0 != (ce.dow & (1 << uint(aTime.Weekday())))
+	if !ce.domStar && !ce.dowStar {
+		return domHit || dowHit
+	}
+
+	return domHit && dowHit
+} // matches()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `parseCron()` parses the five-field cron expression `aSpec`.
+func parseCron(aSpec string) (*tCronExpr, error) {
+	fields := strings.Fields(aSpec)
+	if 5 != len(fields) {
+		return nil, fmt.Errorf("cron %q: need 5 fields, got %d",
+			aSpec, len(fields))
+	}
+
+	expr := &tCronExpr{
+		domStar: `*` == fields[2],
+		dowStar: `*` == fields[4],
+	}
+	var err error
+	if expr.minute, err = parseCronField(fields[0], 0, 59); nil != err {
+		return nil, fmt.Errorf("cron %q: minute: %w", aSpec, err)
+	}
+	if expr.hour, err = parseCronField(fields[1], 0, 23); nil != err {
+		return nil, fmt.Errorf("cron %q: hour: %w", aSpec, err)
+	}
+	if expr.dom, err = parseCronField(fields[2], 1, 31); nil != err {
+		return nil, fmt.Errorf("cron %q: day: %w", aSpec, err)
+	}
+	if expr.month, err = parseCronField(fields[3], 1, 12); nil != err {
+		return nil, fmt.Errorf("cron %q: month: %w", aSpec, err)
+	}
+	if expr.dow, err = parseCronField(fields[4], 0, 7); nil != err {
+		return nil, fmt.Errorf("cron %q: weekday: %w", aSpec, err)
+	}
+	if 0 != (expr.dow & (1 << 7)) { // both 0 and 7 mean Sunday
+		expr.dow |= 1
+	}
+
+	return expr, nil
+} // parseCron()
+
+// `parseCronField()` parses one cron field into a bitset of the
+// accepted values between `aMin` and `aMax`.
+func parseCronField(aField string, aMin, aMax int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(aField, `,`) {
+		step := 1
+		if body, suffix, ok := strings.Cut(part, `/`); ok {
+			parsed, err := strconv.Atoi(suffix)
+			if (nil != err) || (0 >= parsed) {
+				return 0, fmt.Errorf("invalid step %q", suffix)
+			}
+			part, step = body, parsed
+		}
+
+		lo, hi := aMin, aMax
+		if `*` != part {
+			var err error
+			if from, to, ok := strings.Cut(part, `-`); ok {
+				if lo, err = strconv.Atoi(from); nil == err {
+					hi, err = strconv.Atoi(to)
+				}
+			} else {
+				if lo, err = strconv.Atoi(part); nil == err {
+					hi = lo
+				}
+			}
+			if nil != err {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+		}
+		if (lo < aMin) || (hi > aMax) || (lo > hi) {
+			return 0, fmt.Errorf("value %q out of range %d-%d",
+				part, aMin, aMax)
+		}
+		for value := lo; value <= hi; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+} // parseCronField()
+
+/* _EoF_ */
@@ -0,0 +1,84 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronMatches(t *testing.T) {
+	// Tuesday 2026-09-01 03:30
+	when := time.Date(2026, 9, 1, 3, 30, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		spec string
+		want bool
+	}{
+		{`* * * * *`, true},
+		{`30 3 * * *`, true},
+		{`30 3 * * 2`, true},
+		{`30 3 * * 0`, false},
+		{`*/15 * * * *`, true},
+		{`0-29 * * * *`, false},
+		{`* 2-4 * 9 *`, true},
+		{`* * 1 * 0`, true}, // dom OR dow semantics
+		{`* * 2 * 0`, false},
+	} {
+		expr, err := parseCron(tc.spec)
+		if nil != err {
+			t.Fatalf("parseCron(%q) error = %v", tc.spec, err)
+		}
+		if got := expr.matches(when); tc.want != got {
+			t.Errorf("matches(%q) = %v, want %v", tc.spec, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{
+		`* * * *`, `61 * * * *`, `* 24 * * *`, `a * * * *`, `*/0 * * * *`,
+	} {
+		if _, err := parseCron(spec); nil == err {
+			t.Errorf("parseCron(%q) expected an error", spec)
+		}
+	}
+} // TestCronMatches()
+
+func TestScheduleActive(t *testing.T) {
+	// Sunday 2026-09-06 04:15
+	when := time.Date(2026, 9, 6, 4, 15, 0, 0, time.UTC)
+
+	offline, err := newSchedule(&TScheduleConfig{
+		Windows: []string{`* 4-5 * * 0`}, // Sunday early morning
+	})
+	if nil != err {
+		t.Fatalf(`newSchedule() error = %v`, err)
+	}
+	if offline.active(when) {
+		t.Error(`active() = true inside an offline window`)
+	}
+	if !offline.active(when.Add(2 * time.Hour)) {
+		t.Error(`active() = false outside an offline window`)
+	}
+
+	online, err := newSchedule(&TScheduleConfig{
+		Windows: []string{`* 4-5 * * 0`},
+		Mode:    `online`,
+	})
+	if nil != err {
+		t.Fatalf(`newSchedule() error = %v`, err)
+	}
+	if !online.active(when) {
+		t.Error(`active() = false inside an online window`)
+	}
+	if online.active(when.Add(2 * time.Hour)) {
+		t.Error(`active() = true outside an online window`)
+	}
+} // TestScheduleActive()
+
+/* _EoF_ */
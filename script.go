@@ -0,0 +1,435 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// This file implements the per-route scripting stage: a small
+// rule-based language letting a host inspect and modify requests and
+// responses or pick a different backend without recompiling the
+// proxy.  True to the rest of this codebase it's a purpose-built
+// interpreter instead of an embedded Lua or Starlark dependency -
+// the handful of verbs below covers the usual "block this, rewrite
+// that" cases while staying trivially auditable.
+//
+// A script is a plain text file, one rule per line (`#` comments):
+//
+//	<stage> [when <cond> [and <cond>]...] => <action> [args...]
+//
+// with `<stage>` being `request` or `response`.  A condition is
+// `[not] <subject> <op> <value>` where the subject is `method`,
+// `path`, `host`, `query`, `header <Name>`, or (responses only)
+// `status`, and the operator is `is` (exact), `prefix`, `has`
+// (substring), or `matches` (regular expression); a bare subject
+// without operator (e.g. `not header X-Admin`) just tests for a
+// non-empty value.  Actions:
+//
+//	deny <status> [message...]         reject the request
+//	redirect <status> <url>            answer with a redirect
+//	route <target-url>                 proxy to a different backend
+//	set-path <path>                    rewrite the request path
+//	set-header <name> [value...]       set a request/response header
+//	del-header <name>                  drop a request/response header
+//
+// `deny`, `redirect`, `route`, and `set-path` are request-only.
+
+type (
+	// `tScriptCond` is one compiled rule condition.
+	tScriptCond struct {
+		subject string         // method|path|host|query|header|status
+		header  string         // header name (subject `header` only)
+		op      string         // is|prefix|has|matches
+		value   string         // comparison value
+		rx      *regexp.Regexp // compiled value (op `matches` only)
+		negate  bool           // `not` prefix
+	}
+
+	// `tScriptAction` is one compiled rule action.
+	tScriptAction struct {
+		verb   string   // deny|redirect|route|set-path|set-header|del-header
+		status int      // HTTP status (`deny`/`redirect`)
+		name   string   // header name
+		value  string   // header value, path, message, or URL
+		target *url.URL // parsed backend (`route` only)
+	}
+
+	// `tScriptRule` is one compiled script line.
+	tScriptRule struct {
+		conds  []tScriptCond
+		action tScriptAction
+	}
+
+	// `tScript` is a compiled per-route script; it's built at route
+	// construction and read-only afterwards, so no locking is needed.
+	tScript struct {
+		request  []tScriptRule
+		response []tScriptRule
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `loadScript()` reads and compiles the script file `aFilename`.
+//
+// Parameters:
+//   - `aFilename`: The script file to load.
+//
+// Returns:
+//   - `*tScript`: The compiled script.
+//   - `error`: A read or syntax problem (with its line number).
+func loadScript(aFilename string) (*tScript, error) {
+	data, err := os.ReadFile(aFilename)
+	if nil != err {
+		return nil, err
+	}
+
+	script := &tScript{}
+	for num, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if ("" == line) || strings.HasPrefix(line, `#`) {
+			continue
+		}
+		stage, rule, err := parseScriptRule(line)
+		if nil != err {
+			return nil, fmt.Errorf("%s:%d: %w", aFilename, num+1, err)
+		}
+		if `request` == stage {
+			script.request = append(script.request, rule)
+		} else {
+			script.response = append(script.response, rule)
+		}
+	}
+
+	return script, nil
+} // loadScript()
+
+// --------------------------------------------------------------------------
+// tScript methods:
+
+// `modifiesResponses()` reports whether the script has any
+// response-stage rules.
+func (sc *tScript) modifiesResponses() bool {
+	return 0 < len(sc.response)
+} // modifiesResponses()
+
+// `routes()` reports whether the script contains `route` actions.
+func (sc *tScript) routes() bool {
+	for _, rule := range sc.request {
+		if `route` == rule.action.verb {
+			return true
+		}
+	}
+
+	return false
+} // routes()
+
+// `routeTarget()` returns the backend of the first matching `route`
+// rule, or `nil` to keep the configured target.
+func (sc *tScript) routeTarget(aRequest *http.Request) *url.URL {
+	for _, rule := range sc.request {
+		if (`route` == rule.action.verb) && rule.matches(aRequest, nil) {
+			return rule.action.target
+		}
+	}
+
+	return nil
+} // routeTarget()
+
+// `runRequest()` applies the request-stage rules to `aRequest`,
+// reporting whether the response was already written (`deny` resp.
+// `redirect`); `route` rules are handled by the proxy's director.
+func (sc *tScript) runRequest(aWriter http.ResponseWriter, aRequest *http.Request) bool {
+	for _, rule := range sc.request {
+		if !rule.matches(aRequest, nil) {
+			continue
+		}
+		switch rule.action.verb {
+		case `deny`:
+			http.Error(aWriter, rule.action.value, rule.action.status)
+			return true
+
+		case `redirect`:
+			http.Redirect(aWriter, aRequest,
+				rule.action.value, rule.action.status)
+			return true
+
+		case `set-path`:
+			aRequest.URL.Path = rule.action.value
+
+		case `set-header`:
+			aRequest.Header.Set(rule.action.name, rule.action.value)
+
+		case `del-header`:
+			aRequest.Header.Del(rule.action.name)
+		}
+	}
+
+	return false
+} // runRequest()
+
+// `runResponse()` applies the response-stage rules to `aResponse`.
+func (sc *tScript) runResponse(aResponse *http.Response) {
+	for _, rule := range sc.response {
+		if !rule.matches(aResponse.Request, aResponse) {
+			continue
+		}
+		switch rule.action.verb {
+		case `set-header`:
+			aResponse.Header.Set(rule.action.name, rule.action.value)
+
+		case `del-header`:
+			aResponse.Header.Del(rule.action.name)
+		}
+	}
+} // runResponse()
+
+// --------------------------------------------------------------------------
+// tScriptRule methods:
+
+// `matches()` reports whether all conditions of the rule hold for
+// the given request resp. response (`aResponse` is `nil` during the
+// request stage).
+func (sr *tScriptRule) matches(aRequest *http.Request, aResponse *http.Response) bool {
+	for _, cond := range sr.conds {
+		var subject string
+		switch cond.subject {
+		case `method`:
+			subject = aRequest.Method
+		case `path`:
+			subject = aRequest.URL.Path
+		case `host`:
+			subject = normalizeHostname(hostOnly(aRequest.Host))
+		case `query`:
+			subject = aRequest.URL.RawQuery
+		case `status`:
+			subject = strconv.Itoa(aResponse.StatusCode)
+		case `header`:
+			if nil != aResponse {
+				subject = aResponse.Header.Get(cond.header)
+			} else {
+				subject = aRequest.Header.Get(cond.header)
+			}
+		}
+		if !cond.eval(subject) {
+			return false
+		}
+	}
+
+	return true
+} // matches()
+
+// `eval()` applies the condition's operator to `aSubject`.
+func (sc *tScriptCond) eval(aSubject string) bool {
+	var holds bool
+	switch sc.op {
+	case ``: // bare condition: any non-empty value
+		holds = "" != aSubject
+	case `is`:
+		holds = aSubject == sc.value
+	case `prefix`:
+		holds = strings.HasPrefix(aSubject, sc.value)
+	case `has`:
+		holds = strings.Contains(aSubject, sc.value)
+	case `matches`:
+		holds = sc.rx.MatchString(aSubject)
+	}
+
+	return holds != sc.negate
+} // eval()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `parseScriptAction()` compiles the action tokens of one rule.
+func parseScriptAction(aStage string, aTokens []string) (tScriptAction, error) {
+	var action tScriptAction
+	if 0 == len(aTokens) {
+		return action, fmt.Errorf("missing action")
+	}
+	action.verb = aTokens[0]
+	args := aTokens[1:]
+
+	switch action.verb {
+	case `deny`:
+		if `request` != aStage {
+			return action, fmt.Errorf("deny is request-only")
+		}
+		if 0 == len(args) {
+			return action, fmt.Errorf("deny needs a status")
+		}
+		status, err := strconv.Atoi(args[0])
+		if (nil != err) || (100 > status) || (599 < status) {
+			return action, fmt.Errorf("invalid deny status %q", args[0])
+		}
+		action.status = status
+		action.value = strings.Join(args[1:], ` `)
+		if "" == action.value {
+			action.value = http.StatusText(status)
+		}
+
+	case `redirect`:
+		if `request` != aStage {
+			return action, fmt.Errorf("redirect is request-only")
+		}
+		if 2 != len(args) {
+			return action, fmt.Errorf("redirect needs a status and URL")
+		}
+		status, err := strconv.Atoi(args[0])
+		if (nil != err) || (300 > status) || (399 < status) {
+			return action, fmt.Errorf("invalid redirect status %q", args[0])
+		}
+		action.status, action.value = status, args[1]
+
+	case `route`:
+		if `request` != aStage {
+			return action, fmt.Errorf("route is request-only")
+		}
+		if 1 != len(args) {
+			return action, fmt.Errorf("route needs a target URL")
+		}
+		target, err := url.Parse(args[0])
+		if (nil != err) || ("" == target.Scheme) || ("" == target.Host) {
+			return action, fmt.Errorf("invalid route target %q", args[0])
+		}
+		action.target = target
+
+	case `set-path`:
+		if `request` != aStage {
+			return action, fmt.Errorf("set-path is request-only")
+		}
+		if (1 != len(args)) || !strings.HasPrefix(args[0], `/`) {
+			return action, fmt.Errorf("set-path needs an absolute path")
+		}
+		action.value = args[0]
+
+	case `set-header`:
+		if 1 > len(args) {
+			return action, fmt.Errorf("set-header needs a name")
+		}
+		action.name = args[0]
+		action.value = strings.Join(args[1:], ` `)
+
+	case `del-header`:
+		if 1 != len(args) {
+			return action, fmt.Errorf("del-header needs a name")
+		}
+		action.name = args[0]
+
+	default:
+		return action, fmt.Errorf("unknown action %q", action.verb)
+	}
+
+	return action, nil
+} // parseScriptAction()
+
+// `parseScriptCond()` compiles one condition, consuming its tokens.
+func parseScriptCond(aStage string, aTokens []string) (tScriptCond, []string, error) {
+	var cond tScriptCond
+	if (0 < len(aTokens)) && (`not` == aTokens[0]) {
+		cond.negate = true
+		aTokens = aTokens[1:]
+	}
+	if 0 == len(aTokens) {
+		return cond, nil, fmt.Errorf("missing condition subject")
+	}
+	cond.subject = aTokens[0]
+	aTokens = aTokens[1:]
+
+	switch cond.subject {
+	case `method`, `path`, `host`, `query`: // request properties
+
+	case `status`:
+		if `response` != aStage {
+			return cond, nil, fmt.Errorf("status is response-only")
+		}
+
+	case `header`:
+		if 0 == len(aTokens) {
+			return cond, nil, fmt.Errorf("header condition needs a name")
+		}
+		cond.header = aTokens[0]
+		aTokens = aTokens[1:]
+
+	default:
+		return cond, nil, fmt.Errorf("unknown subject %q", cond.subject)
+	}
+
+	if (0 == len(aTokens)) || (`and` == aTokens[0]) || (`=>` == aTokens[0]) {
+		return cond, aTokens, nil // bare condition: non-empty value
+	}
+	if 2 > len(aTokens) {
+		return cond, nil, fmt.Errorf("incomplete %q condition", cond.subject)
+	}
+	cond.op, cond.value = aTokens[0], aTokens[1]
+	switch cond.op {
+	case `is`, `prefix`, `has`: // plain string comparisons
+
+	case `matches`:
+		rx, err := regexp.Compile(cond.value)
+		if nil != err {
+			return cond, nil, fmt.Errorf("invalid pattern %q: %w",
+				cond.value, err)
+		}
+		cond.rx = rx
+
+	default:
+		return cond, nil, fmt.Errorf("unknown operator %q", cond.op)
+	}
+
+	return cond, aTokens[2:], nil
+} // parseScriptCond()
+
+// `parseScriptRule()` compiles one script line.
+func parseScriptRule(aLine string) (string, tScriptRule, error) {
+	var rule tScriptRule
+	tokens := strings.Fields(aLine)
+	stage := tokens[0]
+	if (`request` != stage) && (`response` != stage) {
+		return ``, rule, fmt.Errorf("unknown stage %q", stage)
+	}
+	tokens = tokens[1:]
+
+	if (0 < len(tokens)) && (`when` == tokens[0]) {
+		tokens = tokens[1:]
+		for {
+			cond, rest, err := parseScriptCond(stage, tokens)
+			if nil != err {
+				return ``, rule, err
+			}
+			rule.conds = append(rule.conds, cond)
+			tokens = rest
+			if (0 == len(tokens)) || (`and` != tokens[0]) {
+				break
+			}
+			tokens = tokens[1:]
+		}
+	}
+	if (0 == len(tokens)) || (`=>` != tokens[0]) {
+		return ``, rule, fmt.Errorf("missing `=>` before the action")
+	}
+
+	action, err := parseScriptAction(stage, tokens[1:])
+	if nil != err {
+		return ``, rule, err
+	}
+	rule.action = action
+
+	return stage, rule, nil
+} // parseScriptRule()
+
+/* _EoF_ */
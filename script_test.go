@@ -0,0 +1,174 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// `writeScript()` stores `aRules` as a script file for the tests.
+func writeScript(t *testing.T, aRules string) string {
+	filename := filepath.Join(t.TempDir(), `rules.txt`)
+	if err := os.WriteFile(filename, []byte(aRules), 0o600); nil != err {
+		t.Fatalf(`writing script failed: %v`, err)
+	}
+
+	return filename
+} // writeScript()
+
+func TestScriptParsing(t *testing.T) {
+	script, err := loadScript(writeScript(t, `
+# block bots, tag the rest:
+request when header User-Agent has badbot => deny 403 go away
+request when path prefix /admin and not header X-Admin => deny 403
+request => set-header X-Proxied-By reprox
+response when status is 500 => set-header X-Origin backend
+response => del-header Server
+`))
+	if nil != err {
+		t.Fatalf(`loadScript() failed: %v`, err)
+	}
+	if (3 != len(script.request)) || (2 != len(script.response)) {
+		t.Errorf(`loadScript() compiled %d+%d rules, want 3+2`,
+			len(script.request), len(script.response))
+	}
+
+	for _, broken := range []string{
+		`reject => deny 403`,                       // unknown stage
+		`request when path near /x => deny 403`,    // unknown operator
+		`request when status is 500 => deny 403`,   // response-only subject
+		`response => deny 403`,                     // request-only action
+		`request when path matches ([ => deny 403`, // bad pattern
+		`request set-header X-Foo bar`,             // missing `=>`
+		`request => route not-a-url`,               // unusable target
+	} {
+		if _, err = loadScript(writeScript(t, broken)); nil == err {
+			t.Errorf(`loadScript() accepted %q`, broken)
+		}
+	}
+} // TestScriptParsing()
+
+func TestScriptRequestRules(t *testing.T) {
+	script, err := loadScript(writeScript(t, `
+request when path prefix /admin and not header X-Admin => deny 403 admins only
+request when path is /legacy => redirect 301 /modern
+request when method is POST => set-header X-Was-Post yes
+request when path prefix /old => set-path /new
+`))
+	if nil != err {
+		t.Fatalf(`loadScript() failed: %v`, err)
+	}
+
+	run := func(aMethod, aPath string, aHeader map[string]string) (
+		*httptest.ResponseRecorder, *http.Request) {
+		req := httptest.NewRequest(aMethod, `http://www.mwat.de`+aPath, nil)
+		for name, value := range aHeader {
+			req.Header.Set(name, value)
+		}
+		rec := httptest.NewRecorder()
+		script.runRequest(rec, req)
+		return rec, req
+	}
+
+	if rec, _ := run(http.MethodGet, `/admin/users`, nil); http.StatusForbidden != rec.Code {
+		t.Errorf(`unauthenticated /admin status = %d, want 403`, rec.Code)
+	}
+	if rec, _ := run(http.MethodGet, `/admin/users`,
+		map[string]string{`X-Admin`: `1`}); http.StatusForbidden == rec.Code {
+		t.Error(`authenticated /admin was denied`)
+	}
+	rec, _ := run(http.MethodGet, `/legacy`, nil)
+	if (http.StatusMovedPermanently != rec.Code) ||
+		(`/modern` != rec.Header().Get(`Location`)) {
+		t.Errorf(`/legacy answered (%d, %q)`,
+			rec.Code, rec.Header().Get(`Location`))
+	}
+	if _, req := run(http.MethodPost, `/form`, nil); `yes` != req.Header.Get(`X-Was-Post`) {
+		t.Error(`POST marker header not set`)
+	}
+	if _, req := run(http.MethodGet, `/old/page`, nil); `/new` != req.URL.Path {
+		t.Errorf(`set-path left %q`, req.URL.Path)
+	}
+} // TestScriptRequestRules()
+
+func TestScriptResponseRules(t *testing.T) {
+	script, err := loadScript(writeScript(t, `
+response when status is 500 => set-header X-Origin backend
+response => del-header Server
+`))
+	if nil != err {
+		t.Fatalf(`loadScript() failed: %v`, err)
+	}
+
+	response := &http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Header:     http.Header{`Server`: []string{`leaky/1.0`}},
+		Request:    httptest.NewRequest(http.MethodGet, `http://www.mwat.de/`, nil),
+	}
+	script.runResponse(response)
+	if `backend` != response.Header.Get(`X-Origin`) {
+		t.Error(`status condition didn't set the header`)
+	}
+	if "" != response.Header.Get(`Server`) {
+		t.Error(`Server header survived del-header`)
+	}
+
+	response.StatusCode = http.StatusOK
+	response.Header.Del(`X-Origin`)
+	script.runResponse(response)
+	if "" != response.Header.Get(`X-Origin`) {
+		t.Error(`status condition matched a 200 response`)
+	}
+} // TestScriptResponseRules()
+
+func TestScriptRouting(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`primary`))
+		}))
+	defer primary.Close()
+	special := httptest.NewServer(http.HandlerFunc(
+		func(aWriter http.ResponseWriter, aRequest *http.Request) {
+			_, _ = aWriter.Write([]byte(`special`))
+		}))
+	defer special.Close()
+
+	script := writeScript(t,
+		"request when path prefix /api => route "+special.URL+"\n")
+	ph, err := NewProxyHandler(WithConfig(&TConfig{
+		Hosts: map[string]*THostConfig{
+			`www.mwat.de`: {Target: primary.URL, Script: script},
+		}}))
+	if nil != err {
+		t.Fatalf(`NewProxyHandler() failed: %v`, err)
+	}
+
+	fetch := func(aPath string) string {
+		req := httptest.NewRequest(http.MethodGet,
+			`http://www.mwat.de`+aPath, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		body, _ := io.ReadAll(rec.Result().Body)
+		return string(body)
+	}
+
+	if body := fetch(`/page`); `primary` != body {
+		t.Errorf(`/page answered by %q, want primary`, body)
+	}
+	if body := fetch(`/api/v1`); `special` != body {
+		t.Errorf(`/api/v1 answered by %q, want special`, body)
+	}
+} // TestScriptRouting()
+
+/* _EoF_ */
@@ -0,0 +1,154 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// `TShedConfig` tunes the adaptive load shedding protecting the
+	// proxy itself from overload.
+	TShedConfig struct {
+		// MaxActive sheds once this many requests are in flight
+		// (0: no in-flight limit).
+		MaxActive int `json:"maxActive,omitempty"`
+
+		// MaxGoroutines sheds once the process runs this many
+		// goroutines (0: no goroutine limit).
+		MaxGoroutines int `json:"maxGoroutines,omitempty"`
+
+		// MaxHeapMB sheds once the heap grows beyond this many
+		// megabytes (0: no memory limit).
+		MaxHeapMB int `json:"maxHeapMB,omitempty"`
+
+		// RetryAfterSec is sent with the 503 answers (default 10).
+		RetryAfterSec int `json:"retryAfterSec,omitempty"`
+	}
+
+	// `tLoadShedder` rejects requests with `503` while the proxy
+	// itself is saturated, so the remaining traffic keeps its
+	// latency instead of everything degrading together.
+	//
+	// Shedding is graded by the routes' priority classes: low
+	// priority traffic goes first once a limit is reached, normal
+	// priority follows under severe overload, and high priority
+	// requests are never shed.
+	//
+	// The (comparatively expensive) runtime checks run at most a
+	// few times per second; in between the cached verdict is used.
+	tLoadShedder struct {
+		mtx       sync.Mutex
+		conf      *TShedConfig
+		metrics   *TMetrics
+		nextCheck time.Time
+		level     atomic.Int32 // current tShedLevel
+	}
+
+	// `tShedLevel` grades the current overload.
+	tShedLevel = int32
+)
+
+// the overload grades:
+const (
+	shedNone   tShedLevel = iota // all requests pass
+	shedLow                      // shed low priority traffic
+	shedNormal                   // shed normal priority too
+)
+
+// `shedSevereFactor` is how far beyond a limit the severe grade
+// (shedding normal priority traffic as well) begins.
+const shedSevereFactor = 1.25
+
+// `shedSampleInterval` is how long one saturation verdict is reused.
+const shedSampleInterval = 250 * time.Millisecond
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newLoadShedder()` creates a shedder enforcing `aConfig`.
+func newLoadShedder(aConfig *TShedConfig, aMetrics *TMetrics) *tLoadShedder {
+	if 0 >= aConfig.RetryAfterSec {
+		aConfig.RetryAfterSec = 10
+	}
+
+	return &tLoadShedder{
+		conf:    aConfig,
+		metrics: aMetrics,
+	}
+} // newLoadShedder()
+
+// --------------------------------------------------------------------------
+// tLoadShedder methods:
+
+// `retryAfter()` returns the `Retry-After` value for shed answers.
+func (ls *tLoadShedder) retryAfter() string {
+	return strconv.Itoa(ls.conf.RetryAfterSec)
+} // retryAfter()
+
+// `shed()` reports whether a request of `aPriority` should be
+// rejected under the current load.
+func (ls *tLoadShedder) shed(aPriority TPriority) bool {
+	now := time.Now()
+
+	ls.mtx.Lock()
+	if now.After(ls.nextCheck) {
+		ls.nextCheck = now.Add(shedSampleInterval)
+		ls.mtx.Unlock()
+		ls.sample()
+	} else {
+		ls.mtx.Unlock()
+	}
+
+	switch ls.level.Load() {
+	case shedLow:
+		return PrioLow == aPriority
+	case shedNormal:
+		return PrioHigh != aPriority
+	}
+
+	return false
+} // shed()
+
+// `grade()` turns one measurement into its overload grade.
+func grade(aValue, aLimit float64) tShedLevel {
+	if (0 >= aLimit) || (aValue < aLimit) {
+		return shedNone
+	}
+	if aValue >= (aLimit * shedSevereFactor) {
+		return shedNormal
+	}
+
+	return shedLow
+} // grade()
+
+// `sample()` re-evaluates the saturation verdict.
+func (ls *tLoadShedder) sample() {
+	level := grade(float64(ls.metrics.ActiveTotal()),
+		float64(ls.conf.MaxActive))
+	if goLevel := grade(float64(runtime.NumGoroutine()),
+		float64(ls.conf.MaxGoroutines)); goLevel > level {
+		level = goLevel
+	}
+	if (shedNormal > level) && (0 < ls.conf.MaxHeapMB) {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if heapLevel := grade(float64(stats.HeapAlloc),
+			float64(uint64(ls.conf.MaxHeapMB)<<20)); heapLevel > level {
+			level = heapLevel
+		}
+	}
+	ls.level.Store(level)
+} // sample()
+
+/* _EoF_ */
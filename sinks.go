@@ -0,0 +1,231 @@
+/*
+Copyright © 2024  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `tLogSink` is the destination of one log stream.
+	//
+	// Besides plain files the logger supports syslog (local or
+	// remote RFC 5424) and journald targets, selected by the log
+	// destination string in the configuration:
+	//
+	//	/var/log/reprox/access.log   a regular file
+	//	syslog:                      the local syslog daemon
+	//	syslog://udp/loghost:514     a remote RFC 5424 receiver
+	//	journald:                    the systemd journal
+	tLogSink interface {
+		// `write()` appends `aLine` to the log stream.
+		write(aLine string)
+
+		// `close()` releases the sink's resources.
+		close() error
+
+		// `reopen()` re-establishes the sink (no-op for sockets).
+		reopen() error
+	}
+
+	// `tSyslogSink` sends log lines to a syslog daemon, either via
+	// the local `/dev/log` socket or as RFC 5424 datagrams/streams
+	// to a remote receiver.
+	tSyslogSink struct {
+		mtx      sync.Mutex
+		conn     net.Conn
+		network  string // "unixgram", "udp", or "tcp"
+		addr     string
+		hostname string
+	}
+
+	// `tJournalSink` sends log lines to the local systemd journal.
+	tJournalSink struct {
+		mtx  sync.Mutex
+		conn net.Conn
+	}
+)
+
+// `journalSocket` is the systemd journal's native protocol socket.
+const journalSocket = `/run/systemd/journal/socket`
+
+// --------------------------------------------------------------------------
+// tSyslogSink methods:
+
+// `newSyslogSink()` connects to the syslog target given by `aSpec`
+// (either plain `syslog:` or `syslog://<udp|tcp>/<host:port>`).
+func newSyslogSink(aSpec string) (*tSyslogSink, error) {
+	sink := &tSyslogSink{network: `unixgram`, addr: `/dev/log`}
+	if hostname, err := os.Hostname(); nil == err {
+		sink.hostname = hostname
+	} else {
+		sink.hostname = `-`
+	}
+
+	spec := strings.TrimPrefix(aSpec, `syslog:`)
+	if rest, ok := strings.CutPrefix(spec, `//`); ok && ("" != rest) {
+		network, addr, found := strings.Cut(rest, `/`)
+		if !found || (("udp" != network) && ("tcp" != network)) {
+			return nil, fmt.Errorf("invalid syslog target %q", aSpec)
+		}
+		sink.network, sink.addr = network, addr
+	}
+
+	if err := sink.reopen(); nil != err {
+		return nil, err
+	}
+
+	return sink, nil
+} // newSyslogSink()
+
+// `close()` closes the syslog connection.
+func (ss *tSyslogSink) close() error {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+
+	if nil == ss.conn {
+		return nil
+	}
+	err := ss.conn.Close()
+	ss.conn = nil
+
+	return err
+} // close()
+
+// `reopen()` (re-)establishes the syslog connection.
+func (ss *tSyslogSink) reopen() error {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+
+	if nil != ss.conn {
+		_ = ss.conn.Close()
+	}
+	conn, err := net.Dial(ss.network, ss.addr)
+	if nil != err {
+		return fmt.Errorf("connecting syslog %s/%s: %w",
+			ss.network, ss.addr, err)
+	}
+	ss.conn = conn
+
+	return nil
+} // reopen()
+
+// `write()` sends `aLine` as one syslog message (facility `daemon`,
+// severity `info`).
+func (ss *tSyslogSink) write(aLine string) {
+	ss.mtx.Lock()
+	defer ss.mtx.Unlock()
+	if nil == ss.conn {
+		return
+	}
+
+	var msg string
+	if `unixgram` == ss.network {
+		// traditional BSD format for the local daemon:
+		msg = fmt.Sprintf("<30>%s reprox[%d]: %s",
+			time.Now().Format(time.Stamp), os.Getpid(), aLine)
+	} else {
+		// RFC 5424 for remote receivers:
+		msg = fmt.Sprintf("<30>1 %s %s reprox %d - - %s",
+			time.Now().Format(time.RFC3339), ss.hostname,
+			os.Getpid(), aLine)
+	}
+	if _, err := fmt.Fprint(ss.conn, msg); nil != err {
+		// one transparent reconnection attempt:
+		if conn, err := net.Dial(ss.network, ss.addr); nil == err {
+			ss.conn = conn
+			_, _ = fmt.Fprint(ss.conn, msg)
+		}
+	}
+} // write()
+
+// --------------------------------------------------------------------------
+// tJournalSink methods:
+
+// `newJournalSink()` connects to the local systemd journal.
+func newJournalSink() (*tJournalSink, error) {
+	sink := &tJournalSink{}
+	if err := sink.reopen(); nil != err {
+		return nil, err
+	}
+
+	return sink, nil
+} // newJournalSink()
+
+// `close()` closes the journal connection.
+func (js *tJournalSink) close() error {
+	js.mtx.Lock()
+	defer js.mtx.Unlock()
+
+	if nil == js.conn {
+		return nil
+	}
+	err := js.conn.Close()
+	js.conn = nil
+
+	return err
+} // close()
+
+// `reopen()` (re-)establishes the journal connection.
+func (js *tJournalSink) reopen() error {
+	js.mtx.Lock()
+	defer js.mtx.Unlock()
+
+	if nil != js.conn {
+		_ = js.conn.Close()
+	}
+	conn, err := net.Dial(`unixgram`, journalSocket)
+	if nil != err {
+		return fmt.Errorf("connecting journald: %w", err)
+	}
+	js.conn = conn
+
+	return nil
+} // reopen()
+
+// `write()` sends `aLine` as one journal entry.
+func (js *tJournalSink) write(aLine string) {
+	js.mtx.Lock()
+	defer js.mtx.Unlock()
+	if nil == js.conn {
+		return
+	}
+
+	entry := "MESSAGE=" + aLine +
+		"\nPRIORITY=6\nSYSLOG_IDENTIFIER=reprox\n"
+	_, _ = js.conn.Write([]byte(entry))
+} // write()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `newLogSink()` creates the log sink described by `aSpec`.
+func newLogSink(aSpec string, aRotation *TLogRotation) (tLogSink, error) {
+	switch {
+	case strings.HasPrefix(aSpec, `syslog:`):
+		return newSyslogSink(aSpec)
+
+	case `journald:` == aSpec:
+		return newJournalSink()
+
+	default:
+		lf := &tLogFile{name: aSpec, rotation: aRotation}
+		if err := lf.reopen(); nil != err {
+			return nil, err
+		}
+		return lf, nil
+	}
+} // newLogSink()
+
+/* _EoF_ */
@@ -0,0 +1,199 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// `TSLOConfig` defines the service level objectives reported
+	// per virtual host.
+	TSLOConfig struct {
+		// TargetPercent is the availability objective, e.g. `99.9`
+		// (the default).
+		TargetPercent float64 `json:"targetPercent,omitempty"`
+
+		// LatencyMillis is the threshold below which a request
+		// counts as fast (default 500).
+		LatencyMillis int `json:"latencyMillis,omitempty"`
+
+		// LatencyPercent is the fraction of requests that should
+		// be fast, e.g. `95` (the default).
+		LatencyPercent float64 `json:"latencyPercent,omitempty"`
+	}
+
+	// `tSLOBucket` aggregates one minute of one host's requests.
+	tSLOBucket struct {
+		total  int64
+		errors int64 // responses with status >= 500
+		slow   int64 // slower than the latency threshold
+	}
+
+	// `tSLORing` is a day's worth of per-minute buckets.
+	tSLORing struct {
+		buckets [sloRingSize]tSLOBucket
+		marks   [sloRingSize]int64 // unix minute of each bucket
+	}
+
+	// `TSLOWindow` is the compliance summary of one rolling window.
+	TSLOWindow struct {
+		Requests     int64   `json:"requests"`
+		Availability float64 `json:"availabilityPercent"`
+		FastPercent  float64 `json:"fastPercent"`
+		// BudgetLeft is the unburnt part of the error budget:
+		// 100% means no errors at all, 0% the objective is spent.
+		BudgetLeft float64 `json:"errorBudgetLeftPercent"`
+	}
+
+	// `TSLOStatus` is one host's SLO compliance report.
+	TSLOStatus struct {
+		Hour TSLOWindow `json:"lastHour"`
+		Day  TSLOWindow `json:"lastDay"`
+	}
+
+	// `TSLOTracker` keeps rolling per-minute statistics per host
+	// and computes availability and latency compliance from them.
+	TSLOTracker struct {
+		mtx   sync.Mutex
+		conf  *TSLOConfig
+		slow  time.Duration
+		hosts map[string]*tSLORing
+	}
+)
+
+// `sloRingSize` is the bucket count: one minute each, one day total.
+const sloRingSize = 24 * 60
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newSLOTracker()` creates a tracker for the objectives of
+// `aConfig`, filling in the usual defaults.
+func newSLOTracker(aConfig *TSLOConfig) *TSLOTracker {
+	if 0 >= aConfig.TargetPercent {
+		aConfig.TargetPercent = 99.9
+	}
+	if 0 >= aConfig.LatencyMillis {
+		aConfig.LatencyMillis = 500
+	}
+	if 0 >= aConfig.LatencyPercent {
+		aConfig.LatencyPercent = 95
+	}
+
+	return &TSLOTracker{
+		conf:  aConfig,
+		slow:  time.Duration(aConfig.LatencyMillis) * time.Millisecond,
+		hosts: make(map[string]*tSLORing),
+	}
+} // newSLOTracker()
+
+// --------------------------------------------------------------------------
+// TSLOTracker methods:
+
+// `Report()` returns the current per-host compliance over the last
+// hour and the last day.
+func (st *TSLOTracker) Report() map[string]TSLOStatus {
+	minute := time.Now().Unix() / 60
+
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+	report := make(map[string]TSLOStatus, len(st.hosts))
+	for hostname, ring := range st.hosts {
+		report[hostname] = TSLOStatus{
+			Hour: st.window(ring, minute, 60),
+			Day:  st.window(ring, minute, sloRingSize),
+		}
+	}
+
+	return report
+} // Report()
+
+// `record()` accounts one finished request.
+func (st *TSLOTracker) record(aHostname string, aDuration time.Duration, aStatus int) {
+	minute := time.Now().Unix() / 60
+	idx := minute % sloRingSize
+
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+	ring, ok := st.hosts[aHostname]
+	if !ok {
+		ring = &tSLORing{}
+		st.hosts[aHostname] = ring
+	}
+	if ring.marks[idx] != minute {
+		ring.buckets[idx] = tSLOBucket{}
+		ring.marks[idx] = minute
+	}
+	bucket := &ring.buckets[idx]
+	bucket.total++
+	if 500 <= aStatus {
+		bucket.errors++
+	}
+	if aDuration > st.slow {
+		bucket.slow++
+	}
+} // record()
+
+// `window()` sums a ring over the last `aMinutes` buckets; the
+// caller must hold `st.mtx`.
+func (st *TSLOTracker) window(aRing *tSLORing, aMinute int64, aMinutes int64) TSLOWindow {
+	var sum tSLOBucket
+	oldest := aMinute - aMinutes + 1
+	for idx, mark := range aRing.marks {
+		if mark >= oldest {
+			sum.total += aRing.buckets[idx].total
+			sum.errors += aRing.buckets[idx].errors
+			sum.slow += aRing.buckets[idx].slow
+		}
+	}
+
+	window := TSLOWindow{Requests: sum.total,
+		Availability: 100, FastPercent: 100, BudgetLeft: 100}
+	if 0 == sum.total {
+		return window
+	}
+	window.Availability = 100 *
+		float64(sum.total-sum.errors) / float64(sum.total)
+	window.FastPercent = 100 *
+		float64(sum.total-sum.slow) / float64(sum.total)
+	budget := 100 - st.conf.TargetPercent // allowed error percentage
+	if 0 < budget {
+		errPercent := 100 * float64(sum.errors) / float64(sum.total)
+		window.BudgetLeft = 100 * (budget - errPercent) / budget
+		if 0 > window.BudgetLeft {
+			window.BudgetLeft = 0
+		}
+	}
+
+	return window
+} // window()
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `slo()` handles `GET /api/slo` returning the per-host SLO
+// compliance report.
+func (ad *TAdmin) slo(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+	tracker := ad.handler.SLO()
+	if nil == tracker {
+		http.Error(aWriter, `no SLO objectives configured`,
+			http.StatusNotImplemented)
+		return
+	}
+	sendJSON(aWriter, tracker.Report())
+} // slo()
+
+/* _EoF_ */
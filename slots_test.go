@@ -0,0 +1,121 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSlotCutover(t *testing.T) {
+	colour := func(aName string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(
+			func(aWriter http.ResponseWriter, aRequest *http.Request) {
+				_, _ = aWriter.Write([]byte(aName))
+			}))
+	}
+	blue, green := colour(`blue`), colour(`green`)
+	defer blue.Close()
+	defer green.Close()
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		Target: blue.URL,
+		Slots: map[string]string{
+			`blue`:  blue.URL,
+			`green`: green.URL,
+		},
+		ActiveSlot: `blue`,
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+	admin, err := NewAdmin(&TAdminConfig{Token: `secret`},
+		`reprox.json`, ph, &TLogger{})
+	if nil != err {
+		t.Fatalf(`NewAdmin() error = %v`, err)
+	}
+
+	fetch := func() string {
+		req := httptest.NewRequest(http.MethodGet, `http://example.org/`, nil)
+		rec := httptest.NewRecorder()
+		ph.ServeHTTP(rec, req)
+		return rec.Body.String()
+	}
+	cutover := func(aSlot string) int {
+		req := httptest.NewRequest(http.MethodPost,
+			`http://admin/api/slots/example.org?slot=`+aSlot, nil)
+		req.Header.Set(`Authorization`, `Bearer secret`)
+		rec := httptest.NewRecorder()
+		admin.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if body := fetch(); `blue` != body {
+		t.Fatalf(`initial slot answered %q, want "blue"`, body)
+	}
+
+	if status := cutover(`green`); http.StatusOK != status {
+		t.Fatalf(`cutover status = %d, want 200`, status)
+	}
+	if body := fetch(); `green` != body {
+		t.Errorf(`after cutover answered %q, want "green"`, body)
+	}
+
+	// an unknown slot is refused and traffic stays where it is:
+	if status := cutover(`purple`); http.StatusUnprocessableEntity != status {
+		t.Errorf(`unknown slot status = %d, want 422`, status)
+	}
+	if body := fetch(); `green` != body {
+		t.Errorf(`after refused cutover answered %q, want "green"`, body)
+	}
+
+	// rollback is just another switch:
+	if status := cutover(`blue`); http.StatusOK != status {
+		t.Errorf(`rollback status = %d, want 200`, status)
+	}
+	if body := fetch(); `blue` != body {
+		t.Errorf(`after rollback answered %q, want "blue"`, body)
+	}
+
+	// cutovers under load must be atomic: every request is served
+	// completely by one slot, never by a half-switched route:
+	var wg sync.WaitGroup
+	for worker := 0; 4 > worker; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; 50 > round; round++ {
+				if body := fetch(); (`blue` != body) && (`green` != body) {
+					t.Errorf(`mid-cutover answered %q`, body)
+					return
+				}
+			}
+		}()
+	}
+	for round := 0; 10 > round; round++ {
+		_ = cutover(`green`)
+		_ = cutover(`blue`)
+	}
+	wg.Wait()
+
+	// the admin GET reports the active slot:
+	req := httptest.NewRequest(http.MethodGet,
+		`http://admin/api/slots/example.org`, nil)
+	req.Header.Set(`Authorization`, `Bearer secret`)
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `"active":"blue"`) {
+		t.Errorf(`slot listing = %q`, rec.Body.String())
+	}
+} // TestSlotCutover()
+
+/* _EoF_ */
@@ -0,0 +1,139 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// `newSocks5Dialer()` returns a dial function tunnelling TCP
+// connections through the SOCKS5 proxy `aProxy` (RFC 1928), with
+// optional username/password authentication (RFC 1929) taken from
+// the URL's user info.
+//
+// Parameters:
+//   - `aProxy`: The `socks5://[user:pass@]host:port` proxy URL.
+//
+// Returns:
+//   - The dial function to plug into a transport's `DialContext`.
+func newSocks5Dialer(aProxy *url.URL) func(context.Context, string, string) (net.Conn, error) {
+	proxyAddr := aProxy.Host
+	if "" == aProxy.Port() {
+		proxyAddr = net.JoinHostPort(aProxy.Hostname(), `1080`)
+	}
+	var username, password string
+	if nil != aProxy.User {
+		username = aProxy.User.Username()
+		password, _ = aProxy.User.Password()
+	}
+
+	return func(aCtx context.Context, aNetwork, aAddr string) (net.Conn, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(aCtx, `tcp`, proxyAddr)
+		if nil != err {
+			return nil, err
+		}
+		if err = socks5Handshake(conn, aAddr, username, password); nil != err {
+			_ = conn.Close()
+			return nil, fmt.Errorf("socks5 %s: %w", proxyAddr, err)
+		}
+
+		return conn, nil
+	}
+} // newSocks5Dialer()
+
+// `socks5Handshake()` performs the SOCKS5 greeting, optional
+// authentication, and CONNECT for `aAddr` on `aConn`.
+func socks5Handshake(aConn net.Conn, aAddr, aUser, aPass string) error {
+	host, portStr, err := net.SplitHostPort(aAddr)
+	if nil != err {
+		return err
+	}
+	port, err := strconv.Atoi(portStr)
+	if nil != err {
+		return err
+	}
+
+	// greeting - offer "no auth" and (if configured) user/pass:
+	greeting := []byte{5, 1, 0}
+	if "" != aUser {
+		greeting = []byte{5, 2, 0, 2}
+	}
+	if _, err = aConn.Write(greeting); nil != err {
+		return err
+	}
+	reply := make([]byte, 2)
+	if _, err = io.ReadFull(aConn, reply); nil != err {
+		return err
+	}
+	switch reply[1] {
+	case 0: // no auth required
+
+	case 2: // username/password sub-negotiation
+		auth := []byte{1, byte(len(aUser))}
+		auth = append(auth, aUser...)
+		auth = append(auth, byte(len(aPass)))
+		auth = append(auth, aPass...)
+		if _, err = aConn.Write(auth); nil != err {
+			return err
+		}
+		if _, err = io.ReadFull(aConn, reply); nil != err {
+			return err
+		}
+		if 0 != reply[1] {
+			return fmt.Errorf("authentication failed")
+		}
+
+	default:
+		return fmt.Errorf("no acceptable auth method")
+	}
+
+	// CONNECT request with a domain-name target:
+	request := []byte{5, 1, 0, 3, byte(len(host))}
+	request = append(request, host...)
+	request = append(request, byte(port>>8), byte(port))
+	if _, err = aConn.Write(request); nil != err {
+		return err
+	}
+	head := make([]byte, 4)
+	if _, err = io.ReadFull(aConn, head); nil != err {
+		return err
+	}
+	if 0 != head[1] {
+		return fmt.Errorf("connect failed (code %d)", head[1])
+	}
+	// consume the bound address of the reply:
+	var skip int
+	switch head[3] {
+	case 1:
+		skip = 4 + 2
+	case 3:
+		one := make([]byte, 1)
+		if _, err = io.ReadFull(aConn, one); nil != err {
+			return err
+		}
+		skip = int(one[0]) + 2
+	case 4:
+		skip = 16 + 2
+	default:
+		return fmt.Errorf("invalid address type %d", head[3])
+	}
+	if _, err = io.CopyN(io.Discard, aConn, int64(skip)); nil != err {
+		return err
+	}
+
+	return nil
+} // socks5Handshake()
+
+/* _EoF_ */
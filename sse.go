@@ -0,0 +1,125 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `TEventStream` broadcasts proxy events (backend state
+	// changes, config reloads, route edits) to any number of
+	// Server-Sent-Events subscribers, so dashboards and tooling
+	// can react in real time without polling.
+	//
+	// Slow subscribers never block a publisher: events they can't
+	// keep up with are dropped for them.
+	TEventStream struct {
+		mtx  sync.Mutex
+		subs map[chan string]struct{}
+	}
+)
+
+// `sseHeartbeat` is the pause between keep-alive comments sent to
+// idle subscribers, so intermediate proxies don't cut the stream.
+const sseHeartbeat = 30 * time.Second
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewEventStream()` creates an event broadcaster without any
+// subscribers.
+func NewEventStream() *TEventStream {
+	return &TEventStream{subs: make(map[chan string]struct{})}
+} // NewEventStream()
+
+// --------------------------------------------------------------------------
+// TEventStream methods:
+
+// `Publish()` sends one event to all current subscribers; safe to
+// call on a nil receiver (a no-op then).
+//
+// Parameters:
+//   - `aKind`: The event type (e.g. `backend-eject`, `reload`).
+//   - `aDetail`: The event's payload line.
+func (es *TEventStream) Publish(aKind, aDetail string) {
+	if nil == es {
+		return
+	}
+	// SSE data must not contain raw newlines:
+	aDetail = strings.ReplaceAll(aDetail, "\n", ` `)
+	event := `event: ` + aKind + "\ndata: " + aDetail + "\n\n"
+
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	for sub := range es.subs {
+		select {
+		case sub <- event:
+		default: // drop for subscribers that can't keep up
+		}
+	}
+} // Publish()
+
+// `ServeHTTP()` streams events to one subscriber until the client
+// disconnects.
+//
+// Parameters:
+//   - `aWriter`: Used to stream the events to the client.
+//   - `aRequest`: The incoming subscription request.
+func (es *TEventStream) ServeHTTP(aWriter http.ResponseWriter, aRequest *http.Request) {
+	flusher, ok := aWriter.(http.Flusher)
+	if !ok {
+		http.Error(aWriter, `streaming unsupported`,
+			http.StatusInternalServerError)
+		return
+	}
+	sub := make(chan string, 16)
+	es.mtx.Lock()
+	es.subs[sub] = struct{}{}
+	es.mtx.Unlock()
+	defer func() {
+		es.mtx.Lock()
+		delete(es.subs, sub)
+		es.mtx.Unlock()
+	}()
+
+	header := aWriter.Header()
+	header.Set(`Content-Type`, `text/event-stream`)
+	header.Set(`Cache-Control`, `no-cache`)
+	header.Set(`Connection`, `keep-alive`)
+	_, _ = io.WriteString(aWriter, ": connected\n\n")
+	flusher.Flush()
+
+	ticker := time.NewTicker(sseHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-aRequest.Context().Done():
+			return
+
+		case event := <-sub:
+			if _, err := io.WriteString(aWriter, event); nil != err {
+				return
+			}
+			flusher.Flush()
+
+		case <-ticker.C:
+			if _, err := io.WriteString(aWriter, ": ping\n\n"); nil != err {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+} // ServeHTTP()
+
+/* _EoF_ */
@@ -0,0 +1,70 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEventStream(t *testing.T) {
+	es := NewEventStream()
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if nil != err {
+		t.Fatalf(`subscribing failed: %v`, err)
+	}
+	defer resp.Body.Close()
+	if `text/event-stream` != resp.Header.Get(`Content-Type`) {
+		t.Fatalf(`Content-Type = %q`, resp.Header.Get(`Content-Type`))
+	}
+
+	// wait until the subscription is registered, then publish:
+	for idx := 0; 100 > idx; idx++ {
+		es.mtx.Lock()
+		subscribed := 0 < len(es.subs)
+		es.mtx.Unlock()
+		if subscribed {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	es.Publish(`reload`, "3 hosts\napplied")
+
+	var gotEvent, gotData bool
+	reader := bufio.NewReader(resp.Body)
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) && !(gotEvent && gotData) {
+		line, err := reader.ReadString('\n')
+		if nil != err {
+			break
+		}
+		switch line {
+		case "event: reload\n":
+			gotEvent = true
+		case "data: 3 hosts applied\n":
+			gotData = true
+		}
+	}
+	if !gotEvent || !gotData {
+		t.Errorf(`event = %v, data = %v, want both received`,
+			gotEvent, gotData)
+	}
+
+	// publishing on a nil stream must be a safe no-op:
+	var nilStream *TEventStream
+	nilStream.Publish(`ignored`, `ignored`)
+} // TestEventStream()
+
+/* _EoF_ */
@@ -0,0 +1,210 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// `tEtagInfo` is the cached strong ETag of one file version.
+	tEtagInfo struct {
+		modTime time.Time
+		size    int64
+		etag    string
+	}
+
+	// `tStaticServer` serves a virtual host directly from a local
+	// directory instead of proxying, with strong content-hash ETags
+	// so conditional requests and caches work reliably.
+	//
+	// Conditional (`If-None-Match`/`If-Modified-Since`) and Range
+	// requests are handled by `http.ServeContent`.  Pre-compressed
+	// `.br`/`.gz` sibling files are served automatically to clients
+	// accepting the respective encoding.
+	tStaticServer struct {
+		mtx   sync.Mutex
+		root  string
+		etags map[string]tEtagInfo // file path -> hash
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newStaticServer()` creates a file server rooted at `aRoot`.
+func newStaticServer(aRoot string) *tStaticServer {
+	return &tStaticServer{
+		root:  filepath.Clean(aRoot),
+		etags: make(map[string]tEtagInfo),
+	}
+} // newStaticServer()
+
+// --------------------------------------------------------------------------
+// tStaticServer methods:
+
+// `ServeHTTP()` answers `aRequest` from the server's directory.
+func (ss *tStaticServer) ServeHTTP(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if (http.MethodGet != aRequest.Method) &&
+		(http.MethodHead != aRequest.Method) {
+		aWriter.Header().Set(`Allow`, `GET, HEAD`)
+		http.Error(aWriter, `method not allowed`,
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	// resolve and confine the path to the configured root:
+	name := path.Clean(`/` + aRequest.URL.Path)
+	if strings.HasSuffix(aRequest.URL.Path, `/`) {
+		name = path.Join(name, `index.html`)
+	}
+	filename := filepath.Join(ss.root, filepath.FromSlash(name))
+
+	file, encoding, hasVariant := openVariant(filename,
+		aRequest.Header.Get(`Accept-Encoding`))
+	if nil == file {
+		http.Error(aWriter, `not found`, http.StatusNotFound)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if (nil != err) || info.IsDir() {
+		http.Error(aWriter, `not found`, http.StatusNotFound)
+		return
+	}
+
+	if hasVariant {
+		// the served representation depends on the request:
+		aWriter.Header().Add(`Vary`, `Accept-Encoding`)
+	}
+	if "" != encoding {
+		aWriter.Header().Set(`Content-Encoding`, encoding)
+		// the type of the original file, not of the compressed
+		// sibling `ServeContent` would otherwise sniff:
+		if ctype := mime.TypeByExtension(filepath.Ext(filename)); "" != ctype {
+			aWriter.Header().Set(`Content-Type`, ctype)
+		}
+	}
+	if etag, err := ss.etag(file.Name(), file, info); nil == err {
+		aWriter.Header().Set(`ETag`, etag)
+	}
+	http.ServeContent(aWriter, aRequest, filepath.Base(filename),
+		info.ModTime(), file)
+} // ServeHTTP()
+
+// `etag()` returns the strong (content hash) ETag of `aFile`,
+// re-using the cached hash while the file is unchanged; the file's
+// read position is restored afterwards.
+func (ss *tStaticServer) etag(aName string, aFile *os.File, aInfo os.FileInfo) (string, error) {
+	ss.mtx.Lock()
+	cached, ok := ss.etags[aName]
+	ss.mtx.Unlock()
+	if ok && cached.modTime.Equal(aInfo.ModTime()) &&
+		(cached.size == aInfo.Size()) {
+		return cached.etag, nil
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, aFile); nil != err {
+		return ``, err
+	}
+	if _, err := aFile.Seek(0, io.SeekStart); nil != err {
+		return ``, err
+	}
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)) + `"`
+
+	ss.mtx.Lock()
+	ss.etags[aName] = tEtagInfo{
+		modTime: aInfo.ModTime(),
+		size:    aInfo.Size(),
+		etag:    etag,
+	}
+	ss.mtx.Unlock()
+
+	return etag, nil
+} // etag()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `staticEncodings` lists the pre-compressed sibling variants in
+// order of preference.
+var staticEncodings = []struct {
+	ext, name string
+}{
+	{`.br`, `br`},
+	{`.gz`, `gzip`},
+}
+
+// `openVariant()` opens the best available representation of
+// `aFilename` for a client sending the `Accept-Encoding` value
+// `aAccept`: a pre-compressed `.br`/`.gz` sibling file if the client
+// accepts the respective encoding, the plain file otherwise.
+//
+// It returns the opened file (nil if none exists), the applied
+// content encoding (empty for the plain file), and whether any
+// sibling variant exists at all (for the `Vary` header).
+func openVariant(aFilename, aAccept string) (*os.File, string, bool) {
+	hasVariant := false
+	for _, enc := range staticEncodings {
+		sibling := aFilename + enc.ext
+		info, err := os.Stat(sibling)
+		if (nil != err) || info.IsDir() {
+			continue
+		}
+		hasVariant = true
+		if !acceptsEncoding(aAccept, enc.name) {
+			continue
+		}
+		if file, err := os.Open(sibling); nil == err {
+			return file, enc.name, true
+		}
+	}
+
+	file, err := os.Open(aFilename)
+	if nil != err {
+		return nil, ``, hasVariant
+	}
+
+	return file, ``, hasVariant
+} // openVariant()
+
+// `acceptsEncoding()` reports whether the `Accept-Encoding` value
+// `aAccept` allows `aEncoding` (i.e. lists it with a non-zero
+// quality value).
+func acceptsEncoding(aAccept, aEncoding string) bool {
+	for _, part := range strings.Split(aAccept, `,`) {
+		token, params, _ := strings.Cut(strings.TrimSpace(part), `;`)
+		if !strings.EqualFold(strings.TrimSpace(token), aEncoding) {
+			continue
+		}
+		if value, ok := strings.CutPrefix(
+			strings.TrimSpace(params), `q=`); ok {
+			if q, err := strconv.ParseFloat(value, 64); (nil == err) && (0 == q) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+} // acceptsEncoding()
+
+/* _EoF_ */
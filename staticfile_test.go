@@ -0,0 +1,105 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStaticServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, `page.txt`),
+		[]byte(`static content`), 0644); nil != err {
+		t.Fatal(err)
+	}
+
+	ph, _ := NewProxyHandler()
+	if err := ph.AddHost(`example.org`, &THostConfig{
+		StaticDir: dir,
+	}); nil != err {
+		t.Fatalf(`AddHost() error = %v`, err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet,
+		`http://example.org/page.txt`, nil)
+	rec := httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusOK != rec.Code {
+		t.Fatalf(`status = %d, want 200`, rec.Code)
+	}
+	if `static content` != rec.Body.String() {
+		t.Errorf(`body = %q`, rec.Body.String())
+	}
+	etag := rec.Header().Get(`ETag`)
+	if "" == etag {
+		t.Fatal(`static response lacks an ETag`)
+	}
+
+	req = httptest.NewRequest(http.MethodGet,
+		`http://example.org/page.txt`, nil)
+	req.Header.Set(`If-None-Match`, etag)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusNotModified != rec.Code {
+		t.Errorf(`conditional status = %d, want 304`, rec.Code)
+	}
+
+	// a pre-compressed sibling is served to accepting clients:
+	if err := os.WriteFile(filepath.Join(dir, `page.txt.gz`),
+		[]byte(`gzipped bytes`), 0644); nil != err {
+		t.Fatal(err)
+	}
+	req = httptest.NewRequest(http.MethodGet,
+		`http://example.org/page.txt`, nil)
+	req.Header.Set(`Accept-Encoding`, `gzip, deflate`)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if `gzip` != rec.Header().Get(`Content-Encoding`) {
+		t.Errorf(`Content-Encoding = %q, want "gzip"`,
+			rec.Header().Get(`Content-Encoding`))
+	}
+	if `Accept-Encoding` != rec.Header().Get(`Vary`) {
+		t.Errorf(`Vary = %q, want "Accept-Encoding"`,
+			rec.Header().Get(`Vary`))
+	}
+	if `gzipped bytes` != rec.Body.String() {
+		t.Errorf(`compressed body = %q`, rec.Body.String())
+	}
+
+	// without client support the plain file is used, still varying:
+	req = httptest.NewRequest(http.MethodGet,
+		`http://example.org/page.txt`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if "" != rec.Header().Get(`Content-Encoding`) {
+		t.Errorf(`unexpected Content-Encoding %q`,
+			rec.Header().Get(`Content-Encoding`))
+	}
+	if `static content` != rec.Body.String() {
+		t.Errorf(`plain body = %q`, rec.Body.String())
+	}
+	if `Accept-Encoding` != rec.Header().Get(`Vary`) {
+		t.Error(`plain variant response lacks Vary`)
+	}
+
+	// path traversal is rejected before it reaches the file server:
+	req = httptest.NewRequest(http.MethodGet,
+		`http://example.org/../../etc/passwd`, nil)
+	rec = httptest.NewRecorder()
+	ph.ServeHTTP(rec, req)
+	if http.StatusBadRequest != rec.Code {
+		t.Errorf(`traversal status = %d, want 400`, rec.Code)
+	}
+} // TestStaticServer()
+
+/* _EoF_ */
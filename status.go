@@ -0,0 +1,127 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// `statusPage` is the HTML template of the live status page.
+var statusPage = template.Must(template.New(`status`).Parse(`<!DOCTYPE html>
+<html lang="en"><head><meta charset="utf-8">
+<meta http-equiv="refresh" content="10">
+<title>reprox status</title>
+<style>
+body { font-family: sans-serif; margin: 1em 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #999; padding: .25em .75em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+caption { font-weight: bold; margin-bottom: .5em; text-align: left; }
+</style></head><body>
+<h1>reprox status</h1>
+<p>Uptime: {{.Uptime}} &mdash; maintenance mode: {{.Maintenance}}</p>
+<table><caption>Virtual hosts</caption>
+<tr><th>Host</th><th>Backend</th><th>Requests</th><th>Req/min</th>
+<th>Active</th><th>5xx</th><th>Bytes in</th><th>Bytes out</th></tr>
+{{range .Hosts}}<tr><td>{{.Name}}</td><td>{{.Target}}</td>
+<td>{{.Requests}}</td><td>{{.LastMinute}}</td>
+<td>{{.Active}}</td><td>{{.Errors}}</td>
+<td>{{.BytesIn}}</td><td>{{.BytesOut}}</td></tr>
+{{end}}</table>
+{{if .SLO}}<table><caption>SLO compliance</caption>
+<tr><th>Host</th><th>Avail. 1h</th><th>Avail. 24h</th>
+<th>Fast 1h</th><th>Fast 24h</th><th>Budget left</th></tr>
+{{range .SLO}}<tr><td>{{.Name}}</td>
+<td>{{printf "%.2f%%" .Hour.Availability}}</td>
+<td>{{printf "%.2f%%" .Day.Availability}}</td>
+<td>{{printf "%.1f%%" .Hour.FastPercent}}</td>
+<td>{{printf "%.1f%%" .Day.FastPercent}}</td>
+<td>{{printf "%.0f%%" .Day.BudgetLeft}}</td></tr>
+{{end}}</table>
+{{end}}<table><caption>Recent errors</caption>
+<tr><th>Time</th><th>Host</th><th>Message</th></tr>
+{{range .Recent}}<tr><td>{{.When.Format "15:04:05"}}</td>
+<td>{{.Host}}</td><td>{{.Message}}</td></tr>
+{{end}}</table>
+</body></html>
+`))
+
+type (
+	// `tStatusHost` is one virtual host row of the status page.
+	tStatusHost struct {
+		Name, Target string
+		tHostMetrics
+	}
+
+	// `tStatusSLO` is one virtual host row of the SLO table.
+	tStatusSLO struct {
+		Name string
+		TSLOStatus
+	}
+
+	// `tStatusData` is the data rendered into the status page.
+	tStatusData struct {
+		Uptime      time.Duration
+		Maintenance bool
+		Hosts       []tStatusHost
+		SLO         []tStatusSLO
+		Recent      []tRecentError
+	}
+)
+
+// --------------------------------------------------------------------------
+// TAdmin methods:
+
+// `status()` handles `GET /status` rendering the human-readable
+// live status page.
+func (ad *TAdmin) status(aWriter http.ResponseWriter, aRequest *http.Request) {
+	if http.MethodGet != aRequest.Method {
+		methodNotAllowed(aWriter, http.MethodGet)
+		return
+	}
+
+	metrics := ad.handler.Metrics()
+	counters, recent := metrics.snapshot()
+	routes := ad.handler.Routes()
+
+	data := &tStatusData{
+		Uptime:      metrics.Uptime(),
+		Maintenance: ad.handler.InMaintenance(),
+		Recent:      recent,
+	}
+	for hostname, target := range routes {
+		data.Hosts = append(data.Hosts, tStatusHost{
+			Name:         hostname,
+			Target:       target,
+			tHostMetrics: counters[hostname],
+		})
+	}
+	sort.Slice(data.Hosts, func(i, j int) bool {
+		return data.Hosts[i].Name < data.Hosts[j].Name
+	})
+	if tracker := ad.handler.SLO(); nil != tracker {
+		for hostname, status := range tracker.Report() {
+			data.SLO = append(data.SLO, tStatusSLO{
+				Name:       hostname,
+				TSLOStatus: status,
+			})
+		}
+		sort.Slice(data.SLO, func(i, j int) bool {
+			return data.SLO[i].Name < data.SLO[j].Name
+		})
+	}
+
+	aWriter.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+	_ = statusPage.Execute(aWriter, data)
+} // status()
+
+/* _EoF_ */
@@ -0,0 +1,163 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+type (
+	// `TTCPProxyConfig` describes one generic TCP (L4) forwarding,
+	// fronting non-HTTP services like SMTP or IMAP.
+	TTCPProxyConfig struct {
+		// Listen is the address to accept connections on.
+		Listen string `json:"listen"`
+
+		// Target is the backend address connections are relayed to.
+		Target string `json:"target"`
+
+		// TimeoutSec is the backend dial timeout (default 10).
+		TimeoutSec int `json:"timeoutSec,omitempty"`
+	}
+
+	// `TTCPProxy` relays raw TCP connections to a backend address,
+	// sharing the traffic accounting with the HTTP path.
+	TTCPProxy struct {
+		conf     *TTCPProxyConfig
+		logger   *TLogger
+		metrics  *TMetrics
+		listener net.Listener
+		timeout  time.Duration
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewTCPProxy()` creates a TCP relay according to `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The listen and target addresses.
+//   - `aMetrics`: The shared metrics collector (may be nil).
+//   - `aLogger`: Used to report connection problems.
+//
+// Returns:
+//   - `*TTCPProxy`: The new (not yet listening) relay.
+func NewTCPProxy(aConfig *TTCPProxyConfig, aMetrics *TMetrics, aLogger *TLogger) *TTCPProxy {
+	timeout := time.Duration(aConfig.TimeoutSec) * time.Second
+	if 0 >= timeout {
+		timeout = 10 * time.Second
+	}
+	if nil == aMetrics {
+		aMetrics = newMetrics()
+	}
+
+	return &TTCPProxy{
+		conf:    aConfig,
+		logger:  aLogger,
+		metrics: aMetrics,
+		timeout: timeout,
+	}
+} // NewTCPProxy()
+
+// --------------------------------------------------------------------------
+// TTCPProxy methods:
+
+// `Close()` stops accepting new connections; established relays
+// keep running until either side closes.
+func (tp *TTCPProxy) Close() error {
+	if nil != tp.listener {
+		return tp.listener.Close()
+	}
+
+	return nil
+} // Close()
+
+// `ListenAndServe()` accepts and relays connections until the
+// listener is closed.
+//
+// Returns:
+//   - `error`: The listener's fatal error (`nil` after `Close()`).
+func (tp *TTCPProxy) ListenAndServe() error {
+	listener, err := net.Listen(`tcp`, tp.conf.Listen)
+	if nil != err {
+		return err
+	}
+	tp.listener = listener
+
+	for {
+		conn, err := listener.Accept()
+		if nil != err {
+			if isClosedError(err) {
+				return nil
+			}
+			return err
+		}
+		go tp.serveConn(conn)
+	}
+} // ListenAndServe()
+
+// `serveConn()` relays one accepted connection to the backend,
+// accounting the transferred bytes under the listen address.
+func (tp *TTCPProxy) serveConn(aConn net.Conn) {
+	name := `tcp` + tp.conf.Listen
+	defer aConn.Close()
+
+	tp.metrics.requestStart(name)
+	defer tp.metrics.requestDone(name)
+
+	backend, err := net.DialTimeout(`tcp`, tp.conf.Target, tp.timeout)
+	if nil != err {
+		tp.logger.Error(name, `backend dial: `+err.Error())
+		tp.metrics.countError(name, err.Error())
+		return
+	}
+	defer backend.Close()
+
+	var in, out int64
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		out, _ = io.Copy(aConn, backend)
+		// unblock the other direction's read:
+		type closeReader interface{ CloseRead() error }
+		if cr, ok := aConn.(closeReader); ok {
+			_ = cr.CloseRead()
+		} else {
+			_ = aConn.SetReadDeadline(time.Now())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		in, _ = io.Copy(backend, aConn)
+		type closeWriter interface{ CloseWrite() error }
+		if cw, ok := backend.(closeWriter); ok {
+			_ = cw.CloseWrite()
+		}
+	}()
+	wg.Wait()
+
+	tp.metrics.countTransfer(name, in, out)
+} // serveConn()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `isClosedError()` reports whether `aErr` stems from using a
+// deliberately closed listener.
+func isClosedError(aErr error) bool {
+	return errors.Is(aErr, net.ErrClosed)
+} // isClosedError()
+
+/* _EoF_ */
@@ -0,0 +1,96 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// `tTokenBucket` is a simple token bucket limiting the egress
+	// bandwidth of one virtual host; all of the host's concurrent
+	// responses share the same bucket.
+	tTokenBucket struct {
+		mtx    sync.Mutex
+		rate   int64 // tokens (bytes) added per second
+		tokens int64 // currently available tokens
+		last   time.Time
+	}
+
+	// `tThrottleWriter` wraps a `ResponseWriter` delaying writes
+	// until the host's token bucket permits them.
+	tThrottleWriter struct {
+		http.ResponseWriter
+		bucket *tTokenBucket
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newTokenBucket()` creates a bucket refilling at `aBytesPerSec`,
+// with one second's worth of burst capacity.
+func newTokenBucket(aBytesPerSec int64) *tTokenBucket {
+	return &tTokenBucket{
+		rate:   aBytesPerSec,
+		tokens: aBytesPerSec,
+		last:   time.Now(),
+	}
+} // newTokenBucket()
+
+// --------------------------------------------------------------------------
+// tTokenBucket methods:
+
+// `take()` blocks until `aCount` tokens are available, then consumes
+// them; counts larger than the burst size are allowed through once
+// the bucket is full.
+func (tb *tTokenBucket) take(aCount int64) {
+	for {
+		tb.mtx.Lock()
+		now := time.Now()
+		tb.tokens += int64(now.Sub(tb.last).Seconds() * float64(tb.rate))
+		if tb.tokens > tb.rate {
+			tb.tokens = tb.rate
+		}
+		tb.last = now
+		if (tb.tokens >= aCount) || (tb.tokens >= tb.rate) {
+			tb.tokens -= aCount
+			tb.mtx.Unlock()
+			return
+		}
+		missing := aCount - tb.tokens
+		if missing > tb.rate {
+			missing = tb.rate
+		}
+		tb.mtx.Unlock()
+
+		wait := time.Duration(float64(missing) / float64(tb.rate) *
+			float64(time.Second))
+		time.Sleep(wait)
+	}
+} // take()
+
+// --------------------------------------------------------------------------
+// tThrottleWriter methods:
+
+// `Unwrap()` exposes the wrapped writer to `http.ResponseController`.
+func (tw *tThrottleWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+} // Unwrap()
+
+// `Write()` sends `aData` to the client at the bucket's pace.
+func (tw *tThrottleWriter) Write(aData []byte) (int, error) {
+	tw.bucket.take(int64(len(aData)))
+
+	return tw.ResponseWriter.Write(aData)
+} // Write()
+
+/* _EoF_ */
@@ -0,0 +1,157 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/binary"
+	"os"
+	"strings"
+	"time"
+)
+
+type (
+	// `TTicketKeyConfig` tunes the TLS session ticket key rotation.
+	TTicketKeyConfig struct {
+		// RotateHours is the lifetime of one ticket key before the
+		// next one takes over (default 24).
+		RotateHours int `json:"rotateHours,omitempty"`
+
+		// KeyFile optionally holds a shared master secret; several
+		// proxy instances given the same file derive identical
+		// ticket keys, so resumed sessions work across all of them.
+		KeyFile string `json:"keyFile,omitempty"`
+	}
+
+	// `TTicketRotator` periodically replaces the TLS session ticket
+	// keys of a server's TLS configuration, keeping the two previous
+	// keys valid as an overlap window; without rotation a single
+	// long-lived key would undermine forward secrecy for all
+	// resumed sessions.
+	TTicketRotator struct {
+		conf     *tls.Config
+		secret   []byte // shared master secret (nil: random keys)
+		interval time.Duration
+		keys     [][32]byte // newest first, used without a secret
+		done     chan struct{}
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewTicketRotator()` creates a rotator managing the session
+// ticket keys of `aTLSConf` according to `aConfig` (which may be
+// nil to use the defaults).
+//
+// Parameters:
+//   - `aConfig`: The rotation settings (or nil).
+//   - `aTLSConf`: The TLS configuration to keep rotated.
+//
+// Returns:
+//   - `*TTicketRotator`: The new (not yet started) rotator.
+//   - `error`: An error reading the configured shared key file.
+func NewTicketRotator(aConfig *TTicketKeyConfig, aTLSConf *tls.Config) (*TTicketRotator, error) {
+	if nil == aConfig {
+		aConfig = &TTicketKeyConfig{}
+	}
+	interval := time.Duration(aConfig.RotateHours) * time.Hour
+	if 0 >= interval {
+		interval = 24 * time.Hour
+	}
+
+	rotator := &TTicketRotator{
+		conf:     aTLSConf,
+		interval: interval,
+		done:     make(chan struct{}),
+	}
+	if "" != aConfig.KeyFile {
+		secret, err := os.ReadFile(aConfig.KeyFile)
+		if nil != err {
+			return nil, err
+		}
+		rotator.secret = []byte(strings.TrimSpace(string(secret)))
+	}
+
+	return rotator, nil
+} // NewTicketRotator()
+
+// --------------------------------------------------------------------------
+// TTicketRotator methods:
+
+// `Start()` installs the initial keys and keeps rotating them until
+// `Stop()` is called.
+func (tr *TTicketRotator) Start() {
+	tr.rotate()
+	go func() {
+		// check well below the rotation interval so instances
+		// sharing a secret switch epochs nearly simultaneously:
+		ticker := time.NewTicker(tr.interval / 8)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				tr.rotate()
+			case <-tr.done:
+				return
+			}
+		}
+	}()
+} // Start()
+
+// `Stop()` terminates the key rotation.
+func (tr *TTicketRotator) Stop() {
+	close(tr.done)
+} // Stop()
+
+// `rotate()` installs the current key set: derived from the shared
+// secret and the time epoch, or freshly random without one.
+func (tr *TTicketRotator) rotate() {
+	if nil != tr.secret {
+		// all instances sharing the secret derive the same keys
+		// for the same epochs - no coordination needed:
+		epoch := uint64(time.Now().Unix()) / uint64(tr.interval/time.Second)
+		keys := [][32]byte{
+			tr.deriveKey(epoch),
+			tr.deriveKey(epoch - 1),
+			tr.deriveKey(epoch - 2),
+		}
+		tr.conf.SetSessionTicketKeys(keys)
+		return
+	}
+
+	// local mode: prepend a fresh random key, keep two old ones
+	var fresh [32]byte
+	if _, err := rand.Read(fresh[:]); nil != err {
+		return // keep the current keys rather than using weak ones
+	}
+	tr.keys = append([][32]byte{fresh}, tr.keys...)
+	if 3 < len(tr.keys) {
+		tr.keys = tr.keys[:3]
+	}
+	tr.conf.SetSessionTicketKeys(tr.keys)
+} // rotate()
+
+// `deriveKey()` computes the ticket key of `aEpoch` from the shared
+// master secret.
+func (tr *TTicketRotator) deriveKey(aEpoch uint64) (rKey [32]byte) {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], aEpoch)
+	mac := hmac.New(sha256.New, tr.secret)
+	mac.Write([]byte(`reprox ticket key `))
+	mac.Write(counter[:])
+	copy(rKey[:], mac.Sum(nil))
+
+	return
+} // deriveKey()
+
+/* _EoF_ */
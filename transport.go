@@ -0,0 +1,129 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type (
+	// `TTransportConfig` tunes the HTTP client used for all
+	// upstream connections.
+	//
+	// `1xx` informational responses (100 Continue, 103 Early
+	// Hints) and HTTP trailers are forwarded by the underlying
+	// `httputil.ReverseProxy`; the settings here control the
+	// timing of the `Expect: 100-continue` handshake and the
+	// connection pooling towards the backends.
+	TTransportConfig struct {
+		// ExpectContinueTimeoutMs is how long to wait for a
+		// backend's `100 Continue` before sending the request
+		// body anyway (default 1000).
+		ExpectContinueTimeoutMs int `json:"expectContinueTimeoutMs"`
+
+		// ResponseHeaderTimeoutSec limits the wait for a
+		// backend's response headers; zero means no limit.
+		ResponseHeaderTimeoutSec int `json:"responseHeaderTimeoutSec"`
+
+		// IdleConnTimeoutSec closes idle backend connections
+		// after this many seconds (default 90).
+		IdleConnTimeoutSec int `json:"idleConnTimeoutSec"`
+
+		// MaxIdleConnsPerHost is the keep-alive pool size per
+		// backend (default 8).
+		MaxIdleConnsPerHost int `json:"maxIdleConnsPerHost"`
+	}
+)
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `newUpstreamTransport()` builds the tuned backend transport
+// described by `aConfig`.
+func newUpstreamTransport(aConfig *TTransportConfig) *http.Transport {
+	expectContinue := time.Duration(aConfig.ExpectContinueTimeoutMs) *
+		time.Millisecond
+	if 0 >= expectContinue {
+		expectContinue = time.Second
+	}
+	idleTimeout := time.Duration(aConfig.IdleConnTimeoutSec) * time.Second
+	if 0 >= idleTimeout {
+		idleTimeout = 90 * time.Second
+	}
+	maxIdle := aConfig.MaxIdleConnsPerHost
+	if 0 >= maxIdle {
+		maxIdle = 8
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ExpectContinueTimeout = expectContinue
+	transport.IdleConnTimeout = idleTimeout
+	transport.MaxIdleConnsPerHost = maxIdle
+	transport.ResponseHeaderTimeout =
+		time.Duration(aConfig.ResponseHeaderTimeoutSec) * time.Second
+
+	return transport
+} // newUpstreamTransport()
+
+// --------------------------------------------------------------------------
+// helper functions:
+
+// `http2Transport()` clones `aBase` (or the default transport) as
+// an HTTP/2-only transport: the TLS handshake offers just `h2` via
+// ALPN, so a backend without HTTP/2 support fails the connection
+// instead of silently downgrading to HTTP/1.1.
+func http2Transport(aBase http.RoundTripper) *http.Transport {
+	base, ok := aBase.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	transport.ForceAttemptHTTP2 = true
+	if nil == transport.TLSClientConfig {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.NextProtos = []string{`h2`}
+
+	return transport
+} // http2Transport()
+
+// `outboundProxyTransport()` clones `aBase` (or the default
+// transport) and routes its connections through the outbound proxy
+// `aProxyURL` - an HTTP(S) CONNECT proxy or a SOCKS5 server.
+func outboundProxyTransport(aBase http.RoundTripper, aProxyURL string) (*http.Transport, error) {
+	proxyURL, err := url.Parse(aProxyURL)
+	if nil != err {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", aProxyURL, err)
+	}
+
+	base, ok := aBase.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
+	switch proxyURL.Scheme {
+	case `http`, `https`:
+		transport.Proxy = http.ProxyURL(proxyURL)
+
+	case `socks5`:
+		transport.Proxy = nil
+		transport.DialContext = newSocks5Dialer(proxyURL)
+
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+
+	return transport, nil
+} // outboundProxyTransport()
+
+/* _EoF_ */
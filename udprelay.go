@@ -0,0 +1,216 @@
+/*
+Copyright © 2026  M.Watermann, 10247 Berlin, Germany
+
+		All rights reserved
+	EMail : <support@mwat.de>
+*/
+package reprox
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+type (
+	// `TUDPRelayConfig` describes one UDP port forwarding, e.g. for
+	// DNS or syslog services behind the proxy host.
+	TUDPRelayConfig struct {
+		// Listen is the address to receive datagrams on.
+		Listen string `json:"listen"`
+
+		// Target is the backend address datagrams are relayed to.
+		Target string `json:"target"`
+
+		// IdleSec drops a client's relay session after this many
+		// seconds without traffic (default 60).
+		IdleSec int `json:"idleSec,omitempty"`
+	}
+
+	// `tUDPSession` is the relay state of one client address.
+	tUDPSession struct {
+		backend *net.UDPConn
+		last    time.Time // last activity, guarded by the relay's mtx
+	}
+
+	// `TUDPRelay` forwards datagrams between clients and a backend,
+	// tracking sessions by the clients' source addresses so answers
+	// find their way back.
+	TUDPRelay struct {
+		mtx      sync.Mutex
+		conf     *TUDPRelayConfig
+		logger   *TLogger
+		metrics  *TMetrics
+		conn     *net.UDPConn
+		sessions map[string]*tUDPSession
+		idle     time.Duration
+		done     chan struct{}
+	}
+)
+
+// `udpBufferSize` is the size of the datagram receive buffers.
+const udpBufferSize = 64 << 10
+
+// --------------------------------------------------------------------------
+// constructor function:
+
+// `NewUDPRelay()` creates a UDP relay according to `aConfig`.
+//
+// Parameters:
+//   - `aConfig`: The listen and target addresses.
+//   - `aMetrics`: The shared metrics collector (may be nil).
+//   - `aLogger`: Used to report relay problems.
+//
+// Returns:
+//   - `*TUDPRelay`: The new (not yet listening) relay.
+func NewUDPRelay(aConfig *TUDPRelayConfig, aMetrics *TMetrics, aLogger *TLogger) *TUDPRelay {
+	idle := time.Duration(aConfig.IdleSec) * time.Second
+	if 0 >= idle {
+		idle = time.Minute
+	}
+	if nil == aMetrics {
+		aMetrics = newMetrics()
+	}
+
+	return &TUDPRelay{
+		conf:     aConfig,
+		logger:   aLogger,
+		metrics:  aMetrics,
+		sessions: make(map[string]*tUDPSession),
+		idle:     idle,
+		done:     make(chan struct{}),
+	}
+} // NewUDPRelay()
+
+// --------------------------------------------------------------------------
+// TUDPRelay methods:
+
+// `Close()` stops the relay, dropping all tracked sessions.
+func (ur *TUDPRelay) Close() error {
+	close(ur.done)
+
+	ur.mtx.Lock()
+	for client, session := range ur.sessions {
+		_ = session.backend.Close()
+		delete(ur.sessions, client)
+	}
+	ur.mtx.Unlock()
+	if nil != ur.conn {
+		return ur.conn.Close()
+	}
+
+	return nil
+} // Close()
+
+// `ListenAndServe()` receives and relays datagrams until the relay
+// is closed.
+//
+// Returns:
+//   - `error`: The socket's fatal error (`nil` after `Close()`).
+func (ur *TUDPRelay) ListenAndServe() error {
+	listenAddr, err := net.ResolveUDPAddr(`udp`, ur.conf.Listen)
+	if nil != err {
+		return err
+	}
+	conn, err := net.ListenUDP(`udp`, listenAddr)
+	if nil != err {
+		return err
+	}
+	ur.conn = conn
+	go ur.reaper()
+
+	name := `udp` + ur.conf.Listen
+	buffer := make([]byte, udpBufferSize)
+	for {
+		read, client, err := conn.ReadFromUDP(buffer)
+		if nil != err {
+			if isClosedError(err) {
+				return nil
+			}
+			return err
+		}
+		session, err := ur.session(client)
+		if nil != err {
+			ur.logger.Error(name, `backend dial: `+err.Error())
+			ur.metrics.countError(name, err.Error())
+			continue
+		}
+		if _, err = session.backend.Write(buffer[:read]); nil != err {
+			ur.logger.Error(name, `backend write: `+err.Error())
+		}
+		ur.metrics.countTransfer(name, int64(read), 0)
+	}
+} // ListenAndServe()
+
+// `reaper()` periodically drops sessions idle for longer than the
+// configured timeout.
+func (ur *TUDPRelay) reaper() {
+	ticker := time.NewTicker(ur.idle / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			limit := time.Now().Add(-ur.idle)
+			ur.mtx.Lock()
+			for client, session := range ur.sessions {
+				if session.last.Before(limit) {
+					_ = session.backend.Close()
+					delete(ur.sessions, client)
+				}
+			}
+			ur.mtx.Unlock()
+
+		case <-ur.done:
+			return
+		}
+	}
+} // reaper()
+
+// `session()` returns the relay session of `aClient`, establishing
+// a new backend connection (and its answer pump) on first contact.
+func (ur *TUDPRelay) session(aClient *net.UDPAddr) (*tUDPSession, error) {
+	key := aClient.String()
+
+	ur.mtx.Lock()
+	defer ur.mtx.Unlock()
+	if session, ok := ur.sessions[key]; ok {
+		session.last = time.Now()
+		return session, nil
+	}
+
+	targetAddr, err := net.ResolveUDPAddr(`udp`, ur.conf.Target)
+	if nil != err {
+		return nil, err
+	}
+	backend, err := net.DialUDP(`udp`, nil, targetAddr)
+	if nil != err {
+		return nil, err
+	}
+	session := &tUDPSession{backend: backend, last: time.Now()}
+	ur.sessions[key] = session
+
+	// pump the backend's answers back to the client:
+	go func() {
+		name := `udp` + ur.conf.Listen
+		buffer := make([]byte, udpBufferSize)
+		for {
+			read, err := backend.Read(buffer)
+			if nil != err {
+				return // session closed or backend gone
+			}
+			if _, err = ur.conn.WriteToUDP(buffer[:read], aClient); nil != err {
+				return
+			}
+			ur.metrics.countTransfer(name, 0, int64(read))
+			ur.mtx.Lock()
+			session.last = time.Now()
+			ur.mtx.Unlock()
+		}
+	}()
+
+	return session, nil
+} // session()
+
+/* _EoF_ */